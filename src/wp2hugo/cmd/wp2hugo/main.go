@@ -2,26 +2,36 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"runtime"
 	"slices"
+	"sort"
 	"strings"
 
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/logger"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/mediacache"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/migrationreport"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/outputtarget"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
 	"github.com/rs/zerolog/log"
 )
 
 var (
-	sourceFile                     = flag.String("source", "", "file path to the source WordPress XML file")
+	sourceFile                     = flag.String("source", "", "file path to the source WordPress XML file, or a directory or glob pattern (quoted) matching several export files of the same website")
 	outputDir                      = flag.String("output", "/tmp", "dir path to write the Hugo-generated data to")
 	downloadMedia                  = flag.Bool("download-media", false, "download media files embedded in the WordPress content")
 	downloadAll                    = flag.Bool("download-all", false, "download all media from WordPress library, whether used in content or not")
 	continueOnMediaDownloadFailure = flag.Bool("continue-on-media-download-error", false, "continue processing even if one or more media downloads fail")
+	downloadWorkers                = flag.Int("download-workers", 4, "number of media files downloaded concurrently")
+	downloadsPerSecond             = flag.Float64("downloads-per-second", 1, "maximum number of media download requests sent to the same host per second")
 	generateNgnixConfig            = flag.Bool("generate-nginx-config", true, "generate Nginx configuration for the generated Hugo website for redirecting WordPress GUIDs to Hugo URLs")
 	authors                        = flag.String("authors", "", "CSV list of author name(s), if provided, only posts by these authors will be processed")
 	// This is useful for repeated executions of the tool to avoid downloading the media files again
@@ -31,8 +41,30 @@ var (
 	font           = flag.String("font", "Lexend", "custom font for the output website")
 	colorLogOutput = flag.Bool("color-log-output", true, "enable colored log output, set false to structured JSON log")
 
+	theme                      = flag.String("theme", hugogenerator.ThemePaperMod, "Hugo theme to set up the output website for: "+strings.Join(hugogenerator.ThemeNames(), ", "))
+	themeSource                = flag.String("theme-source", "", "dir path or .tar, .tar.gz or .tgz archive to install the theme from, instead of cloning it from GitHub")
+	offline                    = flag.Bool("offline", false, "create the Hugo site skeleton without running hugo or git, the theme is only installed from --theme-source")
+	frontMatterFormat          = flag.String("frontmatter-format", utils.FormatYAML, "format of the front matter, the Hugo config and the data files: yaml, toml or json")
 	customPostTypes            = flag.String("custom-post-types", "", "CSV list of custom post types to import")
+	categoryAncestors          = flag.Bool("category-ancestors", false, "also file posts under the ancestors of their categories, so that a category lists the posts of its subcategories as in WordPress")
+	taxonomyNames              = flag.String("taxonomy-names", "", "CSV list of taxonomy:singular:plural to rename custom taxonomies in Hugo, e.g. portfolio_skills:skill:skills, the plural is the URL of the terms")
+	mainMenu                   = flag.String("main-menu", "", "slug of the classic WordPress menu to use as the main menu of the theme, by default the first one named main, primary, header or top")
+	reusableBlocks             = flag.String("reusable-blocks", hugogenerator.ReusableBlocksInline, "how to convert the Gutenberg reusable blocks: inline copies them into every page, shortcode writes them once and includes them with a shortcode")
+	shortcodeRules             = flag.String("shortcode-rules", "", "file path to a YAML file of rules converting the shortcodes of WordPress plugins, see doc/shortcodes.md")
+	metaPolicy                 = flag.String("meta-policy", "", "file path to a YAML file choosing the post meta written to the front matter, and how they are renamed and converted, see doc/getting-started.md")
+	summaryPolicy              = flag.String("summary", hugopage.SummaryExcerpt, "summary of the posts having both an excerpt and a <!--more--> divider: excerpt, or more to use the content before the divider and keep the excerpt as description only")
+	embedPrivacy               = flag.Bool("embed-privacy", false, "replace the embeds of YouTube, Vimeo, X, Instagram, SoundCloud, Spotify, TikTok and CodePen with a placeholder which loads them on click")
 	contentDateFolderStructure = flag.String("content-date-folder-structure", hugogenerator.ContentDateFolderStructureFlat, "organize posts/pages by publish date: flat, year, or year-month")
+	// Useful for multi-gigabyte exports that do not fit in memory
+	streamPosts  = flag.Bool("stream-posts", false, "read the source file twice and write posts while parsing them, memory usage stays flat regardless of the number of posts")
+	target       = flag.String("target", outputtarget.TargetHugo, "static site generator to write the content for: "+strings.Join(outputtarget.Names(), ", ")+", the theme flags only apply to hugo")
+	dryRun       = flag.Bool("dry-run", false, "convert the content without generating the site nor downloading media, and write a migration report listing the issues to fix in WordPress first")
+	reportPath   = flag.String("report", "", "file path to write the dry-run report to, standard output if empty")
+	reportFormat = flag.String("report-format", migrationreport.FormatJSON, "format of the dry-run report: json or html")
+	workers      = flag.Int("workers", runtime.NumCPU(), "number of pages converted to Markdown concurrently, the output does not depend on it")
+	tolerant     = flag.Bool("tolerant", false, "skip the WordPress items that cannot be parsed instead of failing, and default their malformed fields")
+	diagnostics  = flag.String("diagnostics", "", "file path to write the items skipped or degraded while parsing to, as JSON")
+	updateSite   = flag.String("update-site", "", "dir path of a Hugo site previously generated by wp2hugo to re-import into, only the pages modified in WordPress since the last import are rewritten and files edited by hand are never overwritten")
 )
 
 var _defaultCustomPosts = []string{"avada_portfolio", "avada_faq", "product", "product_variation"}
@@ -54,31 +86,172 @@ func main() {
 	}
 }
 
-func handle(ctx context.Context, filePath string) error {
+func handle(ctx context.Context, source string) error {
+	filePaths, err := getSourceFiles(source)
+	if err != nil {
+		return err
+	}
 	log.Debug().
-		Str("source", filePath).
+		Strs("source", filePaths).
 		Msg("Reading website export")
-	websiteInfo, err := getWebsiteInfo(filePath)
+	websiteInfo, parseDiagnostics, err := getWebsiteInfo(filePaths)
 	if err != nil {
 		return err
 	}
-	return generate(ctx, *websiteInfo, *outputDir)
+	if *diagnostics != "" {
+		if err := writeDiagnostics(*diagnostics, parseDiagnostics); err != nil {
+			return err
+		}
+	}
+	var postStreamer hugogenerator.PostStreamer
+	if *streamPosts {
+		postStreamer = getPostStreamer(filePaths)
+	}
+	err = generate(ctx, *websiteInfo, *outputDir, postStreamer)
+	logDiagnostics(parseDiagnostics)
+	return err
 }
 
-func getWebsiteInfo(filePath string) (*wpparser.WebsiteInfo, error) {
-	parser := wpparser.NewParser()
+// getSourceFiles expands source, which is either a single file, a directory or a glob pattern.
+// WordPress splits large exports into several files, e.g. WP-CLI's `wp export --max_file_size`
+// produces site.000.xml, site.001.xml, ...
+func getSourceFiles(source string) ([]string, error) {
+	var filePaths []string
+	switch {
+	case utils.DirExists(source):
+		matches, err := filepath.Glob(filepath.Join(source, "*.xml"))
+		if err != nil {
+			return nil, fmt.Errorf("error listing export files in %s: %w", source, err)
+		}
+		filePaths = matches
+	case strings.ContainsAny(source, "*?["):
+		matches, err := filepath.Glob(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source pattern %q: %w", source, err)
+		}
+		filePaths = matches
+	default:
+		filePaths = []string{source}
+	}
+	if len(filePaths) == 0 {
+		return nil, fmt.Errorf("no WordPress export file found for %q", source)
+	}
+	// Keep the order in which WP-CLI numbers the files
+	sort.Strings(filePaths)
+	return filePaths, nil
+}
+
+func getWebsiteInfo(filePaths []string) (*wpparser.WebsiteInfo, []wpparser.Diagnostic, error) {
+	infos := make([]*wpparser.WebsiteInfo, 0, len(filePaths))
+	diagnostics := make([]wpparser.Diagnostic, 0)
+	for _, filePath := range filePaths {
+		parser := wpparser.NewParser()
+		parser.SetTolerant(*tolerant)
+		info, err := parseFile(parser, filePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing %s: %w", filePath, err)
+		}
+		infos = append(infos, info)
+		diagnostics = append(diagnostics, parser.Diagnostics()...)
+	}
+	info, err := wpparser.MergeWebsiteInfos(infos)
+	return info, diagnostics, err
+}
+
+func parseFile(parser *wpparser.Parser, filePath string) (*wpparser.WebsiteInfo, error) {
 	file, err := os.OpenFile(filePath, os.O_RDONLY, 0o644)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		_ = file.Close()
+	}()
 
-	defaultCustomPosts := slices.Clone(_defaultCustomPosts)
-	defaultCustomPosts = append(defaultCustomPosts, strings.Split(*customPostTypes, ",")...)
+	if *streamPosts {
+		// First pass: everything but the posts, they are written during the second pass
+		return parser.ParseStream(file, strings.Split(*authors, ","), getCustomPostTypes(), wpparser.StreamHandler{
+			OnPost: func(wpparser.PostInfo) error { return nil },
+		})
+	}
+	return parser.Parse(file, strings.Split(*authors, ","), getCustomPostTypes())
+}
+
+// logDiagnostics summarizes the items skipped or degraded while parsing
+func logDiagnostics(diagnostics []wpparser.Diagnostic) {
+	if len(diagnostics) == 0 {
+		return
+	}
+	skipped := 0
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Skipped {
+			skipped++
+		}
+		log.Warn().Msg(diagnostic.String())
+	}
+	log.Warn().
+		Int("skipped", skipped).
+		Int("degraded", len(diagnostics)-skipped).
+		Msg("Some WordPress items were malformed, see above")
+}
+
+func writeDiagnostics(filePath string, diagnostics []wpparser.Diagnostic) error {
+	data, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling diagnostics: %w", err)
+	}
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return fmt.Errorf("error writing diagnostics to %s: %w", filePath, err)
+	}
+	return nil
+}
 
-	return parser.Parse(file, strings.Split(*authors, ","), defaultCustomPosts)
+// getPostStreamer returns a streamer that re-reads filePaths and only hands over the posts
+func getPostStreamer(filePaths []string) hugogenerator.PostStreamer {
+	return func(onPost func(post wpparser.PostInfo) error) error {
+		// A post can be present in more than one export file
+		seenPostIDs := make(map[string]bool)
+		for _, filePath := range filePaths {
+			if err := streamFilePosts(filePath, func(post wpparser.PostInfo) error {
+				if seenPostIDs[post.PostID] {
+					return nil
+				}
+				seenPostIDs[post.PostID] = true
+				return onPost(post)
+			}); err != nil {
+				return fmt.Errorf("error streaming posts from %s: %w", filePath, err)
+			}
+		}
+		return nil
+	}
 }
 
-func generate(ctx context.Context, info wpparser.WebsiteInfo, outputDirPath string) error {
+func streamFilePosts(filePath string, onPost func(post wpparser.PostInfo) error) error {
+	file, err := os.OpenFile(filePath, os.O_RDONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// Items are skipped the same way as during the first pass, which has reported them already
+	parser := wpparser.NewParser()
+	parser.SetTolerant(*tolerant)
+	_, err = parser.ParseStream(file, strings.Split(*authors, ","), getCustomPostTypes(), wpparser.StreamHandler{
+		OnAttachment: func(wpparser.AttachmentInfo) error { return nil },
+		OnPage:       func(wpparser.PageInfo) error { return nil },
+		OnPost:       onPost,
+		OnCustomPost: func(wpparser.CustomPostInfo) error { return nil },
+	})
+	return err
+}
+
+func getCustomPostTypes() []string {
+	defaultCustomPosts := slices.Clone(_defaultCustomPosts)
+	return append(defaultCustomPosts, strings.Split(*customPostTypes, ",")...)
+}
+
+func generate(ctx context.Context, info wpparser.WebsiteInfo, outputDirPath string, postStreamer hugogenerator.PostStreamer) error {
 	log.Debug().Msgf("Output: %s", outputDirPath)
 	if !hugogenerator.IsValidContentDateFolderStructure(*contentDateFolderStructure) {
 		return fmt.Errorf("invalid content-date-folder-structure: %q (allowed: %s, %s, %s)",
@@ -88,8 +261,115 @@ func generate(ctx context.Context, info wpparser.WebsiteInfo, outputDirPath stri
 			hugogenerator.ContentDateFolderStructureYearMonth)
 	}
 
-	generator := hugogenerator.NewGenerator(outputDirPath, *font, mediacache.New(*mediaCacheDir),
+	if !hugogenerator.IsValidReusableBlocksMode(*reusableBlocks) {
+		return fmt.Errorf("invalid reusable-blocks: %q (allowed: %s, %s)",
+			*reusableBlocks, hugogenerator.ReusableBlocksInline, hugogenerator.ReusableBlocksShortcode)
+	}
+
+	if !hugopage.IsValidSummaryPolicy(*summaryPolicy) {
+		return fmt.Errorf("invalid summary: %q (allowed: %s, %s)", *summaryPolicy, hugopage.SummaryExcerpt, hugopage.SummaryMore)
+	}
+
+	if !utils.IsValidFormat(*frontMatterFormat) {
+		return fmt.Errorf("invalid frontmatter-format: %q (allowed: %s, %s, %s)",
+			*frontMatterFormat, utils.FormatYAML, utils.FormatTOML, utils.FormatJSON)
+	}
+
+	if *workers < 1 || *downloadWorkers < 1 {
+		return fmt.Errorf("workers and download-workers must be at least 1")
+	}
+	if *downloadsPerSecond <= 0 {
+		return fmt.Errorf("downloads-per-second must be positive")
+	}
+	if *reportFormat != migrationreport.FormatJSON && *reportFormat != migrationreport.FormatHTML {
+		return fmt.Errorf("invalid report-format: %q (allowed: %s, %s)", *reportFormat, migrationreport.FormatJSON, migrationreport.FormatHTML)
+	}
+
+	var selectedShortcodeRules []hugopage.ShortcodeRule
+	if *shortcodeRules != "" {
+		rules, err := hugopage.LoadShortcodeRules(*shortcodeRules)
+		if err != nil {
+			return err
+		}
+		selectedShortcodeRules = rules
+	}
+
+	selectedMetaPolicy := hugopage.DefaultMetaPolicy()
+	if *metaPolicy != "" {
+		policy, err := hugopage.LoadMetaPolicy(*metaPolicy)
+		if err != nil {
+			return err
+		}
+		selectedMetaPolicy = policy
+	}
+
+	selectedTaxonomyNames, err := hugogenerator.ParseTaxonomyNames(*taxonomyNames)
+	if err != nil {
+		return err
+	}
+	selectedTheme, err := hugogenerator.GetTheme(*theme)
+	if err != nil {
+		return err
+	}
+	selectedTarget, err := outputtarget.GetTarget(*target)
+	if err != nil {
+		return err
+	}
+
+	generator := hugogenerator.NewGenerator(outputDirPath, *font, mediacache.New(*mediaCacheDir, *downloadsPerSecond),
 		*downloadMedia, *downloadAll, *continueOnMediaDownloadFailure, *generateNgnixConfig,
 		*contentDateFolderStructure, info)
-	return generator.Generate(ctx)
+	generator.SetTheme(selectedTheme)
+	generator.SetThemeSource(*themeSource)
+	generator.SetOffline(*offline)
+	generator.SetFrontMatterFormat(*frontMatterFormat)
+	generator.SetWorkers(*workers, *downloadWorkers)
+	generator.SetCategoryAncestors(*categoryAncestors)
+	generator.SetTaxonomyNames(selectedTaxonomyNames)
+	generator.SetMainMenu(*mainMenu)
+	generator.SetReusableBlocks(*reusableBlocks)
+	generator.SetEmbedPrivacy(*embedPrivacy)
+	generator.SetSummaryPolicy(*summaryPolicy)
+	generator.SetMetaPolicy(selectedMetaPolicy)
+	generator.SetShortcodeRules(selectedShortcodeRules)
+	if selectedTarget != nil {
+		generator.SetTarget(selectedTarget)
+	}
+	if postStreamer != nil {
+		generator.SetPostStreamer(postStreamer)
+	}
+	if *updateSite != "" {
+		generator.SetUpdateSiteDir(*updateSite)
+	}
+	if !*dryRun {
+		return generator.Generate(ctx)
+	}
+
+	report := migrationreport.New(info)
+	generator.SetDryRun(report)
+	if err := generator.Generate(ctx); err != nil {
+		return err
+	}
+	return writeReport(report)
+}
+
+func writeReport(report *migrationreport.Report) error {
+	if *reportPath == "" {
+		return report.Write(os.Stdout, *reportFormat)
+	}
+	file, err := os.Create(*reportPath)
+	if err != nil {
+		return fmt.Errorf("error creating report file: %w", err)
+	}
+	if err := report.Write(file, *reportFormat); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error closing report file: %w", err)
+	}
+	log.Info().
+		Str("path", *reportPath).
+		Msg("Migration report written")
+	return nil
 }
@@ -0,0 +1,33 @@
+package hugogenerator
+
+import (
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// getPageAuthors returns the authors of page, the ones the export does not declare only have a login
+func (g Generator) getPageAuthors(page wpparser.CommonFields) []wpparser.AuthorInfo {
+	logins := page.Authors
+	if len(logins) == 0 && page.Author != "" {
+		logins = []string{page.Author}
+	}
+	authors := make([]wpparser.AuthorInfo, 0, len(logins))
+	for _, login := range logins {
+		if author := g.wpInfo.GetAuthor(login); author != nil {
+			authors = append(authors, *author)
+		} else {
+			authors = append(authors, wpparser.AuthorInfo{Login: login})
+		}
+	}
+	return authors
+}
+
+// writeAuthorPages writes the page of every author in the authors taxonomy, e.g. content/authors/jdoe/_index.md
+func writeAuthorPages(siteDir string, info wpparser.WebsiteInfo, format string) error {
+	for _, author := range info.Authors() {
+		if err := writeTermPage(siteDir, hugopage.AuthorName, author.Slug(), hugopage.NewAuthorPage(author), format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAuthorPages(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/testcase.WordPress_2.xml")
+	require.NoError(t, err)
+	websiteInfo, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	outputDir := t.TempDir()
+	generator := NewGenerator(outputDir, "", nil, false, false, false, false, ContentDateFolderStructureFlat, *websiteInfo)
+	generator.SetOffline(true)
+	generator.SetTheme(noTheme{})
+	require.NoError(t, generator.Generate(context.Background()))
+
+	siteDirs, err := filepath.Glob(path.Join(outputDir, "generated-*"))
+	require.NoError(t, err)
+	require.Len(t, siteDirs, 1)
+	siteDir := siteDirs[0]
+
+	authorPagePath := path.Join(siteDir, "content", "authors", "jdoe", "_index.md")
+	authorPage, err := os.ReadFile(authorPagePath)
+	require.NoError(t, err)
+	require.Contains(t, string(authorPage), "title: jdoe\n")
+	require.Contains(t, string(authorPage), "- /author/jdoe/\n")
+	require.Contains(t, string(authorPage), "avatar: https://gravatar.com/avatar/")
+	require.NotContains(t, string(authorPage), "john@example.net")
+
+	config, err := os.ReadFile(path.Join(siteDir, "hugo.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(config), "author: authors\n")
+
+	posts, err := filepath.Glob(path.Join(siteDir, "content", "posts", "*.md"))
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	post, err := os.ReadFile(posts[0])
+	require.NoError(t, err)
+	// The author of the post is missing from the export
+	require.Contains(t, string(post), "author: myusername\nauthors:\n  - myusername\n")
+
+	// Pages completed by hand are kept
+	require.NoError(t, os.WriteFile(authorPagePath, []byte("---\ntitle: John\n---\nBio\n"), 0o644))
+	require.NoError(t, writeAuthorPages(siteDir, *websiteInfo, utils.FormatYAML))
+	authorPage, err = os.ReadFile(authorPagePath)
+	require.NoError(t, err)
+	require.Equal(t, "---\ntitle: John\n---\nBio\n", string(authorPage))
+}
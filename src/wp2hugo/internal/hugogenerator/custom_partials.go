@@ -11,6 +11,10 @@ import (
 const _commentsPartial = `
 <!-- fetch /data/comments.yaml -->
 {{ $site_comments := .Site.Data.comments }}
+<!-- TOML data files are tables, the comments are then under "items" -->
+{{ if reflect.IsMap $site_comments }}
+  {{ $site_comments = $site_comments.items }}
+{{ end }}
 
 <!-- /data/comments.yaml not found: skip rendering -->
 {{ $page := . }}
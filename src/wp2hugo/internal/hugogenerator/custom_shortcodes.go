@@ -3,7 +3,9 @@ package hugogenerator
 import (
 	"errors"
 	"path"
+	"strings"
 
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
 	"github.com/rs/zerolog/log"
 )
@@ -17,6 +19,14 @@ const _googleMapsShortCode = `<iframe loading="lazy"
 </iframe>
 `
 
+// Includes the content of a reusable block written to content/reusable-blocks/
+const _reusableBlockShortCode = `{{- with site.GetPage (printf "/reusable-blocks/%s" (.Get "slug")) -}}
+  {{- .Content -}}
+{{- else -}}
+  {{- warnf "Reusable block %q not found: %s" (.Get "slug") .Position -}}
+{{- end -}}
+`
+
 const _selectedPostsShortCode = `{{ $category := .Get "category" }}
 {{ $catLink := .Get "catlink" | default true }}
 {{ $count := .Get "count" | default 5 }}
@@ -128,13 +138,88 @@ const _galleryShortCode = `
 </div>
 `
 
+// Layout of the Gutenberg columns, buttons and cover blocks
+const _columnsShortCode = `
+<div class="wp-columns" style="display: flex; flex-wrap: wrap; gap: 2em;">
+{{- .Inner -}}
+</div>
+`
+
+const _columnShortCode = `
+{{ $p := .Page }}
+<div class="wp-column" style="flex: {{ with .Get "width" }}0 1 {{ . }}{{ else }}1 1 0{{ end }}; min-width: 12em;">
+{{- print .Inner | $p.RenderString (dict "display" "block") -}}
+</div>
+`
+
+const _buttonsShortCode = `
+<div class="wp-buttons" style="display: flex; flex-wrap: wrap; gap: 1em;">
+{{- .Inner -}}
+</div>
+`
+
+const _buttonShortCode = `<a class="wp-button" href="{{ .Get "href" }}"
+{{- with .Get "target" }} target="{{ . }}" rel="noopener"{{ end }}>{{ .Get "text" }}</a>`
+
+const _coverShortCode = `
+{{ $p := .Page }}
+<div class="wp-cover" style="position: relative; overflow: hidden; padding: 4em 2em;">
+{{- with .Get "src" }}
+<img src="{{ . }}" alt="" style="position: absolute; inset: 0; width: 100%; height: 100%; object-fit: cover;">
+{{- end }}
+<div style="position: absolute; inset: 0; background: black; opacity: {{ div (.Get "dim" | default 50 | int) 100.0 }};"></div>
+<div style="position: relative; color: white;">
+{{- print .Inner | $p.RenderString (dict "display" "block") -}}
+</div>
+</div>
+`
+
+const _fileShortCode = `<a href="{{ .Get "src" }}" download>{{ .Get "name" | default (path.Base (.Get "src")) }}</a>`
+
+// Players of the embeds without a built-in Hugo shortcode
+const _soundCloudShortCode = `<iframe loading="lazy" width="100%" height="166" style="border: 0;" allow="autoplay"
+        src="https://w.soundcloud.com/player/?url={{ .Get "url" | urlquery }}">
+</iframe>
+`
+
+const _spotifyShortCode = `<iframe loading="lazy" width="100%" height="{{ if in (slice "track" "episode") (.Get "type") }}152{{ else }}352{{ end }}"
+        style="border: 0;" allow="encrypted-media"
+        src="https://open.spotify.com/embed/{{ .Get "type" }}/{{ .Get "id" }}">
+</iframe>
+`
+
+const _tikTokShortCode = `<iframe loading="lazy" width="325" height="740" style="border: 0;" allowfullscreen
+        src="https://www.tiktok.com/embed/v2/{{ .Get "id" }}">
+</iframe>
+`
+
+const _codePenShortCode = `<iframe loading="lazy" width="100%" height="400" style="border: 0;" allowfullscreen
+        src="https://codepen.io/{{ .Get "user" }}/embed/{{ .Get "id" }}?default-tab=result">
+</iframe>
+`
+
+// Privacy mode: the third-party iframe is only loaded when the visitor clicks on the placeholder
+const _embedPlaceholderShortCode = `<div class="embed-placeholder" style="padding: 2em; border: 1px solid #ccc; text-align: center;">
+  <p>This content is hosted by {{ .Get "provider" }}, loading it sends your data to {{ .Get "provider" }}.</p>
+  <button type="button" data-src="{{ .Get "src" }}"
+          onclick="var f = document.createElement('iframe'); f.src = this.dataset.src; f.width = '100%'; f.height = '400'; f.allowFullscreen = true; f.style.border = '0'; this.parentNode.replaceWith(f);">
+    Load content
+  </button>
+  <p><a href="{{ .Get "url" }}">View on {{ .Get "provider" }}</a></p>
+</div>
+`
+
 func WriteCustomShortCodes(siteDir string) error {
 	return errors.Join(writeGoogleMapsShortCode(siteDir),
 		writeSelectedPostsShortCode(siteDir),
 		writeParallaxBlurShortCode(siteDir),
 		writeAudioShortCode(siteDir),
 		writeVideoShortCode(siteDir),
-		writeGalleryShortCode(siteDir))
+		writeGalleryShortCode(siteDir),
+		writeLayoutShortCodes(siteDir),
+		writeFileShortCode(siteDir),
+		writeEmbedShortCodes(siteDir),
+		writeReusableBlockShortCode(siteDir))
 }
 
 func writeGoogleMapsShortCode(siteDir string) error {
@@ -161,6 +246,41 @@ func writeGalleryShortCode(siteDir string) error {
 	return writeShortCode(siteDir, "gallery", _galleryShortCode)
 }
 
+func writeReusableBlockShortCode(siteDir string) error {
+	return writeShortCode(siteDir, "reusable-block", _reusableBlockShortCode)
+}
+
+func writeLayoutShortCodes(siteDir string) error {
+	return errors.Join(writeShortCode(siteDir, "columns", _columnsShortCode),
+		writeShortCode(siteDir, "column", _columnShortCode),
+		writeShortCode(siteDir, "buttons", _buttonsShortCode),
+		writeShortCode(siteDir, "button", _buttonShortCode),
+		writeShortCode(siteDir, "cover", _coverShortCode))
+}
+
+func writeFileShortCode(siteDir string) error {
+	return writeShortCode(siteDir, "file", _fileShortCode)
+}
+
+func writeEmbedShortCodes(siteDir string) error {
+	return errors.Join(writeShortCode(siteDir, "soundcloud", _soundCloudShortCode),
+		writeShortCode(siteDir, "spotify", _spotifyShortCode),
+		writeShortCode(siteDir, "tiktok", _tikTokShortCode),
+		writeShortCode(siteDir, "codepen", _codePenShortCode),
+		writeShortCode(siteDir, "embed-placeholder", _embedPlaceholderShortCode))
+}
+
+// writeShortcodeRuleLayouts writes the layouts of the shortcode rules, they override the custom shortcodes
+func writeShortcodeRuleLayouts(siteDir string, rules []hugopage.ShortcodeRule) error {
+	var errs []error
+	for _, rule := range rules {
+		if rule.Layout != nil {
+			errs = append(errs, writeShortCode(siteDir, strings.TrimSuffix(rule.Layout.File, ".html"), rule.Layout.Content))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func writeShortCode(siteDir string, shortCodeName string, fileContent string) error {
 	log.Debug().
 		Str("shortcode", shortCodeName).
@@ -0,0 +1,28 @@
+package hugogenerator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteShortcodeRuleLayouts(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	rules := []hugopage.ShortcodeRule{
+		{Name: "su_note", Template: "{{< note >}}", Layout: &hugopage.ShortcodeLayout{File: "note.html", Content: "<div>{{ .Inner }}</div>"}},
+		{Name: "tabs", Template: "${content}"},
+	}
+	require.NoError(t, hugopage.CompileShortcodeRules(rules))
+	require.NoError(t, writeShortcodeRuleLayouts(siteDir, rules))
+
+	content, err := os.ReadFile(filepath.Join(siteDir, "layouts", "shortcodes", "note.html"))
+	require.NoError(t, err)
+	require.Equal(t, "<div>{{ .Inner }}</div>", string(content))
+	entries, err := os.ReadDir(filepath.Join(siteDir, "layouts", "shortcodes"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
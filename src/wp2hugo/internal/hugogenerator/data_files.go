@@ -0,0 +1,41 @@
+package hugogenerator
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+)
+
+// Hugo data files in TOML are tables, lists are written under this key, see _commentsPartial
+const _tomlDataListKey = "items"
+
+type _tomlDataList[T any] struct {
+	Items []T `yaml:"items"`
+}
+
+func getDataFilePath(siteDir string, dataDir string, name string, format string) string {
+	return path.Join(siteDir, dataDir, name+utils.FileExtension(format))
+}
+
+func marshalDataList[T any](format string, list []T) ([]byte, error) {
+	if format == utils.FormatTOML {
+		return utils.Marshal(format, map[string][]T{_tomlDataListKey: list})
+	}
+	return utils.Marshal(format, list)
+}
+
+func unmarshalDataList[T any](format string, data []byte) ([]T, error) {
+	if format == utils.FormatTOML {
+		var table _tomlDataList[T]
+		if err := utils.Unmarshal(format, data, &table); err != nil {
+			return nil, fmt.Errorf("error unmarshalling data list: %w", err)
+		}
+		return table.Items, nil
+	}
+	var list []T
+	if err := utils.Unmarshal(format, data, &list); err != nil {
+		return nil, fmt.Errorf("error unmarshalling data list: %w", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,89 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/migrationreport"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/outputtarget"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// SetDryRun makes the generator convert the content without generating a site nor downloading media,
+// and record in report what would be written and what should be fixed in WordPress first
+func (g *Generator) SetDryRun(report *migrationreport.Report) {
+	g.report = report
+}
+
+// generateDryRun writes the content into a temporary directory, removed afterward, so that the paths
+// in the report, collision suffixes and page bundles included, are the ones of a real import
+func (g Generator) generateDryRun(ctx context.Context) (err error) {
+	if g.updateSiteDir != "" {
+		return errors.New("a dry run cannot update an existing site")
+	}
+	siteDir, err := os.MkdirTemp("", "wp2hugo-dry-run-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, os.RemoveAll(siteDir))
+	}()
+
+	g.downloadMedia = false
+	g.downloadAll = false
+	g.generateNgnixConfig = false
+	if g.manifest, err = readImportManifest(siteDir); err != nil {
+		return err
+	}
+	g.mediaDownloads = newMediaDownloader(g.downloadWorkers)
+	if g.target != nil {
+		g.targetPages = &[]outputtarget.Page{}
+	}
+
+	info := g.wpInfo
+	if err = g.writePosts(ctx, siteDir, info); err != nil {
+		return err
+	}
+	if err = g.writePages(ctx, siteDir, info); err != nil {
+		return err
+	}
+	if err = g.writeCustomPosts(ctx, siteDir, info); err != nil {
+		return err
+	}
+
+	g.report.Finish(func(postID string) string {
+		item, _ := g.manifest.get(postID)
+		return item.Path
+	})
+	log.Info().
+		Int("numItems", g.report.Summary.NumItems).
+		Int("numItemsWithIssues", g.report.Summary.NumItemsWithIssues).
+		Msg("Dry run completed, no site has been generated")
+	return nil
+}
+
+// newReportItem analyzes the page converted from page, its Path is set once written
+func (g Generator) newReportItem(p *hugopage.Page, page wpparser.CommonFields, pageURL *url.URL) *migrationreport.Item {
+	postType := "post"
+	if page.PostType != nil {
+		postType = *page.PostType
+	}
+	return &migrationreport.Item{
+		PostID:                page.PostID,
+		PostType:              postType,
+		Title:                 page.Title,
+		Link:                  page.Link,
+		UnconvertedShortcodes: migrationreport.FindShortcodes(p.Markdown()),
+		UnconvertedBlocks:     p.UnconvertedBlocks(),
+		ExternalMedia:         migrationreport.FindExternalMedia(p.WPMediaLinks(), pageURL.Host),
+		EmptyContent:          strings.TrimSpace(p.Markdown()) == "",
+		UnknownCategories:     g.report.UnknownCategories(page.Categories),
+		MissingLinks:          p.MissingLinks(),
+	}
+}
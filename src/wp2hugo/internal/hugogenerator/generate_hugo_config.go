@@ -1,7 +1,6 @@
 package hugogenerator
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -12,7 +11,6 @@ import (
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
 	"github.com/rs/zerolog/log"
-	"gopkg.in/yaml.v3"
 )
 
 type _HugoNavMenu struct {
@@ -20,6 +18,9 @@ type _HugoNavMenu struct {
 	URL  string `yaml:"url"`
 	// Weight is the order in which the menu items will be displayed
 	Weight int `yaml:"weight"`
+	// Optional, Identifier of the parent entry for nested entries
+	Identifier string `yaml:"identifier,omitempty"`
+	Parent     string `yaml:"parent,omitempty"`
 }
 
 type _HugoAttachment struct {
@@ -33,33 +34,11 @@ type _HugoConfig struct {
 	BaseURL      string `yaml:"baseURL"`
 	LanguageCode string `yaml:"languageCode"`
 	Title        string `yaml:"title"`
-	Theme        string `yaml:"theme"`
-	Taxonomies   struct {
-		Category string `yaml:"category"`
-		Tag      string `yaml:"tag"`
-	}
-	// These will be used for OpenGraph information
-	Params struct {
-		Description         string `yaml:"description"`
-		DefaultTheme        string `yaml:"defaultTheme"`
-		DisableThemeToggle  bool   `yaml:"disableThemeToggle"`
-		ShowShareButtons    bool   `yaml:"showShareButtons"`
-		ShowReadingTime     bool   `yaml:"showReadingTime"`
-		ShowToc             bool   `yaml:"showToc"`
-		ShowBreadCrumbs     bool   `yaml:"showBreadCrumbs"`
-		ShowCodeCopyButtons bool   `yaml:"showCodeCopyButtons"`
-		Comments            bool   `yaml:"comments"`
-		HideFooter          bool   `yaml:"hideFooter"`
-		Assets              struct {
-			Favicon     string `yaml:"favicon"`
-			DisableHLJS bool   `yaml:"disableHLJS"`
-		} `yaml:"assets"`
-		// Author is required by PaperMod's RSS template
-		Author struct {
-			Name  string `yaml:"name"`
-			Email string `yaml:"email,omitempty"`
-		} `yaml:"author"`
-	} `yaml:"params"`
+	Theme        string `yaml:"theme,omitempty"`
+	// Singular name -> plural name
+	Taxonomies map[string]string `yaml:"taxonomies"`
+	// These will be used for OpenGraph information, and by the theme
+	Params map[string]any `yaml:"params"`
 	Markup struct {
 		Highlight struct {
 			CodeFences  bool   `yaml:"codeFences"`
@@ -82,13 +61,12 @@ type _HugoConfig struct {
 			BaseName  string `yaml:"baseName"`
 		} `yaml:"RSS"`
 	} `yaml:"outputFormats"`
-	Menu struct {
-		Main []_HugoNavMenu `yaml:"main"`
-	} `yaml:"menu"`
+	// Menu name -> entries
+	Menu map[string][]_HugoNavMenu `yaml:"menu"`
 }
 
-func setupLibraryData(siteDir string, info wpparser.WebsiteInfo) error {
-	dataPath := path.Join(siteDir, "data", "library.yaml")
+func setupLibraryData(siteDir string, info wpparser.WebsiteInfo, format string) error {
+	dataPath := getDataFilePath(siteDir, "data", "library", format)
 	dataDir := path.Dir(dataPath)
 
 	// Create the directory if it doesn't exist
@@ -97,15 +75,6 @@ func setupLibraryData(siteDir string, info wpparser.WebsiteInfo) error {
 		return fmt.Errorf("error creating directory: %w", err)
 	}
 
-	// Create the YAML file
-	r, err := os.Create(dataPath)
-	if err != nil {
-		return fmt.Errorf("error creating data file: %w", err)
-	}
-	defer func() {
-		_ = r.Close()
-	}()
-
 	// Write the WP media library into data
 	library := make([]_HugoAttachment, 0, len(info.Attachments()))
 	for _, attachment := range info.Attachments() {
@@ -117,7 +86,7 @@ func setupLibraryData(siteDir string, info wpparser.WebsiteInfo) error {
 		})
 	}
 
-	data, err := utils.GetYAML(library)
+	data, err := marshalDataList(format, library)
 	if err != nil {
 		return fmt.Errorf("error marshalling config: %w", err)
 	}
@@ -126,59 +95,46 @@ func setupLibraryData(siteDir string, info wpparser.WebsiteInfo) error {
 	return writeFile(dataPath, data)
 }
 
-func updateConfig(siteDir string, info wpparser.WebsiteInfo) error {
-	configPath := path.Join(siteDir, "hugo.yaml")
-	r, err := os.OpenFile(configPath, os.O_RDONLY, 0o644)
+func getConfigPath(siteDir string, format string) string {
+	return path.Join(siteDir, "hugo"+utils.FileExtension(format))
+}
+
+func updateConfig(siteDir string, info wpparser.WebsiteInfo, theme Theme, format string,
+	taxonomyNames map[string]TaxonomyName, mainMenu string,
+) error {
+	configPath := getConfigPath(siteDir, format)
+	existingConfig, err := os.ReadFile(configPath)
 	if err != nil {
 		return fmt.Errorf("error opening config file: %w", err)
 	}
-	defer func() {
-		_ = r.Close()
-	}()
 
 	var config _HugoConfig
-	if err := yaml.NewDecoder(r).Decode(&config); err != nil {
+	if err := utils.Unmarshal(format, existingConfig, &config); err != nil {
 		return fmt.Errorf("error unmarshalling config: %w", err)
 	}
-	if config.Theme == "" {
-		return errors.New("error: theme is not set in the config file")
-	}
-	// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-faq/
+	config.Theme = theme.Name()
 	config.Title = info.Title()
 	config.BaseURL = info.Link().String()
 	config.LanguageCode = info.Language()
-	config.Taxonomies.Category = hugopage.CategoryName
-	config.Taxonomies.Tag = hugopage.TagName
-	config.Params.Description = info.Description
-	config.Params.Assets.Favicon = "/favicon.ico"
-	config.Params.Assets.DisableHLJS = true
-	// To switch between dark or light according to browser theme
-	config.Params.DefaultTheme = "auto"
-	config.Params.DisableThemeToggle = true
-	config.Params.ShowShareButtons = true
-	config.Params.ShowReadingTime = true
-	config.Params.ShowToc = false
-	config.Params.ShowBreadCrumbs = true
-	config.Params.ShowCodeCopyButtons = true
-	config.Params.Comments = true
-	config.Params.HideFooter = true
+	config.Taxonomies = getHugoTaxonomies(info, taxonomyNames)
+	if config.Params == nil {
+		config.Params = make(map[string]any)
+	}
+	config.Params["description"] = info.Description
 
 	config.Markup.Highlight.CodeFences = true
 	config.Markup.Highlight.GuessSyntax = true
 	config.Markup.Highlight.Style = "monokai"
 	config.Markup.Goldmark.Renderer.Unsafe = true
-	// https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#search-page
-	config.Outputs.Home = []string{"HTML", "RSS", "JSON"}
+	config.Outputs.Home = []string{"HTML", "RSS"}
 	config.OutputFormats.RSS.MediaType = "application/rss+xml"
 	// Same as WordPress's feed.xml
 	config.OutputFormats.RSS.BaseName = "feed"
 
-	addNavigationLinks(info, &config)
+	addNavigationLinks(info, &config, theme.SearchURL(), getNavMenus(info, taxonomyNames, mainMenu))
 	setAuthor(info, &config)
-	if err := r.Close(); err != nil {
-		return fmt.Errorf("error closing config file: %w", err)
-	}
-	data, err := utils.GetYAML(config)
+	theme.UpdateConfig(&config)
+	data, err := utils.Marshal(format, config)
 	if err != nil {
 		return fmt.Errorf("error marshalling config: %w", err)
 	}
@@ -186,35 +142,69 @@ func updateConfig(siteDir string, info wpparser.WebsiteInfo) error {
 	return writeFile(configPath, data)
 }
 
-func addNavigationLinks(info wpparser.WebsiteInfo, config *_HugoConfig) {
-	if len(info.NavigationLinks()) == 0 {
-		return
+// setConfigTheme sets the theme in the config of the Hugo site just created in siteDir
+func setConfigTheme(siteDir string, themeName string, format string) error {
+	configPath := getConfigPath(siteDir, format)
+	existingConfig, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("error opening config file: %w", err)
+	}
+	config := make(map[string]any)
+	if err := utils.Unmarshal(format, existingConfig, &config); err != nil {
+		return fmt.Errorf("error unmarshalling config: %w", err)
 	}
+	config["theme"] = themeName
+	data, err := utils.Marshal(format, config)
+	if err != nil {
+		return fmt.Errorf("error marshalling config: %w", err)
+	}
+	return writeFile(configPath, data)
+}
 
-	searchPresent := false
+// addNavigationLinks adds the menus to the config, the main one being either the navigation
+// of the block theme or one of the classic menus, see getNavMenus
+func addNavigationLinks(info wpparser.WebsiteInfo, config *_HugoConfig, searchURL string,
+	menus map[string][]_HugoNavMenu,
+) {
+	if config.Menu == nil {
+		config.Menu = make(map[string][]_HugoNavMenu)
+	}
+	for name, entries := range menus {
+		config.Menu[name] = append(config.Menu[name], entries...)
+	}
 	for i, link := range info.NavigationLinks() {
-		config.Menu.Main = append(config.Menu.Main, _HugoNavMenu{
+		config.Menu[_mainMenuName] = append(config.Menu[_mainMenuName], _HugoNavMenu{
 			Name:   link.Title,
 			URL:    hugopage.ReplaceAbsoluteLinksWithRelative(info.Link().Host, link.URL),
 			Weight: i + 1,
 		})
-		if strings.HasSuffix(link.URL, "/search/") {
+	}
+	if len(config.Menu[_mainMenuName]) == 0 {
+		return
+	}
+
+	// Themes without search page get no search link
+	searchPresent := searchURL == ""
+	weight := 0
+	for _, entry := range config.Menu[_mainMenuName] {
+		if searchURL != "" && strings.HasSuffix(entry.URL, searchURL) {
 			searchPresent = true
 		}
+		weight = max(weight, entry.Weight)
 	}
 
 	// add search at the end of the menu
 	if !searchPresent {
-		config.Menu.Main = append(config.Menu.Main, _HugoNavMenu{
+		config.Menu[_mainMenuName] = append(config.Menu[_mainMenuName], _HugoNavMenu{
 			Name:   "🔍",
-			URL:    "/search/",
-			Weight: len(info.NavigationLinks()) + 1,
+			URL:    searchURL,
+			Weight: weight + 1,
 		})
 	}
 }
 
 // setAuthor sets the author name in the config using the most common author across posts.
-// This is required by the PaperMod theme's RSS template.
+// This is required by the RSS template of PaperMod and of Hugo itself.
 func setAuthor(info wpparser.WebsiteInfo, config *_HugoConfig) {
 	authorCount := make(map[string]int)
 	for _, post := range info.Posts() {
@@ -232,8 +222,11 @@ func setAuthor(info wpparser.WebsiteInfo, config *_HugoConfig) {
 		}
 	}
 
+	if author := info.GetAuthor(authorName); author != nil {
+		authorName = author.Name()
+	}
 	if authorName == "" {
 		authorName = info.Title()
 	}
-	config.Params.Author.Name = authorName
+	config.Params["author"] = map[string]string{"name": authorName}
 }
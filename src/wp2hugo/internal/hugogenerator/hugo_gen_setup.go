@@ -1,6 +1,7 @@
 package hugogenerator
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,42 +11,30 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/mediacache"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/migrationreport"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/nginxgenerator"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/outputtarget"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
 	"github.com/rs/zerolog/log"
-	"gopkg.in/yaml.v3"
 )
 
-const _archiveContent = `
----
-title: "All"
-layout: "archives"
-url: "/all/"
-summary: archives
----
-`
-
-const _searchContent = `
----
-title: "Search" # in any language you want
-layout: "search" # necessary for search
-summary: "Search"
-url: "/search/"
-placeholder: "placeholder text in search input box"
----
-`
-
 // Find image media thumbnails resized by WP, like `some-file-1920x1080.jpg`
 var _resizedMedia = regexp.MustCompile(`(.*)-\d+x\d+\.(jpg|jpeg|png|webp|gif)`)
 
+// Media are downloaded from the same server, a few connections at a time are enough
+const _defaultDownloadWorkers = 4
+
 const (
 	ContentDateFolderStructureFlat      = "flat"
 	ContentDateFolderStructureYear      = "year"
@@ -53,27 +42,81 @@ const (
 )
 
 type Generator struct {
+	theme Theme
+	// Optional, directory or archive to install the theme from instead of cloning it
+	themeSource string
+	// Format of the front matter, the Hugo config and the data files, see utils.FormatYAML
+	frontMatterFormat string
+	// Set up the site without running hugo nor git
+	offline bool
+
 	fontName                   string
 	imageURLProvider           hugopage.ImageURLProvider
 	outputDirPath              string
 	wpInfo                     wpparser.WebsiteInfo
 	contentDateFolderStructure string
+	// Add the ancestors of their categories to the posts
+	categoryAncestors bool
+	// Names of the custom taxonomies in Hugo, by WordPress taxonomy
+	taxonomyNames map[string]TaxonomyName
+	// Optional, slug of the classic menu to use as main menu
+	mainMenu string
+	// How the reusable blocks are converted, one of ReusableBlocks*
+	reusableBlocks string
+	// Replace the third-party embeds with a placeholder which loads them on click
+	embedPrivacy bool
+	// Conversions of the shortcodes of plugins, and the layouts they need
+	shortcodeRules []hugopage.ShortcodeRule
+	// Summary of the posts having both an excerpt and a <!--more--> divider, one of hugopage.Summary*
+	summaryPolicy string
+	// Post meta written to the front matter
+	metaPolicy hugopage.MetaPolicy
+	// Set by Generate
+	linkResolver  *hugopage.LinkResolver
+	categoryNames map[string]string
+	acfFields     *hugopage.ACFFields
 
 	// Media related
 	mediaProvider                  MediaProvider
 	downloadMedia                  bool
 	downloadAll                    bool
 	continueOnMediaDownloadFailure bool
+	downloadWorkers                int
+	mediaDownloads                 *mediaDownloader
+
+	// Number of pages converted concurrently
+	workers int
 
 	// Nginx related
 	generateNgnixConfig bool
 	ngnixConfig         *nginxgenerator.Config
+
+	// Optional, when set posts are written one at a time as they are streamed
+	// instead of being read from wpInfo
+	postStreamer PostStreamer
+
+	// Optional, when set the existing Hugo site in this directory is updated
+	// instead of generating a new one
+	updateSiteDir string
+	siteUpdater   *siteUpdater
+	manifest      *importManifest
+
+	// Optional, when set the content is written for this static site generator instead of Hugo
+	target      outputtarget.Target
+	targetPages *[]outputtarget.Page
+
+	// Optional, when set nothing is generated and the pages are recorded in the report instead
+	report *migrationreport.Report
 }
 
 type MediaProvider interface {
 	GetReader(ctx context.Context, url string) (io.Reader, error)
 }
 
+// PostStreamer replays the posts of the WordPress export through onPost, one at a time.
+// See wpparser.Parser.ParseStream
+type PostStreamer func(onPost func(post wpparser.PostInfo) error) error
+
 func NewGenerator(outputDirPath string, fontName string,
 	mediaProvider MediaProvider, downloadMedia bool, downloadAll bool, continueOnMediaDownloadFailure bool,
 	generateNgnixConfig bool, contentDateFolderStructure string, info wpparser.WebsiteInfo,
@@ -83,17 +126,25 @@ func NewGenerator(outputDirPath string, fontName string,
 		ngnixConfig = nginxgenerator.NewConfig()
 	}
 	return &Generator{
+		theme:                      paperModTheme{},
+		frontMatterFormat:          utils.FormatYAML,
 		fontName:                   fontName,
 		imageURLProvider:           newImageURLProvider(info),
 		outputDirPath:              outputDirPath,
 		wpInfo:                     info,
 		contentDateFolderStructure: contentDateFolderStructure,
+		reusableBlocks:             ReusableBlocksInline,
+		summaryPolicy:              hugopage.SummaryExcerpt,
+		metaPolicy:                 hugopage.DefaultMetaPolicy(),
 
 		// Media related
 		mediaProvider:                  mediaProvider,
 		downloadMedia:                  downloadMedia,
 		downloadAll:                    downloadAll,
 		continueOnMediaDownloadFailure: continueOnMediaDownloadFailure,
+		downloadWorkers:                _defaultDownloadWorkers,
+
+		workers: runtime.NumCPU(),
 
 		// Nginx related
 		generateNgnixConfig: generateNgnixConfig,
@@ -101,6 +152,78 @@ func NewGenerator(outputDirPath string, fontName string,
 	}
 }
 
+// SetPostStreamer makes the generator write the posts handed over by streamer
+// instead of the ones held in memory by wpparser.WebsiteInfo
+func (g *Generator) SetPostStreamer(streamer PostStreamer) {
+	g.postStreamer = streamer
+}
+
+// SetTheme sets the theme the website is generated for, PaperMod by default
+func (g *Generator) SetTheme(theme Theme) {
+	g.theme = theme
+}
+
+// SetThemeSource makes the generator install the theme from source, a directory or a tarball,
+// instead of cloning it from its repository
+func (g *Generator) SetThemeSource(source string) {
+	g.themeSource = source
+}
+
+// SetOffline makes the generator create the site skeleton itself instead of running hugo and git.
+// The theme is not downloaded then, see SetThemeSource
+func (g *Generator) SetOffline(offline bool) {
+	g.offline = offline
+}
+
+// SetFrontMatterFormat sets the format of the front matter, the Hugo config and the data files,
+// YAML by default
+func (g *Generator) SetFrontMatterFormat(format string) {
+	g.frontMatterFormat = format
+}
+
+// SetUpdateSiteDir makes the generator re-import into the Hugo site previously generated in siteDir
+func (g *Generator) SetUpdateSiteDir(siteDir string) {
+	g.updateSiteDir = siteDir
+}
+
+// SetWorkers sets the number of pages converted concurrently, and the number of media downloaded
+// concurrently. The output is the same whatever the numbers are.
+func (g *Generator) SetWorkers(workers int, downloadWorkers int) {
+	g.workers = workers
+	g.downloadWorkers = downloadWorkers
+}
+
+// SetTarget makes the generator write the content for target instead of Hugo
+func (g *Generator) SetTarget(target outputtarget.Target) {
+	g.target = target
+}
+
+// SetEmbedPrivacy makes the embeds of YouTube, Vimeo, X... load only when the visitor clicks on them
+func (g *Generator) SetEmbedPrivacy(embedPrivacy bool) {
+	g.embedPrivacy = embedPrivacy
+}
+
+// SetSummaryPolicy sets the summary of the posts having both an excerpt and a <!--more--> divider,
+// one of hugopage.Summary*
+func (g *Generator) SetSummaryPolicy(policy string) {
+	g.summaryPolicy = policy
+}
+
+// SetMetaPolicy sets the post meta written to the front matter, see hugopage.LoadMetaPolicy
+func (g *Generator) SetMetaPolicy(policy hugopage.MetaPolicy) {
+	g.metaPolicy = policy
+}
+
+// SetShortcodeRules sets the conversions of the WordPress shortcodes of plugins, see hugopage.LoadShortcodeRules
+func (g *Generator) SetShortcodeRules(rules []hugopage.ShortcodeRule) {
+	g.shortcodeRules = rules
+}
+
+// SetMainMenu sets the slug of the classic WordPress menu to use as main menu
+func (g *Generator) SetMainMenu(slug string) {
+	g.mainMenu = slug
+}
+
 func IsValidContentDateFolderStructure(contentDateFolderStructure string) bool {
 	switch contentDateFolderStructure {
 	case ContentDateFolderStructureFlat, ContentDateFolderStructureYear, ContentDateFolderStructureYearMonth:
@@ -127,13 +250,50 @@ func getDateBasedContentDir(baseDir string, publishDate *time.Time, contentDateF
 
 func (g Generator) Generate(ctx context.Context) error {
 	info := g.wpInfo
-	siteDir, err := g.setupHugo(ctx, g.outputDirPath)
+	if g.target != nil && g.updateSiteDir != "" {
+		return fmt.Errorf("updating an existing site is only supported for Hugo, not %s", g.target.Name())
+	}
+	if g.target != nil && g.frontMatterFormat != utils.FormatYAML {
+		return fmt.Errorf("front matter format %s is only supported for Hugo, %s uses YAML", g.frontMatterFormat, g.target.Name())
+	}
+	if g.target != nil && g.reusableBlocks == ReusableBlocksShortcode {
+		return fmt.Errorf("reusable blocks as shortcodes are only supported for Hugo, not %s", g.target.Name())
+	}
+	for _, rule := range g.shortcodeRules {
+		if g.target != nil && rule.Layout != nil {
+			return fmt.Errorf("the layout of the shortcode rule %s is only supported for Hugo, not %s", rule.Name,
+				g.target.Name())
+		}
+	}
+	g.linkResolver = g.newLinkResolver(info)
+	g.categoryNames = make(map[string]string)
+	for _, category := range info.Categories() {
+		g.categoryNames[category.ID] = category.Name
+	}
+	g.acfFields = hugopage.NewACFFields(info.ACFFieldGroups(), info.Attachments(), g.linkResolver)
+	if g.report != nil {
+		return g.generateDryRun(ctx)
+	}
+	siteDir, err := g.getSiteDir(ctx)
 	if err != nil {
 		return err
 	}
-	if err = updateConfig(*siteDir, info); err != nil {
+	if g.manifest, err = readImportManifest(*siteDir); err != nil {
 		return err
 	}
+	g.mediaDownloads = newMediaDownloader(g.downloadWorkers)
+	switch {
+	case g.target != nil:
+		g.targetPages = &[]outputtarget.Page{}
+	case g.updateSiteDir != "":
+		if g.siteUpdater, err = newSiteUpdater(*siteDir, g.manifest); err != nil {
+			return err
+		}
+	default:
+		if err = updateConfig(*siteDir, info, g.theme, g.frontMatterFormat, g.taxonomyNames, g.mainMenu); err != nil {
+			return err
+		}
+	}
 
 	if g.downloadAll {
 		if err = g.downloadAllMedia(ctx, *siteDir, info); err != nil {
@@ -153,30 +313,75 @@ func (g Generator) Generate(ctx context.Context) error {
 	if err = g.writeCustomPosts(ctx, *siteDir, info); err != nil {
 		return err
 	}
-	if err = setupArchivePage(*siteDir); err != nil {
+	if err = g.manifest.write(); err != nil {
+		return err
+	}
+	if g.target != nil {
+		return g.finishTarget(ctx, *siteDir, info)
+	}
+	if err = writeAuthorPages(*siteDir, info, g.frontMatterFormat); err != nil {
 		return err
 	}
-	if err = setupSearchPage(*siteDir); err != nil {
+	if err = writeTermPages(*siteDir, info, g.taxonomyNames, g.frontMatterFormat); err != nil {
 		return err
 	}
-	if err = setupFont(*siteDir, g.fontName); err != nil {
+	if err = g.writeReusableBlocks(ctx, *siteDir, info); err != nil {
 		return err
 	}
+	if g.siteUpdater != nil {
+		g.siteUpdater.report()
+	} else {
+		// The existing site has those already, and maybe customized
+		if err = g.theme.SetupLayouts(*siteDir, g.fontName); err != nil {
+			return err
+		}
+	}
 	if err = WriteCustomShortCodes(*siteDir); err != nil {
 		return err
 	}
+	if err = writeShortcodeRuleLayouts(*siteDir, g.shortcodeRules); err != nil {
+		return err
+	}
 	if err = WriteCustomPartials(*siteDir); err != nil {
 		return err
 	}
 
-	if err = setupLibraryData(*siteDir, info); err != nil {
+	if err = setupLibraryData(*siteDir, info, g.frontMatterFormat); err != nil {
 		return err
 	}
 
-	if err = setupRssFeedFormat(*siteDir); err != nil {
+	if g.siteUpdater == nil {
+		if err = setupRssFeedFormat(*siteDir, g.theme); err != nil {
+			return err
+		}
+	}
+
+	if err = g.writeSiteExtras(ctx, *siteDir, info); err != nil {
 		return err
 	}
 
+	log.Debug().
+		Str("cmd", fmt.Sprintf("cd %s && hugo serve", *siteDir)).
+		Msg("Hugo site has been generated")
+	return nil
+}
+
+// finishTarget writes the configuration of the site generated for the output target
+func (g Generator) finishTarget(ctx context.Context, siteDir string, info wpparser.WebsiteInfo) error {
+	if err := g.target.Finish(siteDir, info, *g.targetPages); err != nil {
+		return fmt.Errorf("error setting up %s site: %w", g.target.Name(), err)
+	}
+	if err := g.writeSiteExtras(ctx, siteDir, info); err != nil {
+		return err
+	}
+	log.Info().
+		Str("location", siteDir).
+		Msgf("%s site has been generated", g.target.Name())
+	return nil
+}
+
+// writeSiteExtras writes the favicon and the Nginx config, if enabled
+func (g Generator) writeSiteExtras(ctx context.Context, siteDir string, info wpparser.WebsiteInfo) error {
 	if g.downloadMedia {
 		url1 := info.Link().Scheme + "://" + info.Link().Host + "/favicon.ico"
 		media, err := g.mediaProvider.GetReader(ctx, url1)
@@ -186,15 +391,16 @@ func (g Generator) Generate(ctx context.Context) error {
 				Str("url", url1).
 				Msg("error fetching favicon")
 		} else {
-			if err = writeFavicon(path.Join(*siteDir, "static"), media); err != nil {
+			defer closeMedia(media)
+			if err = writeFavicon(path.Join(siteDir, g.mediaDir()), media); err != nil {
 				return err
 			}
 		}
 	}
 
 	if g.generateNgnixConfig {
-		nginxConfigPath := path.Join(*siteDir, "nginx.conf")
-		if err = os.WriteFile(nginxConfigPath, []byte(g.ngnixConfig.Generate()), 0o600); err != nil {
+		nginxConfigPath := path.Join(siteDir, "nginx.conf")
+		if err := os.WriteFile(nginxConfigPath, []byte(g.ngnixConfig.Generate()), 0o600); err != nil {
 			return err
 		} else {
 			log.Info().
@@ -202,102 +408,153 @@ func (g Generator) Generate(ctx context.Context) error {
 				Msg("Nginx config generated")
 		}
 	}
-
-	log.Debug().
-		Str("cmd", fmt.Sprintf("cd %s && hugo serve", *siteDir)).
-		Msg("Hugo site has been generated")
 	return nil
 }
 
-func (g Generator) setupHugo(ctx context.Context, outputDirPath string) (*string, error) {
+// mediaDir is the directory of the site served at the root of the website
+func (g Generator) mediaDir() string {
+	if g.target != nil {
+		return g.target.MediaDir()
+	}
+	return "static"
+}
+
+// dataDir is the directory of the site with the data files, like the comments
+func (g Generator) dataDir() string {
+	if g.target != nil {
+		return g.target.DataDir()
+	}
+	return "data"
+}
+
+func (g Generator) getSiteDir(ctx context.Context) (*string, error) {
+	if g.updateSiteDir != "" {
+		return &g.updateSiteDir, nil
+	}
+	if g.target != nil {
+		return g.setupTargetSite(g.outputDirPath)
+	}
+	return g.setupHugo(ctx, g.outputDirPath)
+}
+
+func getSiteName() string {
 	// Replace spaces and colons with dashes
 	timeFormat := time.Now().Format(
 		strings.ReplaceAll(strings.ReplaceAll(time.DateTime, " ", "-"), ":", "-"))
-	siteName := "generated-" + timeFormat
+	return "generated-" + timeFormat
+}
+
+func createOutputDir(outputDirPath string) error {
+	if err := os.MkdirAll(outputDirPath, 0o700); err != nil {
+		log.Error().
+			Err(err).
+			Str("outputDirPath", outputDirPath).
+			Msg("error creating output directory")
+		return fmt.Errorf("error creating output directory '%s': %w", outputDirPath, err)
+	}
+	return nil
+}
+
+// setupTargetSite creates the empty directory of the site generated for the output target
+func (g Generator) setupTargetSite(outputDirPath string) (*string, error) {
+	if err := createOutputDir(outputDirPath); err != nil {
+		return nil, err
+	}
+	siteDir := path.Join(outputDirPath, getSiteName())
+	if utils.FileExists(siteDir) {
+		return nil, fmt.Errorf("error creating %s site: %s already exists", g.target.Name(), siteDir)
+	}
+	if err := utils.CreateDirIfNotExist(siteDir); err != nil {
+		return nil, err
+	}
+	return &siteDir, nil
+}
+
+func (g Generator) setupHugo(ctx context.Context, outputDirPath string) (*string, error) {
+	siteName := getSiteName()
 	log.Debug().
 		Str("siteName", siteName).
 		Msg("Setting up Hugo site")
-	// Verify hugo is present
-	_, err := exec.LookPath("hugo")
-	if err != nil {
-		log.Fatal().
-			Err(err).
-			Msg("Hugo not found, install it from https://gohugo.io/")
-		return nil, fmt.Errorf("hugo not found, install it from https://gohugo.io/: %w", err)
+	// Create output directory
+	if err := createOutputDir(outputDirPath); err != nil {
+		return nil, err
 	}
 
-	// Create output directory
-	err = os.MkdirAll(outputDirPath, 0o700)
-	if err != nil {
-		log.Fatal().
+	siteDir := path.Join(outputDirPath, siteName)
+	if g.offline {
+		if err := createSiteSkeleton(siteDir, g.theme.Name(), g.frontMatterFormat); err != nil {
+			return nil, err
+		}
+		if err := g.installTheme(ctx, siteDir); err != nil {
+			return nil, err
+		}
+		log.Info().
+			Str("location", siteDir).
+			Msgf("Hugo site skeleton has been created")
+		return &siteDir, nil
+	}
+
+	// Verify hugo is present
+	if _, err := exec.LookPath("hugo"); err != nil {
+		log.Error().
 			Err(err).
-			Str("outputDirPath", outputDirPath).
-			Msg("error creating output directory")
-		return nil, fmt.Errorf("error creating output directory '%s': %w", outputDirPath, err)
+			Msg("Hugo not found, install it from https://gohugo.io/ or use offline mode")
+		return nil, fmt.Errorf("hugo not found, install it from https://gohugo.io/ or use offline mode: %w", err)
 	}
 
 	commands := []string{
-		"git version",
 		"hugo version",
-		// Use YAML file as it is easier to edit it afterward than TOML
-		fmt.Sprintf("cd %s && hugo new site %s --format yaml", outputDirPath, siteName),
-		fmt.Sprintf("cd %s && git clone https://github.com/adityatelange/hugo-PaperMod themes/PaperMod --depth=1",
-			path.Join(outputDirPath, siteName)),
-		// Set theme to PaperMod
-		fmt.Sprintf(`echo theme: 'PaperMod'>> %s/hugo.yaml`, path.Join(outputDirPath, siteName)),
-		// Verify that the site is set up correctly
-		fmt.Sprintf("cd %s && hugo", path.Join(outputDirPath, siteName)),
-	}
-	for i, command := range commands {
-		log.Debug().
-			Int("step", i+1).
-			Int("totalSteps", len(commands)).
-			Str("cmd", command).
-			Msg("Running Hugo setup command")
-		var (
-			output []byte
-			err    error
-		)
-		if runtime.GOOS == "windows" {
-			output, err = exec.CommandContext(ctx, "cmd", "/C", command).Output()
-		} else {
-			// mac & Linux
-			output, err = exec.CommandContext(ctx, "bash", "-c", command).Output()
-		}
-		if err != nil {
-			log.Error().
-				Err(err).
-				Bytes("output", output).
-				Str("cmd", command).
-				Msg("error running Hugo setup command")
-			return nil, fmt.Errorf("error running Hugo setup command '%s' -> %w", command, err)
+		fmt.Sprintf("cd %s && hugo new site %s --format %s", outputDirPath, siteName, g.frontMatterFormat),
+	}
+	if err := runCommands(ctx, commands); err != nil {
+		return nil, err
+	}
+	if err := g.installTheme(ctx, siteDir); err != nil {
+		return nil, err
+	}
+
+	if g.theme.Name() != "" {
+		if err := setConfigTheme(siteDir, g.theme.Name(), g.frontMatterFormat); err != nil {
+			return nil, err
 		}
-		log.Debug().Msgf("Hugo setup output: %s", output)
+	}
+	// Verify that the site is set up correctly
+	if err := runCommands(ctx, []string{fmt.Sprintf("cd %s && hugo", siteDir)}); err != nil {
+		return nil, err
 	}
 
 	// Delete .git directory
-	deleteDirs := []string{
-		path.Join(outputDirPath, siteName, ".git"),
-		path.Join(outputDirPath, siteName, "themes/PaperMod/.git"),
-	}
-	for _, dir := range deleteDirs {
-		err := os.RemoveAll(dir)
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("dir", dir).
-				Msg("error removing directory")
-			return nil, fmt.Errorf("error removing directory '%s': %w", dir, err)
-		}
+	gitDir := path.Join(siteDir, ".git")
+	if err := os.RemoveAll(gitDir); err != nil {
+		log.Error().
+			Err(err).
+			Str("dir", gitDir).
+			Msg("error removing directory")
+		return nil, fmt.Errorf("error removing directory '%s': %w", gitDir, err)
 	}
 
-	siteDir := path.Join(outputDirPath, siteName)
 	log.Info().
 		Str("location", siteDir).
 		Msgf("Hugo site skeleton has been setup")
 	return &siteDir, nil
 }
 
+func (g Generator) installTheme(ctx context.Context, siteDir string) error {
+	var err error
+	switch {
+	case g.themeSource != "":
+		err = installThemeFromSource(siteDir, g.theme.Name(), g.themeSource)
+	case g.offline && g.theme.Name() != "":
+		err = fmt.Errorf("theme %s cannot be downloaded in offline mode, provide it as theme source", g.theme.Name())
+	default:
+		err = g.theme.Install(ctx, siteDir)
+	}
+	if err != nil {
+		return fmt.Errorf("error installing theme: %w", err)
+	}
+	return nil
+}
+
 func (g Generator) downloadAllMedia(ctx context.Context, outputDirPath string, info wpparser.WebsiteInfo) error {
 	hostname := info.Link().Host
 	prefixes := make([]string, 0, 4)
@@ -307,19 +564,37 @@ func (g Generator) downloadAllMedia(ctx context.Context, outputDirPath string, i
 	prefixes = append(prefixes, "https://www."+hostname)
 	prefixes = append(prefixes, "http://www."+hostname)
 
-	for _, attachment := range info.Attachments() {
-		if _, err := downloadMedia(ctx, *attachment.GetAttachmentURL(), outputDirPath, prefixes, g, info.Link()); err != nil {
-			return err
+	attachments := info.Attachments()
+	mediaPaths := make([]string, len(attachments))
+	errs := make([]error, len(attachments))
+	var wg sync.WaitGroup
+	for i, attachment := range attachments {
+		wg.Go(func() {
+			mediaPaths[i], _, errs[i] = downloadMedia(ctx, *attachment.GetAttachmentURL(), outputDirPath, prefixes, g, info.Link())
+		})
+	}
+	wg.Wait()
+
+	// In the order of the export, as a sequential download would
+	for i, attachment := range attachments {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		if mediaPaths[i] != "" {
+			if err := g.manifest.addMedia(attachment.CommonFields, mediaPaths[i]); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// getPagePath returns the path of page, and the path it collides with if any, see getFilePath
 func getPagePath(outputDirPath string, page wpparser.CommonFields, posts []wpparser.CommonFields,
 	contentDateFolderStructure string,
-) (string, error) {
-	pagePath := ""
+) (string, string, error) {
+	pagePath, takenPath := "", ""
 
 	if page.PostParentID != nil {
 		for _, parent := range posts {
@@ -335,9 +610,9 @@ func getPagePath(outputDirPath string, page wpparser.CommonFields, posts []wppar
 					getDateBasedContentDir(pagesBaseDir, parent.PublishDate, contentDateFolderStructure),
 					parentFileName)
 				if err := utils.CreateDirIfNotExist(pagesDir); err != nil {
-					return pagePath, err
+					return pagePath, takenPath, err
 				}
-				pagePath = getFilePath(pagesDir, page.GetFileInfo().FileNameWithLanguage())
+				pagePath, takenPath = getFilePath(pagesDir, page.GetFileInfo().FileNameWithLanguage())
 				break
 			}
 		}
@@ -358,7 +633,7 @@ func getPagePath(outputDirPath string, page wpparser.CommonFields, posts []wppar
 			getDateBasedContentDir(pagesBaseDir, page.PublishDate, contentDateFolderStructure),
 			page.GetFileInfo().FileNameNoLanguage())
 		if err := utils.CreateDirIfNotExist(pagesDir); err != nil {
-			return pagePath, err
+			return pagePath, takenPath, err
 		}
 
 		// If this page has no parent, it is the parent of the page bundle
@@ -368,13 +643,13 @@ func getPagePath(outputDirPath string, page wpparser.CommonFields, posts []wppar
 		if lang != nil {
 			fileName = fmt.Sprintf("%s.%s", fileName, *lang)
 		}
-		pagePath = getFilePath(pagesDir, fileName)
+		pagePath, takenPath = getFilePath(pagesDir, fileName)
 	}
 
-	return pagePath, nil
+	return pagePath, takenPath, nil
 }
 
-func sanitizePageBundles(dirPath string) error {
+func sanitizePageBundles(dirPath string, onRename func(oldPath string, newPath string) error) error {
 	// For pages and custom post types, at import we assume they are all hierarchical,
 	// meaning the parent page gets written into `/content/pages/parent-page/_index.md`
 	// and then the children go into `/content/pages/parent-page/child.md`.
@@ -399,7 +674,7 @@ func sanitizePageBundles(dirPath string) error {
 	for _, file := range files {
 		if file.IsDir() {
 			// Recurse into subdirectory
-			if err := sanitizePageBundles(path.Join(dirPath, file.Name())); err != nil {
+			if err := sanitizePageBundles(path.Join(dirPath, file.Name()), onRename); err != nil {
 				return err
 			}
 		} else {
@@ -430,6 +705,9 @@ func sanitizePageBundles(dirPath string) error {
 						Msg("error renaming _index.md to index.md")
 					return err
 				}
+				if err := onRename(oldPath, newPath); err != nil {
+					return err
+				}
 			} else if strings.HasPrefix(name, "index.") && fileCount > 0 {
 				// index.md defines a leaf but we found other leaves in the subfolder:
 				// convert to branch (aka rename to _index.md)
@@ -444,6 +722,9 @@ func sanitizePageBundles(dirPath string) error {
 						Msg("error renaming index.md to _index.md")
 					return err
 				}
+				if err := onRename(oldPath, newPath); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -451,13 +732,17 @@ func sanitizePageBundles(dirPath string) error {
 	return nil
 }
 
-func sanitizePostType(outputDirPath string, postType string) {
+func (g Generator) sanitizePostType(outputDirPath string, postType string) {
+	if g.target != nil {
+		// Page bundles are specific to Hugo
+		return
+	}
 	// Content type subfolder always uses the plural form of the type name
 	if !strings.HasSuffix(postType, "s") {
 		postType += "s"
 	}
 
-	if err := sanitizePageBundles(path.Join(outputDirPath, "content", postType)); err != nil {
+	if err := sanitizePageBundles(path.Join(outputDirPath, "content", postType), g.manifest.rename); err != nil {
 		// Intentionally ignore the error
 		fmt.Println("Error sanitizing page bundles:", err)
 	}
@@ -469,35 +754,34 @@ func (g Generator) writePages(ctx context.Context, outputDirPath string, info wp
 		return nil
 	}
 
-	pagesDir := path.Join(outputDirPath, "content", "pages")
-	if err := utils.CreateDirIfNotExist(pagesDir); err != nil {
-		return err
-	}
-
 	// Write pages
-	for _, page := range info.Pages() {
-		// If the current element is a child of another custom post,
-		// ensure it is saved in the same directory and
-		// prepend the name of the parent in the filename
-
-		// Convert info.Pages() to a slice of wpparser.CommonFields
-		pages := make([]wpparser.CommonFields, len(info.Pages()))
-		for i, p := range info.Pages() {
-			pages[i] = p.CommonFields
-		}
-		if pagePath, err := getPagePath(outputDirPath, page.CommonFields, pages, g.contentDateFolderStructure); err != nil {
-			return err
-		} else {
-			if err := g.writePage(ctx, outputDirPath, pagePath, page.CommonFields, info); err != nil {
+	err := g.runPagePipeline(ctx, func(pipeline *pagePipeline) error {
+		for _, page := range info.Pages() {
+			// If the current element is a child of another custom post,
+			// ensure it is saved in the same directory and
+			// prepend the name of the parent in the filename
+
+			// Convert info.Pages() to a slice of wpparser.CommonFields
+			pages := make([]wpparser.CommonFields, len(info.Pages()))
+			for i, p := range info.Pages() {
+				pages[i] = p.CommonFields
+			}
+			if err := g.writeItem(pipeline, outputDirPath, page.CommonFields, info, func() (string, string, error) {
+				return getPagePath(outputDirPath, page.CommonFields, pages, g.contentDateFolderStructure)
+			}); err != nil {
 				return err
 			}
+			// Redirect from old URL to new URL
+			g.maybeAddNginxRedirect(page.CommonFields)
 		}
-		// Redirect from old URL to new URL
-		g.maybeAddNginxRedirect(page.CommonFields)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Properly set page bundle type
-	sanitizePostType(outputDirPath, "pages")
+	g.sanitizePostType(outputDirPath, "pages")
 
 	return nil
 }
@@ -509,30 +793,34 @@ func (g Generator) writeCustomPosts(ctx context.Context, outputDirPath string, i
 	}
 
 	// Write custom posts
-	for _, page := range info.CustomPosts() {
-		// If the current element is a child of another custom post,
-		// ensure it is saved in the same directory and
-		// prepend the name of the parent in the filename
-
-		// Convert info.CustomPosts() to a slice of wpparser.CommonFields
-		customPosts := make([]wpparser.CommonFields, len(info.CustomPosts()))
-		for i, cp := range info.CustomPosts() {
-			customPosts[i] = cp.CommonFields
-		}
-		if pagePath, err := getPagePath(outputDirPath, page.CommonFields, customPosts, ContentDateFolderStructureFlat); err != nil {
-			return err
-		} else {
-			if err := g.writePage(ctx, outputDirPath, pagePath, page.CommonFields, info); err != nil {
+	err := g.runPagePipeline(ctx, func(pipeline *pagePipeline) error {
+		for _, page := range info.CustomPosts() {
+			// If the current element is a child of another custom post,
+			// ensure it is saved in the same directory and
+			// prepend the name of the parent in the filename
+
+			// Convert info.CustomPosts() to a slice of wpparser.CommonFields
+			customPosts := make([]wpparser.CommonFields, len(info.CustomPosts()))
+			for i, cp := range info.CustomPosts() {
+				customPosts[i] = cp.CommonFields
+			}
+			if err := g.writeItem(pipeline, outputDirPath, page.CommonFields, info, func() (string, string, error) {
+				return getPagePath(outputDirPath, page.CommonFields, customPosts, ContentDateFolderStructureFlat)
+			}); err != nil {
 				return err
 			}
+			// Redirect from old URL to new URL
+			g.maybeAddNginxRedirect(page.CommonFields)
 		}
-		// Redirect from old URL to new URL
-		g.maybeAddNginxRedirect(page.CommonFields)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Properly set page bundle type
 	for _, postType := range info.CustomPostTypes() {
-		sanitizePostType(outputDirPath, postType)
+		g.sanitizePostType(outputDirPath, postType)
 	}
 
 	return nil
@@ -587,9 +875,17 @@ func sameHost(url1 url.URL, url2 url.URL) bool {
 
 // Sometimes multiple pages have the same filename
 // Ref: https://github.com/ashishb/wp2hugo/issues/7
-func getFilePath(pagesDir string, baseFileName string) string {
-	pagePath := path.Join(pagesDir, baseFileName+".md")
+func getFilePath(pagesDir string, baseFileName string) (string, string) {
+	return getFilePathWithExtension(pagesDir, baseFileName, ".md")
+}
+
+// getFilePathWithExtension returns a path of pagesDir no file has, with a numeric suffix if the path
+// of baseFileName is already taken, and that taken path, empty if there was no collision
+func getFilePathWithExtension(pagesDir string, baseFileName string, extension string) (string, string) {
+	pagePath := path.Join(pagesDir, baseFileName+extension)
+	takenPath := ""
 	if utils.FileExists(pagePath) {
+		takenPath = pagePath
 		for i := 1; ; i++ {
 			log.Info().
 				Str("baseFileName", baseFileName).
@@ -598,58 +894,121 @@ func getFilePath(pagesDir string, baseFileName string) string {
 
 			fileNameParts := strings.SplitN(baseFileName, ".", 2)
 			if len(fileNameParts) == 2 {
-				pagePath = path.Join(pagesDir, fmt.Sprintf("%s-%d.%s.md", fileNameParts[0], i, fileNameParts[1]))
+				pagePath = path.Join(pagesDir, fmt.Sprintf("%s-%d.%s%s", fileNameParts[0], i, fileNameParts[1], extension))
 			} else {
-				pagePath = path.Join(pagesDir, fmt.Sprintf("%s-%d.md", baseFileName, i))
+				pagePath = path.Join(pagesDir, fmt.Sprintf("%s-%d%s", baseFileName, i, extension))
 			}
 			if !utils.FileExists(pagePath) {
 				break
 			}
 		}
 	}
-	return pagePath
+	return pagePath, takenPath
 }
 
 func (g Generator) writePosts(ctx context.Context, outputDirPath string, info wpparser.WebsiteInfo) error {
-	if len(info.Posts()) == 0 {
+	if g.postStreamer == nil && len(info.Posts()) == 0 {
 		log.Info().Msg("No posts to write")
 		return nil
 	}
 
 	postsBaseDir := path.Join(outputDirPath, "content", "posts")
-	if err := utils.CreateDirIfNotExist(postsBaseDir); err != nil {
-		return err
+	return g.runPagePipeline(ctx, func(pipeline *pagePipeline) error {
+		writePost := func(post wpparser.PostInfo) error {
+			return g.writePost(pipeline, outputDirPath, postsBaseDir, post, info)
+		}
+		if g.postStreamer != nil {
+			return g.postStreamer(writePost)
+		}
+
+		// Write posts
+		for _, post := range info.Posts() {
+			if err := writePost(post); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// runPagePipeline submits the pages to a new pagePipeline with submitAll, and waits for them to be written
+func (g Generator) runPagePipeline(ctx context.Context, submitAll func(pipeline *pagePipeline) error) error {
+	pipeline := newPagePipeline(ctx, g.workers)
+	err := submitAll(pipeline)
+	// The error of the pipeline, if any, is the cause of the submission error
+	if waitErr := pipeline.wait(); waitErr != nil {
+		return waitErr
 	}
+	return err
+}
 
-	// Write posts
-	for _, post := range info.Posts() {
+func (g Generator) writePost(pipeline *pagePipeline, outputDirPath string, postsBaseDir string,
+	post wpparser.PostInfo, info wpparser.WebsiteInfo,
+) error {
+	if err := g.writeItem(pipeline, outputDirPath, post.CommonFields, info, func() (string, string, error) {
 		postsDir := getDateBasedContentDir(postsBaseDir, post.PublishDate, g.contentDateFolderStructure)
 		if err := utils.CreateDirIfNotExist(postsDir); err != nil {
-			return err
+			return "", "", err
 		}
 		filename := post.GetFileInfo().FileNameWithLanguage()
-		postPath := getFilePath(postsDir, filename)
-		if err := g.writePage(ctx, outputDirPath, postPath, post.CommonFields, info); err != nil {
-			return err
-		}
-		// Redirect from old URL to new URL
-		g.maybeAddNginxRedirect(post.CommonFields)
+		pagePath, takenPath := getFilePath(postsDir, filename)
+		return pagePath, takenPath, nil
+	}); err != nil {
+		return err
 	}
+	// Redirect from old URL to new URL
+	g.maybeAddNginxRedirect(post.CommonFields)
 	return nil
 }
 
-// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#archives-layout
-func setupArchivePage(siteDir string) error {
-	filePath := path.Join(siteDir, "content", "archives.md")
-	content := _archiveContent
-	return writeFile(filePath, []byte(content))
+// writeItem writes page to the path returned by getNewPath, unless the site is being updated
+// and page has been generated before.
+// The page is converted concurrently by pipeline, getNewPath is called when writing it,
+// it also returns the path the page collides with, if any.
+func (g Generator) writeItem(pipeline *pagePipeline, outputDirPath string, page wpparser.CommonFields,
+	info wpparser.WebsiteInfo, getNewPath func() (string, string, error),
+) error {
+	getPagePath := getNewPath
+	if g.target != nil {
+		getPagePath = func() (string, string, error) {
+			return g.getTargetPagePath(outputDirPath, page)
+		}
+	}
+	if g.siteUpdater != nil {
+		existingPath, write := g.siteUpdater.getPagePath(page)
+		if !write {
+			return nil
+		}
+		if existingPath != "" {
+			getPagePath = func() (string, string, error) {
+				return existingPath, "", nil
+			}
+		}
+	}
+
+	return pipeline.submit(func(ctx context.Context) (func() error, error) {
+		converted, err := g.convertPage(ctx, outputDirPath, page)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			pagePath, takenPath, err := getPagePath()
+			if err != nil {
+				return err
+			}
+			return g.writeConvertedPage(outputDirPath, pagePath, takenPath, page, info, converted)
+		}, nil
+	})
 }
 
-// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#search-page
-func setupSearchPage(siteDir string) error {
-	filePath := path.Join(siteDir, "content", "search.md")
-	content := _searchContent
-	return writeFile(filePath, []byte(content))
+func (g Generator) getTargetPagePath(outputDirPath string, page wpparser.CommonFields) (string, string, error) {
+	dir, fileName := g.target.PagePath(page)
+	pagesDir := path.Join(outputDirPath, dir)
+	if err := utils.CreateDirIfNotExist(pagesDir); err != nil {
+		return "", "", err
+	}
+	pagePath, takenPath := getFilePathWithExtension(pagesDir, fileName, g.target.FileExtension())
+	return pagePath, takenPath, nil
 }
 
 func writeFile(filePath string, content []byte) error {
@@ -667,48 +1026,38 @@ func writeFile(filePath string, content []byte) error {
 	return nil
 }
 
-func updateComments(siteDir string, pageData wpparser.CommonFields, info wpparser.WebsiteInfo) error {
-	dataPath := path.Join(siteDir, "data", "comments.yaml")
-	dataDir := path.Dir(dataPath)
-
+func updateComments(siteDir string, dataDir string, format string, pageData wpparser.CommonFields, info wpparser.WebsiteInfo) error {
+	dataPath := getDataFilePath(siteDir, dataDir, "comments", format)
 	// Create the directory if it doesn't exist
-	err := os.MkdirAll(dataDir, 0o755)
+	err := os.MkdirAll(path.Dir(dataPath), 0o755)
 	if err != nil {
 		return fmt.Errorf("error creating directory: %w", err)
 	}
 
-	// Open/Create the YAML file
-	r, err := os.OpenFile(dataPath, os.O_RDWR|os.O_CREATE, 0o644)
-	if err != nil {
-		return fmt.Errorf("error opening data file: %w", err)
-	}
-	defer func() {
-		_ = r.Close()
-	}()
-
-	// Check if file is empty
-	stat, err := r.Stat()
-	if err != nil {
-		return fmt.Errorf("error stating file: %w", err)
-	}
-
 	// Fetch existing comments
 	// pre-allocate len(pageData.Comments) accepting that there might be more comments
 	comments := make([]wpparser.CommentInfo, 0, len(pageData.Comments))
-	if stat.Size() > 0 {
-		if err := yaml.NewDecoder(r).Decode(&comments); err != nil {
+	existingData, err := os.ReadFile(dataPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error reading data file: %w", err)
+	}
+	if len(existingData) > 0 {
+		if comments, err = unmarshalDataList[wpparser.CommentInfo](format, existingData); err != nil {
 			return fmt.Errorf("error unmarshalling comments: %w", err)
 		}
 	}
 
-	// Append comments for the current post
+	// Replace the comments of the current post, it might have been imported before
+	comments = slices.DeleteFunc(comments, func(comment wpparser.CommentInfo) bool {
+		return comment.PostID == pageData.PostID
+	})
 	for _, comment := range pageData.Comments {
 		comment.PostLink = hugopage.ReplaceAbsoluteLinksWithRelative(info.Link().Host, comment.PostLink)
 		comments = append(comments, comment)
 	}
 
 	// Update the file
-	data, err := utils.GetYAML(comments)
+	data, err := marshalDataList(format, comments)
 	if err != nil {
 		return fmt.Errorf("error marshalling comments: %w", err)
 	}
@@ -718,61 +1067,173 @@ func updateComments(siteDir string, pageData wpparser.CommonFields, info wpparse
 	return writeFile(dataPath, data)
 }
 
-func (g Generator) writePage(ctx context.Context, outputMediaDirPath string, pagePath string,
-	page wpparser.CommonFields, info wpparser.WebsiteInfo,
-) error {
+// convertedPage is a page converted to Markdown, along with the media downloaded for it
+type convertedPage struct {
+	content    []byte
+	mediaPaths []string
+	// Only for dry runs
+	reportItem *migrationreport.Item
+}
+
+// convertPage converts page and downloads its media, it is safe for concurrent use
+func (g Generator) convertPage(ctx context.Context, outputMediaDirPath string, page wpparser.CommonFields) (*convertedPage, error) {
 	pageURL, err := url.Parse(page.Link)
 	if err != nil {
-		return fmt.Errorf("error parsing page URL: %w", err)
+		return nil, fmt.Errorf("error parsing page URL: %w", err)
 	}
 
 	p, err := g.newHugoPage(pageURL, page)
 	if err != nil {
-		return fmt.Errorf("error creating Hugo page: %w", err)
+		return nil, fmt.Errorf("error creating Hugo page: %w", err)
 	}
 
+	var reportItem *migrationreport.Item
+	if g.report != nil {
+		reportItem = g.newReportItem(p, page, pageURL)
+	}
+
+	var mediaPaths []string
 	if g.downloadMedia {
-		urlReplacements, err := g.downloadPageMedia(ctx, outputMediaDirPath, p, pageURL)
+		urlReplacements, downloadedPaths, err := g.downloadPageMedia(ctx, outputMediaDirPath, p, pageURL)
 		if err != nil {
-			return err
+			return nil, err
 		} else {
 			p.Replace(urlReplacements)
+			mediaPaths = downloadedPaths
 		}
 	}
 
-	w, err := os.OpenFile(pagePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	content, err := g.renderPage(p)
 	if err != nil {
-		return fmt.Errorf("error opening page file: %w", err)
+		return nil, err
 	}
+	return &convertedPage{content: content, mediaPaths: mediaPaths, reportItem: reportItem}, nil
+}
 
-	if err = p.Write(w); err != nil {
+// writeConvertedPage writes page, converted by convertPage, to pagePath and records it,
+// takenPath is the path of the file page collides with, if any.
+// Pages are written one at a time, see pagePipeline
+func (g Generator) writeConvertedPage(outputMediaDirPath string, pagePath string, takenPath string,
+	page wpparser.CommonFields, info wpparser.WebsiteInfo, converted *convertedPage,
+) error {
+	if err := writeFile(pagePath, converted.content); err != nil {
 		return fmt.Errorf("error writing page file: %w", err)
 	}
-
-	if err = w.Close(); err != nil {
-		return fmt.Errorf("error closing page file: %w", err)
+	if err := g.manifest.add(page, pagePath, converted.content, converted.mediaPaths); err != nil {
+		return err
+	}
+	if converted.reportItem != nil {
+		item := *converted.reportItem
+		item.Path = pagePath
+		item.CollidesWith = takenPath
+		g.report.Add(item)
+	}
+	if g.target != nil {
+		if err := g.addTargetPage(outputMediaDirPath, pagePath, page); err != nil {
+			return err
+		}
 	}
 
 	log.Info().Msgf("Page written: %s", pagePath)
 
-	if err := updateComments(outputMediaDirPath, page, info); err != nil {
+	if err := updateComments(outputMediaDirPath, g.dataDir(), g.frontMatterFormat, page, info); err != nil {
 		return fmt.Errorf("error saving comments: %w", err)
 	}
 
 	return nil
 }
 
+func (g Generator) renderPage(p *hugopage.Page) ([]byte, error) {
+	if g.target != nil {
+		content, err := g.target.Render(p.Metadata(), p.Markdown())
+		if err != nil {
+			return nil, fmt.Errorf("error rendering %s page: %w", g.target.Name(), err)
+		}
+		return content, nil
+	}
+	var content bytes.Buffer
+	if err := p.Write(&content, g.frontMatterFormat); err != nil {
+		return nil, fmt.Errorf("error writing page file: %w", err)
+	}
+	return content.Bytes(), nil
+}
+
+func (g Generator) addTargetPage(siteDir string, pagePath string, page wpparser.CommonFields) error {
+	relativePath, err := filepath.Rel(siteDir, pagePath)
+	if err != nil {
+		return fmt.Errorf("error getting relative path of %s: %w", pagePath, err)
+	}
+	postType := "post"
+	if page.PostType != nil {
+		postType = *page.PostType
+	}
+	*g.targetPages = append(*g.targetPages, outputtarget.Page{
+		Path:        filepath.ToSlash(relativePath),
+		Title:       page.Title,
+		PostType:    postType,
+		PublishDate: page.PublishDate,
+	})
+	return nil
+}
+
+func (g Generator) frontMatter() hugopage.FrontMatterMapper {
+	if g.target != nil {
+		return g.target.FrontMatter()
+	}
+	return g.theme.FrontMatter()
+}
+
+func (g Generator) pageOptions() hugopage.Options {
+	return hugopage.Options{
+		EmbedPrivacy:   g.embedPrivacy,
+		ShortcodeRules: g.shortcodeRules,
+		LinkResolver:   g.linkResolver,
+		SummaryPolicy:  g.summaryPolicy,
+		CategoryNames:  g.categoryNames,
+		MetaPolicy:     g.metaPolicy,
+		ACFFields:      g.acfFields,
+	}
+}
+
+// newLinkResolver returns the resolver of the links to the items of info. Streamed posts are not known
+// in advance, so the links to them are not resolved and the missing items are not reported
+func (g Generator) newLinkResolver(info wpparser.WebsiteInfo) *hugopage.LinkResolver {
+	if info.Link() == nil {
+		return nil
+	}
+	var items []wpparser.CommonFields
+	for _, post := range info.Posts() {
+		items = append(items, post.CommonFields)
+	}
+	for _, page := range info.Pages() {
+		items = append(items, page.CommonFields)
+	}
+	for _, post := range info.CustomPosts() {
+		items = append(items, post.CommonFields)
+	}
+	return hugopage.NewLinkResolver(*info.Link(), items, info.Attachments(), g.postStreamer == nil)
+}
+
 func (g Generator) newHugoPage(pageURL *url.URL, page wpparser.CommonFields) (*hugopage.Page, error) {
 	return hugopage.NewPage(
 		g.imageURLProvider,
-		*pageURL, page.Author, page.Title, page.PublishDate,
+		*pageURL, g.getPageAuthors(page), page.Title, page.PublishDate,
 		page.PublishStatus == wpparser.PublishStatusDraft || page.PublishStatus == wpparser.PublishStatusPending,
-		page.Categories, page.Tags, g.wpInfo.GetAttachmentsForPost(page.PostID),
-		page.Footnotes, page.Content, page.GUID, page.FeaturedImageID, page.PostFormat,
-		page.CustomMetaData, page.Taxonomies, page.PostID, page.PostParentID)
+		g.getPageCategories(page), page.Tags, g.wpInfo.GetAttachmentsForPost(page.PostID),
+		page.Footnotes, g.expandReusableBlocks(page.Content), page.Excerpt, page.GUID, page.FeaturedImageID, page.PostFormat,
+		page.CustomMetaData, g.getPageTaxonomies(page), page.PostID, page.PostParentID, g.frontMatter(), g.pageOptions())
+}
+
+// downloadMedia downloads link into the static directory, and returns the path of the downloaded file
+// (empty if it has not been downloaded) and the links to replace with the full resolution ones.
+// The number of concurrent downloads is bounded by g.mediaDownloads
+func downloadMedia(ctx context.Context, link string, outputMediaDirPath string, prefixes []string, g Generator, pageURL *url.URL) (string, map[string]string, error) {
+	return g.mediaDownloads.download(ctx, link, func() (string, map[string]string, error) {
+		return downloadMediaNow(ctx, link, outputMediaDirPath, prefixes, g, pageURL)
+	})
 }
 
-func downloadMedia(ctx context.Context, link string, outputMediaDirPath string, prefixes []string, g Generator, pageURL *url.URL) (map[string]string, error) {
+func downloadMediaNow(ctx context.Context, link string, outputMediaDirPath string, prefixes []string, g Generator, pageURL *url.URL) (string, map[string]string, error) {
 	// Uniformize protocol-less links: add protocol
 	if strings.HasPrefix(link, "//") {
 		link = strings.Replace(link, "//", pageURL.Scheme+"://", 1)
@@ -790,11 +1251,11 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 			Str("link", link).
 			Str("source", pageURL.String()).
 			Msg("non-relative link (skipped for download)")
-		return nil, nil
+		return "", nil, nil
 	}
 
 	relativeLink := link
-	outputFilePath := fmt.Sprintf("%s/static/%s", outputMediaDirPath,
+	outputFilePath := fmt.Sprintf("%s/%s", path.Join(outputMediaDirPath, g.mediaDir()),
 		strings.TrimSuffix(strings.Split(link, "?")[0], "/"))
 
 	if strings.HasPrefix(link, "http") {
@@ -856,7 +1317,7 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 				Str("pageLink", pageURL.String()).
 				Str("outputFilePath", outputFilePath).
 				Msg("server returned 406 Not Acceptable for media file, skipping")
-			return urlReplacement, nil
+			return "", urlReplacement, nil
 		}
 		if g.continueOnMediaDownloadFailure {
 			log.Error().
@@ -865,12 +1326,13 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 				Str("pageLink", pageURL.String()).
 				Str("outputFilePath", outputFilePath).
 				Msg("error fetching media file")
-			return urlReplacement, nil
+			return "", urlReplacement, nil
 		} else {
-			return nil, fmt.Errorf("error fetching media file %s: %w", link, err)
+			return "", nil, fmt.Errorf("error fetching media file %s: %w", link, err)
 		}
 	}
 
+	defer closeMedia(media)
 	if err = download(outputFilePath, media); err != nil {
 		if g.continueOnMediaDownloadFailure {
 			log.Error().
@@ -878,15 +1340,16 @@ func downloadMedia(ctx context.Context, link string, outputMediaDirPath string,
 				Str("mediaLink", link).
 				Str("pageLink", pageURL.String()).
 				Msg("error downloading media file")
+			return "", urlReplacement, nil
 		} else {
-			return nil, fmt.Errorf("error downloading media file: %w embedded in %s", err, pageURL.String())
+			return "", nil, fmt.Errorf("error downloading media file: %w embedded in %s", err, pageURL.String())
 		}
 	}
 
-	return urlReplacement, nil
+	return outputFilePath, urlReplacement, nil
 }
 
-func (g Generator) downloadPageMedia(ctx context.Context, outputMediaDirPath string, p *hugopage.Page, pageURL *url.URL) (map[string]string, error) {
+func (g Generator) downloadPageMedia(ctx context.Context, outputMediaDirPath string, p *hugopage.Page, pageURL *url.URL) (map[string]string, []string, error) {
 	links := p.WPMediaLinks()
 	log.Debug().
 		Str("page", pageURL.String()).
@@ -905,14 +1368,32 @@ func (g Generator) downloadPageMedia(ctx context.Context, outputMediaDirPath str
 	prefixes = append(prefixes, "https://www."+hostname)
 	prefixes = append(prefixes, "http://www."+hostname)
 
-	urlReplacements := make(map[string]string)
+	type result struct {
+		mediaPath   string
+		replacement map[string]string
+		err         error
+	}
+	results := make([]result, len(links))
+	var wg sync.WaitGroup
+	for i, link := range links {
+		wg.Go(func() {
+			mediaPath, replacement, err := downloadMedia(ctx, link, outputMediaDirPath, prefixes, g, pageURL)
+			results[i] = result{mediaPath: mediaPath, replacement: replacement, err: err}
+		})
+	}
+	wg.Wait()
 
-	for _, link := range links {
-		if replacement, err := downloadMedia(ctx, link, outputMediaDirPath, prefixes, g, pageURL); err != nil {
-			return nil, err
-		} else {
-			maps.Copy(urlReplacements, replacement)
+	// Merged in the order of the links, as a sequential download would
+	urlReplacements := make(map[string]string)
+	mediaPaths := make([]string, 0, len(links))
+	for _, result := range results {
+		if result.err != nil {
+			return nil, nil, result.err
+		}
+		maps.Copy(urlReplacements, result.replacement)
+		if result.mediaPath != "" {
+			mediaPaths = append(mediaPaths, result.mediaPath)
 		}
 	}
-	return urlReplacements, nil
+	return urlReplacements, mediaPaths, nil
 }
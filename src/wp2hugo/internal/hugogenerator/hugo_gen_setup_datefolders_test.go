@@ -26,7 +26,7 @@ func TestGetPagePath_WithYearMonthFolders_NoParent(t *testing.T) {
 	postType := "page"
 	publishDate := time.Date(2026, time.March, 4, 10, 0, 0, 0, time.UTC)
 
-	pagePath, err := getPagePath(outputDir, wpparser.CommonFields{
+	pagePath, _, err := getPagePath(outputDir, wpparser.CommonFields{
 		Title:       "My page",
 		Link:        "https://example.com/my-page/",
 		PostType:    &postType,
@@ -61,7 +61,7 @@ func TestGetPagePath_WithYearMonthFolders_ChildUsesParentFolder(t *testing.T) {
 		PostParentID: &childParentID,
 	}
 
-	pagePath, err := getPagePath(outputDir, child, []wpparser.CommonFields{parent}, ContentDateFolderStructureYearMonth)
+	pagePath, _, err := getPagePath(outputDir, child, []wpparser.CommonFields{parent}, ContentDateFolderStructureYearMonth)
 	require.NoError(t, err)
 	require.Equal(t, path.Join(outputDir, "content", "pages", "2025", "01", "parent-page", "child-page.md"), pagePath)
 }
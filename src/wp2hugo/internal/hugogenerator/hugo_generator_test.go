@@ -1,10 +1,16 @@
 package hugogenerator
 
 import (
+	"context"
 	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"testing"
 
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/migrationreport"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/outputtarget"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
 	"github.com/stretchr/testify/require"
 )
@@ -50,3 +56,102 @@ func TestPost(t *testing.T) {
 	require.Equal(t, "netzfundst��cke", post.Categories[0])
 	require.Len(t, post.Content, 1276)
 }
+
+func TestGenerateForTarget(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/testcase.WordPress_2.xml")
+	require.NoError(t, err)
+	websiteInfo, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	target, err := outputtarget.GetTarget(outputtarget.TargetJekyll)
+	require.NoError(t, err)
+	outputDir := t.TempDir()
+	generator := NewGenerator(outputDir, "", nil, false, false, false, false, ContentDateFolderStructureFlat, *websiteInfo)
+	generator.SetTarget(target)
+	require.NoError(t, generator.Generate(context.Background()))
+
+	siteDirs, err := filepath.Glob(path.Join(outputDir, "generated-*"))
+	require.NoError(t, err)
+	require.Len(t, siteDirs, 1)
+	siteDir := siteDirs[0]
+
+	posts, err := filepath.Glob(path.Join(siteDir, "_posts", "*.md"))
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.FileExists(t, path.Join(siteDir, "_config.yml"))
+	require.FileExists(t, path.Join(siteDir, "_includes", "figure.html"))
+	require.NoDirExists(t, path.Join(siteDir, "content"))
+	require.NoDirExists(t, path.Join(siteDir, "layouts"))
+}
+
+func TestGenerateForTarget_UpdateSite(t *testing.T) {
+	t.Parallel()
+	target, err := outputtarget.GetTarget(outputtarget.TargetMkDocs)
+	require.NoError(t, err)
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, ContentDateFolderStructureFlat, wpparser.WebsiteInfo{})
+	generator.SetTarget(target)
+	generator.SetUpdateSiteDir(t.TempDir())
+	require.ErrorContains(t, generator.Generate(context.Background()), "only supported for Hugo")
+}
+
+func TestGenerateForTarget_ShortcodeRuleLayouts(t *testing.T) {
+	t.Parallel()
+	target, err := outputtarget.GetTarget(outputtarget.TargetJekyll)
+	require.NoError(t, err)
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, ContentDateFolderStructureFlat, wpparser.WebsiteInfo{})
+	generator.SetTarget(target)
+	generator.SetShortcodeRules([]hugopage.ShortcodeRule{{
+		Name:     "su_note",
+		Template: `{{< note >}}${content}{{< /note >}}`,
+		Layout:   &hugopage.ShortcodeLayout{File: "note.html", Content: "<div>{{ .Inner }}</div>"},
+	}})
+	require.ErrorContains(t, generator.Generate(context.Background()), "only supported for Hugo")
+}
+
+func TestGenerateDryRun(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/testcase.WordPress.2024-07-01.xml")
+	require.NoError(t, err)
+	websiteInfo, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	outputDir := t.TempDir()
+	report := migrationreport.New(*websiteInfo)
+	generator := NewGenerator(outputDir, "", nil, true, true, false, true, ContentDateFolderStructureFlat, *websiteInfo)
+	generator.SetDryRun(report)
+	require.NoError(t, generator.Generate(context.Background()))
+
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	require.Len(t, report.Items, 1)
+	item := report.Items[0]
+	require.Equal(t, "1", item.PostID)
+	require.Equal(t, "content/posts/test-entry.md", item.Path)
+	// Not declared in the export
+	require.Equal(t, []string{"main"}, item.UnknownCategories)
+	require.Equal(t, 1, report.Summary.NumItemsWithIssues)
+}
+
+func TestGenerateDryRun_Collisions(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/collisions.WordPress.xml")
+	require.NoError(t, err)
+	websiteInfo, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	report := migrationreport.New(*websiteInfo)
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, ContentDateFolderStructureFlat, *websiteInfo)
+	generator.SetDryRun(report)
+	require.NoError(t, generator.Generate(context.Background()))
+
+	require.Len(t, report.Items, 3)
+	require.Equal(t, "content/posts/top-tips-2.md", report.Items[1].Path)
+	// Its slug ends with a number, it does not collide with top-tips.md
+	require.Empty(t, report.Items[1].CollidesWith)
+	require.Equal(t, "content/posts/top-tips-1.md", report.Items[2].Path)
+	require.Equal(t, "content/posts/top-tips.md", report.Items[2].CollidesWith)
+	require.Equal(t, 1, report.Summary.NumCollisions)
+}
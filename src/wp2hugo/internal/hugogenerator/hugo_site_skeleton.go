@@ -0,0 +1,260 @@
+package hugogenerator
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/rs/zerolog/log"
+)
+
+// Same as what `hugo new site --format yaml` creates
+const _defaultArchetype = `---
+date: '{{ .Date }}'
+draft: true
+title: '{{ replace .File.ContentBaseName "-" " " | title }}'
+---
+`
+
+// Same as what `hugo new site --format toml` creates
+const _defaultTOMLArchetype = `+++
+date = '{{ .Date }}'
+draft = true
+title = '{{ replace .File.ContentBaseName "-" " " | title }}'
++++
+`
+
+// Same as what `hugo new site --format json` creates
+const _defaultJSONArchetype = `{
+   "date": "{{ .Date }}",
+   "draft": true,
+   "title": "{{ replace .File.ContentBaseName "-" " " | title }}"
+}
+`
+
+func getDefaultArchetype(format string) ([]byte, error) {
+	switch format {
+	case utils.FormatYAML:
+		return []byte(_defaultArchetype), nil
+	case utils.FormatTOML:
+		return []byte(_defaultTOMLArchetype), nil
+	case utils.FormatJSON:
+		return []byte(_defaultJSONArchetype), nil
+	default:
+		return nil, fmt.Errorf("unknown front matter format %q", format)
+	}
+}
+
+// Same as what `hugo new site` creates
+var _defaultHugoConfig = map[string]string{
+	"baseURL":      "https://example.org/",
+	"languageCode": "en-us",
+	"title":        "My New Hugo Site",
+}
+
+var _siteSkeletonDirs = []string{"archetypes", "assets", "content", "data", "i18n", "layouts", "static", "themes"}
+
+// createSiteSkeleton creates the same site as `hugo new site --format <format>` would,
+// without requiring the hugo binary
+func createSiteSkeleton(siteDir string, themeName string, format string) error {
+	if utils.FileExists(siteDir) {
+		return fmt.Errorf("error creating Hugo site: %s already exists", siteDir)
+	}
+	for _, dir := range _siteSkeletonDirs {
+		if err := utils.CreateDirIfNotExist(path.Join(siteDir, dir)); err != nil {
+			return err
+		}
+	}
+
+	config := maps.Clone(_defaultHugoConfig)
+	if themeName != "" {
+		config["theme"] = themeName
+	}
+	configData, err := utils.Marshal(format, config)
+	if err != nil {
+		return fmt.Errorf("error marshalling config: %w", err)
+	}
+	archetype, err := getDefaultArchetype(format)
+	if err != nil {
+		return err
+	}
+	return errors.Join(
+		writeFile(path.Join(siteDir, "archetypes", "default.md"), archetype),
+		writeFile(getConfigPath(siteDir, format), configData))
+}
+
+// installThemeFromSource installs the theme into themes/<themeName> of siteDir from source,
+// either a directory or a .tar, .tar.gz or .tgz archive, e.g. one downloaded from GitHub
+func installThemeFromSource(siteDir string, themeName string, source string) error {
+	if themeName == "" {
+		return errors.New("a theme source is given but no theme is used")
+	}
+	themeDir := path.Join(siteDir, "themes", themeName)
+	log.Info().
+		Str("source", source).
+		Str("themeDir", themeDir).
+		Msg("Installing theme")
+
+	if utils.DirExists(source) {
+		return copyDir(source, themeDir)
+	}
+	switch {
+	case strings.HasSuffix(source, ".tar.gz"), strings.HasSuffix(source, ".tgz"):
+		return extractTarball(source, themeDir, true)
+	case strings.HasSuffix(source, ".tar"):
+		return extractTarball(source, themeDir, false)
+	default:
+		return fmt.Errorf("unsupported theme source %q, expected a directory or a .tar, .tar.gz or .tgz archive", source)
+	}
+}
+
+func copyDir(sourceDir string, targetDir string) error {
+	return filepath.WalkDir(sourceDir, func(sourcePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(sourceDir, sourcePath)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return fs.SkipDir
+		}
+		targetPath := filepath.Join(targetDir, relativePath)
+		if entry.IsDir() {
+			return utils.CreateDirIfNotExist(targetPath)
+		}
+		if !entry.Type().IsRegular() {
+			log.Warn().
+				Str("path", sourcePath).
+				Msg("Skipping non-regular file")
+			return nil
+		}
+		source, err := os.Open(sourcePath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = source.Close()
+		}()
+		return copyToFile(targetPath, source)
+	})
+}
+
+// extractTarball extracts the archive into targetDir, when all the files of the archive are in
+// a single top-level directory, as in GitHub archives, that directory is stripped
+func extractTarball(archivePath string, targetDir string, gzipped bool) error {
+	prefix, err := getTarballPrefix(archivePath, gzipped)
+	if err != nil {
+		return err
+	}
+	return readTarball(archivePath, gzipped, func(header *tar.Header, r io.Reader) error {
+		name := path.Clean(header.Name)
+		if name+"/" == prefix {
+			return nil
+		}
+		name = strings.TrimPrefix(name, prefix)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid file path in theme archive: %q", header.Name)
+		}
+		targetPath := filepath.Join(targetDir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			return utils.CreateDirIfNotExist(targetPath)
+		case tar.TypeReg:
+			if err := utils.CreateDirIfNotExist(filepath.Dir(targetPath)); err != nil {
+				return err
+			}
+			return copyToFile(targetPath, r)
+		default:
+			log.Warn().
+				Str("path", header.Name).
+				Msg("Skipping non-regular file in theme archive")
+			return nil
+		}
+	})
+}
+
+func getTarballPrefix(archivePath string, gzipped bool) (string, error) {
+	topLevelNames := make(map[string]bool)
+	hasTopLevelFile := false
+	err := readTarball(archivePath, gzipped, func(header *tar.Header, _ io.Reader) error {
+		topLevelName, _, found := strings.Cut(path.Clean(header.Name), "/")
+		if !found && header.Typeflag != tar.TypeDir {
+			hasTopLevelFile = true
+		}
+		topLevelNames[topLevelName] = true
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(topLevelNames) != 1 || hasTopLevelFile {
+		return "", nil
+	}
+	for topLevelName := range topLevelNames {
+		return topLevelName + "/", nil
+	}
+	return "", nil
+}
+
+func readTarball(archivePath string, gzipped bool, onFile func(header *tar.Header, r io.Reader) error) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening theme archive: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var r io.Reader = file
+	if gzipped {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("error reading theme archive: %w", err)
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+		r = gzipReader
+	}
+
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading theme archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			// e.g. the commit ID GitHub adds to its archives
+			continue
+		}
+		if err := onFile(header, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+func copyToFile(targetPath string, r io.Reader) error {
+	w, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", targetPath, err)
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("error writing %s: %w", targetPath, err)
+	}
+	return w.Close()
+}
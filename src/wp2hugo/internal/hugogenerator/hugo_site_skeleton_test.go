@@ -0,0 +1,150 @@
+package hugogenerator
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateOffline(t *testing.T) {
+	t.Parallel()
+	// A minimal PaperMod
+	themeSource := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(themeSource, ".git"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(themeSource, "layouts/partials"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(themeSource, "layouts/_default"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(themeSource, "assets/css/extended"), 0o755))
+	require.NoError(t, writeFile(filepath.Join(themeSource, "layouts/_default/rss.xml"), []byte("<guid>{{ .Permalink }}</guid>")))
+
+	file, err := os.Open("./testdata/testcase.WordPress_2.xml")
+	require.NoError(t, err)
+	websiteInfo, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	outputDir := t.TempDir()
+	generator := NewGenerator(outputDir, "Lexend", nil, false, false, false, false, ContentDateFolderStructureFlat, *websiteInfo)
+	generator.SetOffline(true)
+	generator.SetThemeSource(themeSource)
+	require.NoError(t, generator.Generate(context.Background()))
+
+	siteDirs, err := filepath.Glob(path.Join(outputDir, "generated-*"))
+	require.NoError(t, err)
+	require.Len(t, siteDirs, 1)
+	siteDir := siteDirs[0]
+
+	config, err := os.ReadFile(path.Join(siteDir, "hugo.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(config), "theme: PaperMod")
+	require.FileExists(t, path.Join(siteDir, "archetypes", "default.md"))
+	require.NoDirExists(t, path.Join(siteDir, "themes", "PaperMod", ".git"))
+	require.FileExists(t, path.Join(siteDir, "layouts", "rss.xml"))
+	require.FileExists(t, path.Join(siteDir, _outputHeadFile))
+
+	posts, err := filepath.Glob(path.Join(siteDir, "content", "posts", "*.md"))
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+}
+
+func TestGenerateOffline_RequiresThemeSource(t *testing.T) {
+	t.Parallel()
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, ContentDateFolderStructureFlat, wpparser.WebsiteInfo{})
+	generator.SetOffline(true)
+	require.ErrorContains(t, generator.Generate(context.Background()), "cannot be downloaded in offline mode")
+}
+
+func TestInstallThemeFromSource_Tarball(t *testing.T) {
+	t.Parallel()
+	// Same layout as the archives of GitHub
+	archivePath := writeTestTarball(t, map[string]string{
+		"hugo-PaperMod-master/theme.toml":               "name = 'PaperMod'",
+		"hugo-PaperMod-master/layouts/_default/rss.xml": "<rss/>",
+	})
+
+	siteDir := t.TempDir()
+	require.NoError(t, installThemeFromSource(siteDir, "PaperMod", archivePath))
+	data, err := os.ReadFile(path.Join(siteDir, "themes", "PaperMod", "layouts", "_default", "rss.xml"))
+	require.NoError(t, err)
+	require.Equal(t, "<rss/>", string(data))
+	require.FileExists(t, path.Join(siteDir, "themes", "PaperMod", "theme.toml"))
+}
+
+func TestInstallThemeFromSource_TarballOutsideOfThemeDir(t *testing.T) {
+	t.Parallel()
+	archivePath := writeTestTarball(t, map[string]string{
+		"theme.toml":    "name = 'PaperMod'",
+		"../escape.txt": "",
+	})
+	require.ErrorContains(t, installThemeFromSource(t.TempDir(), "PaperMod", archivePath), "invalid file path")
+}
+
+func writeTestTarball(t *testing.T, files map[string]string) string {
+	t.Helper()
+	archivePath := path.Join(t.TempDir(), "theme.tar.gz")
+	file, err := os.Create(archivePath)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, file.Close())
+	return archivePath
+}
+
+func TestGenerateOffline_TOML(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/testcase.WordPress_2.xml")
+	require.NoError(t, err)
+	websiteInfo, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	outputDir := t.TempDir()
+	generator := NewGenerator(outputDir, "", nil, false, false, false, false, ContentDateFolderStructureFlat, *websiteInfo)
+	generator.SetOffline(true)
+	generator.SetTheme(noTheme{})
+	generator.SetFrontMatterFormat(utils.FormatTOML)
+	require.NoError(t, generator.Generate(context.Background()))
+
+	siteDirs, err := filepath.Glob(path.Join(outputDir, "generated-*"))
+	require.NoError(t, err)
+	require.Len(t, siteDirs, 1)
+	siteDir := siteDirs[0]
+
+	config, err := os.ReadFile(path.Join(siteDir, "hugo.toml"))
+	require.NoError(t, err)
+	require.Contains(t, string(config), "baseURL = ")
+	require.NoFileExists(t, path.Join(siteDir, "hugo.yaml"))
+	require.FileExists(t, path.Join(siteDir, "data", "library.toml"))
+
+	posts, err := filepath.Glob(path.Join(siteDir, "content", "posts", "*.md"))
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	post, err := os.ReadFile(posts[0])
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(post), "+++\n"))
+	require.Contains(t, string(post), `post_id = "22887"`)
+
+	comments, err := os.ReadFile(path.Join(siteDir, "data", "comments.toml"))
+	require.NoError(t, err)
+	items, err := unmarshalDataList[wpparser.CommentInfo](utils.FormatTOML, comments)
+	require.NoError(t, err)
+	require.Len(t, items, len(websiteInfo.Posts()[0].Comments))
+}
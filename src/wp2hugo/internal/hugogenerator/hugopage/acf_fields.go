@@ -0,0 +1,241 @@
+package hugopage
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Value of the meta _name of a field, e.g. field_64a1b2c3d4e5f, or any key of the fields registered in PHP
+var _acfFieldKeyRegEx = regexp.MustCompile(`^field_\w+$`)
+
+// ACFFields converts the post meta of the fields of Advanced Custom Fields to typed, nested front matter
+// Ref: https://www.advancedcustomfields.com/resources/
+type ACFFields struct {
+	// Top-level fields by key
+	fields map[string]wpparser.ACFField
+	// URLs of the files of the attachments by post ID
+	attachmentURLs map[string]string
+	// Optional, resolves the relationships to the URLs of the Hugo pages
+	links *LinkResolver
+}
+
+// acfFieldValues are the fields converted from the meta of a page
+type acfFieldValues struct {
+	// Top-level fields by name
+	values map[string]any
+	// Meta keys of the references and sub fields, which are not written as is
+	consumed map[string]bool
+	// Media of the image, file and gallery fields
+	media []string
+}
+
+// NewACFFields returns the converter of the fields of groups, the media are the files of attachments
+// and the relationships are resolved by links
+func NewACFFields(groups []wpparser.ACFFieldGroup, attachments []wpparser.AttachmentInfo, links *LinkResolver) *ACFFields {
+	a := &ACFFields{
+		fields:         make(map[string]wpparser.ACFField),
+		attachmentURLs: make(map[string]string),
+		links:          links,
+	}
+	for _, group := range groups {
+		for _, field := range group.Fields {
+			a.fields[field.Key] = field
+		}
+	}
+	for _, attachment := range attachments {
+		if fileURL := attachment.GetAttachmentURL(); fileURL != nil {
+			a.attachmentURLs[attachment.PostID] = *fileURL
+		}
+	}
+	return a
+}
+
+// convert returns the fields of customMetaData. Without the definition of a field, its value is
+// left as is but its reference is dropped
+func (a *ACFFields) convert(customMetaData []wpparser.CustomMetaDatum, hostName string) acfFieldValues {
+	result := acfFieldValues{
+		values:   make(map[string]any),
+		consumed: make(map[string]bool),
+	}
+	metas := make(map[string]string, len(customMetaData))
+	for _, metadatum := range customMetaData {
+		metas[metadatum.Key] = metadatum.Value
+	}
+	for _, metadatum := range customMetaData {
+		name, ok := strings.CutPrefix(metadatum.Key, "_")
+		if !ok || !_acfFieldKeyRegEx.MatchString(metadatum.Value) {
+			continue
+		}
+		if _, ok := metas[name]; !ok {
+			continue
+		}
+		result.consumed[metadatum.Key] = true
+		if a == nil {
+			continue
+		}
+		if field, ok := a.fields[metadatum.Value]; ok {
+			value := a.getValue(field, name, metas, &result, hostName)
+			// The top-level meta itself is written, with its converted value
+			delete(result.consumed, name)
+			result.values[name] = value
+		}
+	}
+	return result
+}
+
+// getValue returns the value of field, stored in the meta name
+func (a *ACFFields) getValue(field wpparser.ACFField, name string, metas map[string]string, result *acfFieldValues,
+	hostName string,
+) any {
+	result.consumed[name] = true
+	result.consumed["_"+name] = true
+	raw, ok := metas[name]
+	if !ok {
+		return nil
+	}
+
+	switch field.Type {
+	case "repeater":
+		count, _ := strconv.Atoi(raw)
+		rows := make([]any, 0, count)
+		for i := 0; i < count; i++ {
+			row := make(map[string]any)
+			for _, subField := range field.SubFields {
+				a.setSubValue(row, subField, fmt.Sprintf("%s_%d_%s", name, i, subField.Name), metas, result, hostName)
+			}
+			rows = append(rows, row)
+		}
+		return rows
+	case "group":
+		group := make(map[string]any)
+		for _, subField := range field.SubFields {
+			a.setSubValue(group, subField, name+"_"+subField.Name, metas, result, hostName)
+		}
+		return group
+	case "flexible_content":
+		// E.g. a:2:{i:0;s:4:"hero";i:1;s:4:"text";}
+		layouts := getPHPArrayValues(raw)
+		layoutKeys := getACFLayoutKeys(field)
+		rows := make([]any, 0, len(layouts))
+		for i, layout := range layouts {
+			row := map[string]any{"layout": layout}
+			for _, subField := range field.SubFields {
+				if subField.Setting("parent_layout") == layoutKeys[layout] {
+					a.setSubValue(row, subField, fmt.Sprintf("%s_%d_%s", name, i, subField.Name), metas, result, hostName)
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows
+	case "image", "file":
+		return a.getMediaURL(raw, result, hostName)
+	case "gallery":
+		images := make([]any, 0)
+		for _, id := range getACFIDs(raw) {
+			if image := a.getMediaURL(id, result, hostName); image != nil {
+				images = append(images, image)
+			}
+		}
+		return images
+	case "relationship", "post_object", "page_link":
+		ids := getACFIDs(raw)
+		pages := make([]any, 0, len(ids))
+		for _, id := range ids {
+			pages = append(pages, a.getPageURL(id))
+		}
+		if field.Type != "relationship" && field.Setting("multiple") != "1" {
+			if len(pages) == 0 {
+				return nil
+			}
+			return pages[0]
+		}
+		return pages
+	case "true_false":
+		return raw == "1"
+	case "number", "range":
+		value, err := coerceMetaValue(raw, MetaTypeNumber)
+		if err != nil {
+			return raw
+		}
+		return value
+	case "date_picker", "date_time_picker":
+		value, err := coerceMetaValue(raw, MetaTypeDate)
+		if err != nil {
+			return raw
+		}
+		return value
+	}
+	// E.g. checkbox, link or google_map
+	return getMetaValue(wpparser.CustomMetaDatum{Key: name, Value: raw}, MetaTypeString)
+}
+
+func (a *ACFFields) setSubValue(values map[string]any, field wpparser.ACFField, name string, metas map[string]string,
+	result *acfFieldValues, hostName string,
+) {
+	if value := a.getValue(field, name, metas, result, hostName); value != nil {
+		values[field.Name] = value
+	}
+}
+
+// getMediaURL returns the URL of the attachment id, relative if it is on the website
+func (a *ACFFields) getMediaURL(id string, result *acfFieldValues, hostName string) any {
+	fileURL, ok := a.attachmentURLs[strings.TrimSpace(id)]
+	if !ok {
+		if id != "" {
+			log.Debug().
+				Str("attachmentID", id).
+				Msg("Attachment of ACF field not found")
+		}
+		return nil
+	}
+	if u, err := url.Parse(fileURL); err == nil && u.Host == hostName {
+		fileURL = u.Path
+	}
+	result.media = append(result.media, fileURL)
+	return fileURL
+}
+
+// getPageURL returns the URL of the page of the post ID id, id itself if it is not exported,
+// page_link fields also store URLs
+func (a *ACFFields) getPageURL(id string) string {
+	if pageURL, ok := a.links.pageURL(id); ok {
+		return pageURL
+	}
+	return id
+}
+
+// getACFIDs returns the post IDs of value, a single ID or a PHP serialized array of them
+func getACFIDs(value string) []string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "a:") {
+		return getPHPArrayValues(value)
+	}
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// getACFLayoutKeys returns the keys of the layouts of a flexible content field by name
+func getACFLayoutKeys(field wpparser.ACFField) map[string]string {
+	keys := make(map[string]string)
+	layouts, _ := field.Settings["layouts"].(map[string]any)
+	for key, layout := range layouts {
+		layout, ok := layout.(map[string]any)
+		if !ok {
+			continue
+		}
+		// Layouts are stored by key, e.g. layout_64a1b2c3d4e5f
+		if layoutKey, ok := layout["key"]; ok {
+			key = fmt.Sprint(layoutKey)
+		}
+		keys[fmt.Sprint(layout["name"])] = key
+	}
+	return keys
+}
@@ -0,0 +1,182 @@
+package hugopage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestACFFields(t *testing.T) *ACFFields {
+	t.Helper()
+	field := func(key string, name string, fieldType string, subFields ...wpparser.ACFField) wpparser.ACFField {
+		return wpparser.ACFField{
+			Key:       key,
+			Name:      name,
+			Type:      fieldType,
+			Settings:  map[string]any{"type": fieldType},
+			SubFields: subFields,
+		}
+	}
+	text := field("field_hero_title", "title", "text")
+	text.Settings["parent_layout"] = "layout_hero"
+	groups := []wpparser.ACFFieldGroup{{
+		Key: "group_product",
+		Fields: []wpparser.ACFField{
+			field("field_price", "price", "number"),
+			field("field_on_sale", "on_sale", "true_false"),
+			field("field_release", "release", "date_picker"),
+			field("field_photo", "photo", "image"),
+			field("field_gallery", "gallery", "gallery"),
+			field("field_related", "related", "relationship"),
+			field("field_features", "features", "repeater",
+				field("field_feature_label", "label", "text"),
+				field("field_feature_icon", "icon", "image")),
+			field("field_dimensions", "dimensions", "group",
+				field("field_width", "width", "number"),
+				field("field_height", "height", "number")),
+			{
+				Key:  "field_sections",
+				Name: "sections",
+				Type: "flexible_content",
+				Settings: map[string]any{
+					"type": "flexible_content",
+					"layouts": map[string]any{
+						"layout_hero": map[string]any{"key": "layout_hero", "name": "hero"},
+						"layout_text": map[string]any{"key": "layout_text", "name": "text"},
+					},
+				},
+				SubFields: []wpparser.ACFField{text},
+			},
+		},
+	}}
+	a := NewACFFields(groups, nil, newTestLinkResolver(t, false))
+	a.attachmentURLs["10"] = "https://www.example.com/wp-content/uploads/2024/01/photo.jpg"
+	a.attachmentURLs["11"] = "https://cdn.example.net/icon.png"
+	return a
+}
+
+func TestACFFields_Convert(t *testing.T) {
+	t.Parallel()
+	metas := []wpparser.CustomMetaDatum{
+		{Key: "price", Value: "12.5"},
+		{Key: "_price", Value: "field_price"},
+		{Key: "on_sale", Value: "1"},
+		{Key: "_on_sale", Value: "field_on_sale"},
+		{Key: "release", Value: "20240102"},
+		{Key: "_release", Value: "field_release"},
+		{Key: "photo", Value: "10"},
+		{Key: "_photo", Value: "field_photo"},
+		{Key: "gallery", Value: `a:3:{i:0;s:2:"11";i:1;s:2:"99";i:2;s:2:"10";}`},
+		{Key: "_gallery", Value: "field_gallery"},
+		{Key: "related", Value: `a:2:{i:0;s:2:"45";i:1;s:3:"999";}`},
+		{Key: "_related", Value: "field_related"},
+		{Key: "features", Value: "2"},
+		{Key: "_features", Value: "field_features"},
+		{Key: "features_0_label", Value: "Waterproof"},
+		{Key: "_features_0_label", Value: "field_feature_label"},
+		{Key: "features_0_icon", Value: "11"},
+		{Key: "_features_0_icon", Value: "field_feature_icon"},
+		{Key: "features_1_label", Value: "Light"},
+		{Key: "_features_1_label", Value: "field_feature_label"},
+		{Key: "dimensions", Value: ""},
+		{Key: "_dimensions", Value: "field_dimensions"},
+		{Key: "dimensions_width", Value: "20"},
+		{Key: "_dimensions_width", Value: "field_width"},
+		{Key: "dimensions_height", Value: "30"},
+		{Key: "_dimensions_height", Value: "field_height"},
+		{Key: "sections", Value: `a:2:{i:0;s:4:"hero";i:1;s:4:"text";}`},
+		{Key: "_sections", Value: "field_sections"},
+		{Key: "sections_0_title", Value: "Welcome"},
+		{Key: "_sections_0_title", Value: "field_hero_title"},
+		{Key: "unknown", Value: "kept"},
+		{Key: "_unknown", Value: "field_deleted"},
+		{Key: "subtitle", Value: "Not a field"},
+	}
+
+	fields := newTestACFFields(t).convert(metas, "www.example.com")
+	require.Equal(t, map[string]any{
+		"price":   12.5,
+		"on_sale": true,
+		"release": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"photo":   "/wp-content/uploads/2024/01/photo.jpg",
+		"gallery": []any{"https://cdn.example.net/icon.png", "/wp-content/uploads/2024/01/photo.jpg"},
+		"related": []any{"/about/team/", "999"},
+		"features": []any{
+			map[string]any{"label": "Waterproof", "icon": "https://cdn.example.net/icon.png"},
+			map[string]any{"label": "Light"},
+		},
+		"dimensions": map[string]any{"width": int64(20), "height": int64(30)},
+		"sections": []any{
+			map[string]any{"layout": "hero", "title": "Welcome"},
+			map[string]any{"layout": "text"},
+		},
+	}, fields.values)
+	require.Equal(t, []string{
+		"/wp-content/uploads/2024/01/photo.jpg",
+		"https://cdn.example.net/icon.png",
+		"/wp-content/uploads/2024/01/photo.jpg",
+		"https://cdn.example.net/icon.png",
+	}, fields.media)
+
+	for _, key := range []string{"_price", "features_0_label", "_features_0_label", "dimensions_width", "_unknown"} {
+		require.True(t, fields.consumed[key], key)
+	}
+	for _, key := range []string{"price", "features", "unknown", "subtitle"} {
+		require.False(t, fields.consumed[key], key)
+	}
+}
+
+func TestSetCustomMetadata_ACFFields(t *testing.T) {
+	t.Parallel()
+	metas := []wpparser.CustomMetaDatum{
+		{Key: "price", Value: "12.5"},
+		{Key: "_price", Value: "field_price"},
+		{Key: "photo", Value: "10"},
+		{Key: "_photo", Value: "field_photo"},
+		{Key: "on_sale", Value: "1"},
+		{Key: "_on_sale", Value: "field_on_sale"},
+	}
+	policy := MetaPolicy{Deny: []string{"on_sale"}, Rename: map[string]string{"photo": "image"}}
+	metadata := make(map[string]any)
+	media := setCustomMetadata(metadata, metas, Options{MetaPolicy: policy, ACFFields: newTestACFFields(t)},
+		"www.example.com")
+	require.Equal(t, map[string]any{
+		"price": 12.5,
+		"image": "/wp-content/uploads/2024/01/photo.jpg",
+	}, metadata)
+	require.Equal(t, []string{"/wp-content/uploads/2024/01/photo.jpg"}, media)
+
+	// Without field definitions, only the references are dropped
+	metadata = make(map[string]any)
+	setCustomMetadata(metadata, metas, Options{MetaPolicy: DefaultMetaPolicy()}, "www.example.com")
+	require.Equal(t, map[string]any{"price": "12.5", "photo": "10", "on_sale": "1"}, metadata)
+}
+
+func TestACFFields_FlexibleContentOrder(t *testing.T) {
+	t.Parallel()
+	// PHP arrays of 11 rows or more are not in the order of the string indexes: 0, 1, 10, 11, 2...
+	const count = 12
+	layouts := ""
+	metas := []wpparser.CustomMetaDatum{{Key: "_sections", Value: "field_sections"}}
+	expected := make([]any, 0, count)
+	for i := 0; i < count; i++ {
+		if i%3 == 0 {
+			layouts += fmt.Sprintf(`i:%d;s:4:"hero";`, i)
+			title := fmt.Sprintf("Hero %d", i)
+			metas = append(metas,
+				wpparser.CustomMetaDatum{Key: fmt.Sprintf("sections_%d_title", i), Value: title},
+				wpparser.CustomMetaDatum{Key: fmt.Sprintf("_sections_%d_title", i), Value: "field_hero_title"})
+			expected = append(expected, map[string]any{"layout": "hero", "title": title})
+		} else {
+			layouts += fmt.Sprintf(`i:%d;s:4:"text";`, i)
+			expected = append(expected, map[string]any{"layout": "text"})
+		}
+	}
+	metas = append(metas, wpparser.CustomMetaDatum{Key: "sections", Value: fmt.Sprintf("a:%d:{%s}", count, layouts)})
+
+	fields := newTestACFFields(t).convert(metas, "www.example.com")
+	require.Equal(t, expected, fields.values["sections"])
+}
@@ -0,0 +1,72 @@
+package hugopage
+
+// FrontMatterMapper writes the front matter keys whose name and shape depend on the Hugo theme
+type FrontMatterMapper interface {
+	// SetCoverImage sets the featured image of the page
+	SetCoverImage(metadata map[string]any, imageURL string, alt string)
+	// SetTableOfContents shows the table of contents of the page
+	SetTableOfContents(metadata map[string]any)
+	// SetNoIndex keeps search engines from indexing the page
+	SetNoIndex(metadata map[string]any)
+}
+
+// PaperModFrontMatter maps front matter keys to the ones of the PaperMod theme
+// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-variables/
+type PaperModFrontMatter struct{}
+
+func (PaperModFrontMatter) SetCoverImage(metadata map[string]any, imageURL string, alt string) {
+	metadata["cover"] = map[string]string{
+		"image": imageURL,
+		"alt":   alt,
+	}
+}
+
+// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#show-table-of-contents-toc-on-blog-post
+func (PaperModFrontMatter) SetTableOfContents(metadata map[string]any) {
+	metadata["ShowToc"] = true
+	metadata["TocOpen"] = true
+}
+
+// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-variables/#page-variables
+func (PaperModFrontMatter) SetNoIndex(metadata map[string]any) {
+	metadata["robotsNoIndex"] = true
+	setNoSitemap(metadata)
+}
+
+// AnankeFrontMatter maps front matter keys to the ones of the Ananke theme
+// Ref: https://github.com/theNewDynamic/gohugo-theme-ananke#readme
+type AnankeFrontMatter struct{}
+
+func (AnankeFrontMatter) SetCoverImage(metadata map[string]any, imageURL string, _ string) {
+	metadata["featured_image"] = imageURL
+}
+
+func (AnankeFrontMatter) SetTableOfContents(metadata map[string]any) {
+	metadata["toc"] = true
+}
+
+func (AnankeFrontMatter) SetNoIndex(metadata map[string]any) {
+	setNoSitemap(metadata)
+}
+
+// HugoFrontMatter only uses the keys known to Hugo itself, for sites without a theme
+type HugoFrontMatter struct{}
+
+// Used by Hugo's embedded Open Graph and Twitter Cards templates
+// Ref: https://gohugo.io/templates/embedded/#open-graph
+func (HugoFrontMatter) SetCoverImage(metadata map[string]any, imageURL string, _ string) {
+	metadata["images"] = []string{imageURL}
+}
+
+func (HugoFrontMatter) SetTableOfContents(metadata map[string]any) {
+	metadata["toc"] = true
+}
+
+func (HugoFrontMatter) SetNoIndex(metadata map[string]any) {
+	setNoSitemap(metadata)
+}
+
+// Ref: https://gohugo.io/methods/page/sitemap/
+func setNoSitemap(metadata map[string]any) {
+	metadata["sitemap"] = map[string]any{"disable": true}
+}
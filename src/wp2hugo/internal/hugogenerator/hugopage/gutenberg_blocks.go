@@ -0,0 +1,349 @@
+package hugopage
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/html"
+)
+
+// Delimiter of a Gutenberg block, e.g. <!-- wp:image {"id":3875} -->, <!-- /wp:image --> or <!-- wp:jetpack/map /-->
+// JSON attributes end at the first "}" followed by the end of the comment, WordPress escapes "-->" in them
+// Ref: https://developer.wordpress.org/block-editor/explanations/architecture/data-flow/#serialization-and-parsing
+var _blockDelimiter = regexp.MustCompile(`(?s)<!--\s+(/)?wp:([a-z][a-z0-9_-]*/)?([a-z][a-z0-9_-]*)\s+(\{.*?\}\s+)?(/)?-->`)
+
+// Block is a Gutenberg block, blocks without delimiters are HTML of the classic editor and have no name
+type Block struct {
+	// E.g. "core/image", namespaced as in WordPress
+	Name string
+	// JSON attributes of the opening delimiter, numbers are json.Number
+	Attrs       map[string]any
+	InnerBlocks []*Block
+
+	// HTML around the inner blocks, innerHTML[i] precedes InnerBlocks[i]
+	innerHTML []string
+	// Delimiters as in the source, closer is empty for void blocks
+	opener string
+	closer string
+}
+
+// ParseBlocks parses htmlContent into a tree of blocks, HTML outside of blocks ends up in blocks without name.
+// Like WordPress, it never fails: unclosed blocks end with the content and unexpected closers are kept as HTML
+func ParseBlocks(htmlContent string) []*Block {
+	root := &Block{innerHTML: []string{""}}
+	stack := []*Block{root}
+	pos := 0
+	for _, match := range _blockDelimiter.FindAllStringSubmatchIndex(htmlContent, -1) {
+		top := stack[len(stack)-1]
+		top.appendHTML(htmlContent[pos:match[0]])
+		pos = match[1]
+
+		delimiter := htmlContent[match[0]:match[1]]
+		isCloser := match[2] >= 0
+		if isCloser {
+			if len(stack) == 1 {
+				log.Debug().
+					Str("delimiter", delimiter).
+					Msg("Block closer without opener")
+				top.appendHTML(delimiter)
+				continue
+			}
+			top.closer = delimiter
+			stack = stack[:len(stack)-1]
+			stack[len(stack)-1].appendBlock(top)
+			continue
+		}
+
+		namespace := "core/"
+		if match[4] >= 0 {
+			namespace = htmlContent[match[4]:match[5]]
+		}
+		block := &Block{
+			Name:      namespace + htmlContent[match[6]:match[7]],
+			innerHTML: []string{""},
+			opener:    delimiter,
+		}
+		if match[8] >= 0 {
+			block.Attrs = parseBlockAttrs(block.Name, htmlContent[match[8]:match[9]])
+		}
+		if match[10] >= 0 {
+			// Void block
+			top.appendBlock(block)
+		} else {
+			stack = append(stack, block)
+		}
+	}
+	stack[len(stack)-1].appendHTML(htmlContent[pos:])
+	for len(stack) > 1 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		stack[len(stack)-1].appendBlock(top)
+	}
+
+	// HTML between the top-level blocks becomes blocks of its own
+	var blocks []*Block
+	for i, part := range root.innerHTML {
+		if part != "" {
+			blocks = append(blocks, &Block{innerHTML: []string{part}})
+		}
+		if i < len(root.InnerBlocks) {
+			blocks = append(blocks, root.InnerBlocks[i])
+		}
+	}
+	return blocks
+}
+
+func parseBlockAttrs(name string, attrsJSON string) map[string]any {
+	decoder := json.NewDecoder(strings.NewReader(attrsJSON))
+	decoder.UseNumber()
+	var attrs map[string]any
+	if err := decoder.Decode(&attrs); err != nil {
+		log.Warn().
+			Err(err).
+			Str("block", name).
+			Msg("Invalid block attributes")
+		return nil
+	}
+	return attrs
+}
+
+func (b *Block) appendHTML(part string) {
+	b.innerHTML[len(b.innerHTML)-1] += part
+}
+
+func (b *Block) appendBlock(block *Block) {
+	b.InnerBlocks = append(b.InnerBlocks, block)
+	b.innerHTML = append(b.innerHTML, "")
+}
+
+// InnerHTML returns the HTML of the block, without its inner blocks
+func (b *Block) InnerHTML() string {
+	return strings.Join(b.innerHTML, "")
+}
+
+// AttrString returns the attribute key as a string, empty if it is not set or is not a scalar
+func (b *Block) AttrString(key string) string {
+	switch v := b.Attrs[key].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+// AttrInt returns the attribute key as an int, 0 if it is not set or is not a number
+func (b *Block) AttrInt(key string) int {
+	n, err := strconv.Atoi(b.AttrString(key))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// blockConverter converts block to HTML, which can contain Hugo shortcodes.
+// It returns false if the block cannot be converted, it is then kept as is
+type blockConverter func(r *blockRenderer, block *Block) (string, bool)
+
+// Converters by block name, blocks without converter are kept as is and reported
+var _blockConverters = map[string]blockConverter{
+	"core/image":   convertImageBlock,
+	"core/gallery": convertGalleryBlock,
+	"core/audio":   convertAudioBlock,
+	"core/video":   convertVideoBlock,
+	"core/embed":   convertEmbedBlock,
+
+	"core/columns":    convertColumnsBlock,
+	"core/column":     convertColumnBlock,
+	"core/media-text": convertMediaTextBlock,
+	"core/buttons":    convertButtonsBlock,
+	"core/button":     convertButtonBlock,
+	"core/cover":      convertCoverBlock,
+
+	"core/quote":     convertQuoteBlock,
+	"core/pullquote": convertQuoteBlock,
+	"core/details":   convertDetailsBlock,
+	"core/file":      convertFileBlock,
+	"core/table":     convertTableBlock,
+	"core/separator": convertSeparatorBlock,
+}
+
+// Blocks whose HTML has a Markdown equivalent
+var _htmlBlocks = []string{
+	"core/paragraph", "core/heading", "core/list", "core/list-item", "core/code", "core/preformatted",
+	"core/html", "core/freeform", "core/shortcode", "core/more", "core/nextpage", "core/spacer", "core/footnotes",
+}
+
+// blockRenderer converts blocks with the registered converters
+type blockRenderer struct {
+	converters map[string]blockConverter
+	options    Options
+	// Names of the blocks which have not been converted, in order of appearance
+	unconverted []string
+}
+
+func newBlockRenderer(options Options) *blockRenderer {
+	return &blockRenderer{converters: _blockConverters, options: options}
+}
+
+// convertBlocks converts the Gutenberg blocks of htmlContent, and returns the names of the blocks
+// which have been kept as is, see unconvertedBlockName
+func convertBlocks(htmlContent string, options Options) (string, []string) {
+	log.Debug().
+		Msg("Converting Gutenberg blocks")
+	r := newBlockRenderer(options)
+	var output strings.Builder
+	for _, block := range ParseBlocks(htmlContent) {
+		output.WriteString(r.render(block))
+	}
+	return output.String(), r.unconverted
+}
+
+func (r *blockRenderer) render(block *Block) string {
+	if block.Name == "" || slices.Contains(_htmlBlocks, block.Name) {
+		return r.keep(block)
+	}
+	converter, ok := r.converters[block.Name]
+	if !ok && strings.HasPrefix(block.Name, "core-embed/") {
+		// Before WordPress 5.6, embeds had a block per provider
+		converter, ok = r.converters["core/embed"]
+	}
+	if ok {
+		if output, converted := converter(r, block); converted {
+			return output
+		}
+	}
+	if name := unconvertedBlockName(block); !slices.Contains(r.unconverted, name) {
+		r.unconverted = append(r.unconverted, name)
+	}
+	return r.keep(block)
+}
+
+// keep returns the block as is, with its inner blocks converted
+func (r *blockRenderer) keep(block *Block) string {
+	return block.opener + r.renderInner(block) + block.closer
+}
+
+// renderInner returns the HTML of the block with its inner blocks converted, without its delimiters
+func (r *blockRenderer) renderInner(block *Block) string {
+	var output strings.Builder
+	for i, part := range block.innerHTML {
+		output.WriteString(part)
+		if i < len(block.InnerBlocks) {
+			output.WriteString(r.render(block.InnerBlocks[i]))
+		}
+	}
+	return output.String()
+}
+
+// renderInnerBlocks returns the inner blocks of block converted, without the HTML wrapping them,
+// e.g. <div class="wp-block-column"> of a column
+func (r *blockRenderer) renderInnerBlocks(block *Block) string {
+	var output strings.Builder
+	for i, inner := range block.InnerBlocks {
+		if i > 0 {
+			output.WriteString(block.innerHTML[i])
+		}
+		output.WriteString(r.render(inner))
+	}
+	return output.String()
+}
+
+// unconvertedBlockName returns the name of block without the core namespace, embeds are reported
+// as "embed:<provider>"
+func unconvertedBlockName(block *Block) string {
+	if block.Name == "core/embed" || strings.HasPrefix(block.Name, "core-embed/") {
+		return "embed:" + getEmbedProvider(block)
+	}
+	return strings.TrimPrefix(block.Name, "core/")
+}
+
+// parseBlockHTML parses the HTML of a block, nil if it is invalid
+func parseBlockHTML(block *Block) *html.Node {
+	doc, err := html.Parse(strings.NewReader(block.InnerHTML()))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("block", block.Name).
+			Msg("Invalid block HTML")
+		return nil
+	}
+	return doc
+}
+
+// findElement returns the first element named tag in node, nil if there is none
+func findElement(node *html.Node, tag string) *html.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Type == html.ElementNode && node.Data == tag {
+		return node
+	}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// hasClass returns true if node has the CSS class
+func hasClass(node *html.Node, class string) bool {
+	return slices.Contains(strings.Fields(getAttr(node, "class")), class)
+}
+
+// findElementWithClass returns the first element of node with the CSS class, nil if there is none
+func findElementWithClass(node *html.Node, class string) *html.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Type == html.ElementNode && hasClass(node, class) {
+		return node
+	}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElementWithClass(c, class); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// getText returns the text of node, without its tags
+func getText(node *html.Node) string {
+	if node == nil {
+		return ""
+	}
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var b strings.Builder
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(getText(c))
+	}
+	return b.String()
+}
+
+func getAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// renderChildren returns the HTML inside of node
+func renderChildren(node *html.Node) string {
+	var b strings.Builder
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(renderNode(c))
+	}
+	return strings.ReplaceAll(b.String(), "&#39;", "'")
+}
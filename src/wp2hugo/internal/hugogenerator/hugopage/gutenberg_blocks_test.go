@@ -0,0 +1,110 @@
+package hugopage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBlocks(t *testing.T) {
+	t.Parallel()
+	const content = `<p>Classic</p>
+<!-- wp:columns {"style":{"spacing":{"blockGap":"1em"}},"className":"a } b"} -->
+<div class="wp-block-columns"><!-- wp:column -->
+<div class="wp-block-column"><!-- wp:paragraph --><p>One</p><!-- /wp:paragraph --></div>
+<!-- /wp:column --></div>
+<!-- /wp:columns -->
+<!-- wp:jetpack/map {"zoom":13} /-->
+<!-- /wp:paragraph -->
+<!-- wp:quote --><blockquote>Unclosed</blockquote>`
+
+	blocks := ParseBlocks(content)
+	names := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		names = append(names, block.Name)
+	}
+	require.Equal(t, []string{"", "core/columns", "", "jetpack/map", "", "core/quote"}, names)
+
+	columns := blocks[1]
+	require.Equal(t, "a } b", columns.AttrString("className"))
+	require.Equal(t, map[string]any{"spacing": map[string]any{"blockGap": "1em"}}, columns.Attrs["style"])
+	require.Len(t, columns.InnerBlocks, 1)
+	require.Equal(t, "core/column", columns.InnerBlocks[0].Name)
+	require.Equal(t, "core/paragraph", columns.InnerBlocks[0].InnerBlocks[0].Name)
+	require.Equal(t, "\n<div class=\"wp-block-column\"></div>\n", columns.InnerBlocks[0].InnerHTML())
+
+	require.Equal(t, json.Number("13"), blocks[3].Attrs["zoom"])
+	require.Equal(t, 13, blocks[3].AttrInt("zoom"))
+	require.Empty(t, blocks[3].InnerBlocks)
+	// Stray closer
+	require.Equal(t, "\n<!-- /wp:paragraph -->\n", blocks[4].InnerHTML())
+	require.Equal(t, "<blockquote>Unclosed</blockquote>", blocks[5].InnerHTML())
+
+	// Blocks without converter are kept as they are
+	converted, unconverted := convertBlocks(content, Options{})
+	require.Equal(t, `<p>Classic</p>
+<p>{{< columns >}}</p><p>{{< column >}}</p><!-- wp:paragraph --><p>One</p><!-- /wp:paragraph --><p>{{< /column >}}</p><p>{{< /columns >}}</p>
+<!-- wp:jetpack/map {"zoom":13} /-->
+<!-- /wp:paragraph -->
+<blockquote>Unclosed</blockquote>`, converted)
+	require.Equal(t, []string{"jetpack/map"}, unconverted)
+}
+
+func TestConvertBlocks_UnconvertedBlocks(t *testing.T) {
+	t.Parallel()
+	content := `<!-- wp:paragraph --><p>Text</p><!-- /wp:paragraph -->
+<!-- wp:embed {"url":"https://www.youtube.com/watch?v=abc","type":"video","providerNameSlug":"youtube"} --><!-- /wp:embed -->
+<!-- wp:embed {"url":"https://www.flickr.com/photos/a/1","type":"photo","providerNameSlug":"flickr"} --><!-- /wp:embed -->
+<!-- wp:core-embed/reddit {"url":"https://www.reddit.com/r/a/comments/1"} --><!-- /wp:core-embed/reddit -->
+<!-- wp:group --><!-- wp:social-link /--><!-- /wp:group -->
+<!-- wp:jetpack/contact-form /-->
+<!-- wp:group -->`
+	_, unconverted := convertBlocks(content, Options{})
+	require.Equal(t, []string{"embed:flickr", "embed:reddit", "group", "social-link", "jetpack/contact-form"}, unconverted)
+}
+
+func TestConvertMediaBlocks(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		content  string
+		expected string
+	}{
+		{
+			`<!-- wp:audio {"id":1} --><figure class="wp-block-audio"><audio controls src="/wp-content/uploads/a_b.mp3"></audio></figure><!-- /wp:audio -->`,
+			`{{< audio src="/wp-content/uploads/a%5Fb.mp3" >}}`,
+		},
+		{
+			`<!-- wp:video {"id":2} --><figure class="wp-block-video"><video controls src="/wp-content/uploads/v.mp4"></video></figure><!-- /wp:video -->`,
+			`{{< video src="/wp-content/uploads/v.mp4" >}}`,
+		},
+		{
+			`<!-- wp:core-embed/youtube {"url":"https://youtu.be/8K7PdBH3W_I"} --><figure>https://youtu.be/8K7PdBH3W_I</figure><!-- /wp:core-embed/youtube -->`,
+			`{{< youtube 8K7PdBH3W_I >}}`,
+		},
+		{
+			// Since WordPress 5.9
+			`<!-- wp:gallery {"columns":2,"linkTo":"none"} -->
+<figure class="wp-block-gallery has-nested-images columns-2"><!-- wp:image {"id":1} -->
+<figure class="wp-block-image"><img src="/a.jpg" alt="A"/></figure>
+<!-- /wp:image -->
+
+<!-- wp:image {"id":2} -->
+<figure class="wp-block-image"><img src="/b.jpg" alt=""/><figcaption class="wp-element-caption">Bee</figcaption></figure>
+<!-- /wp:image --></figure>
+<!-- /wp:gallery -->`,
+			`<br>{{< gallery cols="2" >}}<br>{{< figure src="/a.jpg" alt="A" caption="A" >}}<br>{{< figure src="/b.jpg" alt="Bee" caption="Bee" >}}<br>{{< /gallery >}}<br>`,
+		},
+	}
+	for _, testCase := range testCases {
+		converted, unconverted := convertBlocks(testCase.content, Options{})
+		require.Equal(t, testCase.expected, converted)
+		require.Empty(t, unconverted)
+	}
+}
+
+func TestUnescapeHugoShortcodes(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "a\\_b {{< youtube 8K7PdBH3W_I >}} {{< figure alt=\"*bold*\" >}}",
+		unescapeHugoShortcodes("a\\_b {{< youtube 8K7PdBH3W\\_I >}} {{< figure alt=\"\\*bold\\*\" >}}"))
+}
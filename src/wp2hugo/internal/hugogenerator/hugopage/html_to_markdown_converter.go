@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/JohannesKaufmann/html-to-markdown/plugin"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/rs/zerolog/log"
 )
@@ -17,9 +18,15 @@ var (
 	gistMarkdown = regexp.MustCompile(`\\\[gist .*\]`)
 )
 
-func getMarkdownConverter() *md.Converter {
+func getMarkdownConverter(options Options) *md.Converter {
 	converter := md.NewConverter("", true, nil)
-	converter.Use(getYouTubeForHugoConverter())
+	converter.Use(plugin.Table())
+	converter.Use(getRawTextConverter())
+	// Rules added last are tried first
+	converter.Use(getEmbedIframeConverter(options.EmbedPrivacy))
+	if !options.EmbedPrivacy {
+		converter.Use(getYouTubeForHugoConverter())
+	}
 	converter.Use(getGoogleMapsEmbedForHugoConverter())
 	converter.Use(convertCustomBRToNewline())
 	converter.Use(convertBrToNewline())
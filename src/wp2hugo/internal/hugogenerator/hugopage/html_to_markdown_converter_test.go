@@ -30,7 +30,7 @@ some more text
 
 func TestIframe(t *testing.T) {
 	t.Parallel()
-	converter := getMarkdownConverter()
+	converter := getMarkdownConverter(Options{})
 	result, err := converter.ConvertString(_textWithIframe)
 	require.NoError(t, err)
 	require.Contains(t, result, `{{< googlemaps src="1lcjyzfxxXcdDP3XkrikfqIJryfFi4ZA" width=640 height=480 >}}`)
@@ -38,7 +38,7 @@ func TestIframe(t *testing.T) {
 
 func TestBlockGist(t *testing.T) {
 	t.Parallel()
-	converter := getMarkdownConverter()
+	converter := getMarkdownConverter(Options{})
 	result, err := converter.ConvertString(_textWithBlockGist)
 	require.NoError(t, err)
 	require.Contains(t, result, `{{< gist lawrencegripper 8e701b0d201e65af0f8bc9b8b0b14207 >}}`)
@@ -46,7 +46,7 @@ func TestBlockGist(t *testing.T) {
 
 func TestBlockGistDoesNotBreakImgParsing(t *testing.T) {
 	t.Parallel()
-	converter := getMarkdownConverter()
+	converter := getMarkdownConverter(Options{})
 	result, err := converter.ConvertString(_textWithImgFigureBlock)
 	require.NoError(t, err)
 	require.Equal(t, `[![](https://blog.gripdev.xyz/wp-content/uploads/2024/03/image.png?w=1024)](https://blog.gripdev.xyz/wp-content/uploads/2024/03/image.png)`, result)
@@ -54,7 +54,7 @@ func TestBlockGistDoesNotBreakImgParsing(t *testing.T) {
 
 func TestMarkdownGist(t *testing.T) {
 	t.Parallel()
-	converter := getMarkdownConverter()
+	converter := getMarkdownConverter(Options{})
 	result, err := converter.ConvertString(_textMarkdownGist)
 	require.NoError(t, err)
 	require.Contains(t, result, `{{< gist lawrencegripper 6bee7de123bea1936359 >}}`)
@@ -1,15 +1,18 @@
 package hugopage
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"net/url"
 	"regexp"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
 	"github.com/go-enry/go-enry/v2"
@@ -27,6 +30,7 @@ const (
 
 	CategoryName = "categories"
 	TagName      = "tags"
+	AuthorName   = "authors"
 )
 
 type Page struct {
@@ -36,10 +40,52 @@ type Page struct {
 
 	metadata map[string]any
 	markdown string
+	// Media of the front matter, e.g. the cover image, to download along with the ones of the content
+	frontMatterMedia []string
+	// Gutenberg blocks left as is, see convertBlocks
+	unconvertedBlocks []string
+	// Internal links to items which are not exported, see LinkResolver
+	missingLinks []string
+	options      Options
 }
 
+// Options of the conversion of the HTML content to Markdown
+type Options struct {
+	// Replace the third-party embeds with a placeholder which loads them on click
+	EmbedPrivacy bool
+	// Conversions of the shortcodes of plugins, see LoadShortcodeRules
+	ShortcodeRules []ShortcodeRule
+	// Optional, rewrites the internal links to the URLs of the Hugo pages
+	LinkResolver *LinkResolver
+	// Summary of the pages having both an excerpt and a <!--more--> divider, one of Summary*
+	SummaryPolicy string
+	// Names of the categories by term ID, to find the primary category set by the SEO plugins
+	CategoryNames map[string]string
+	// Post meta written to the front matter, all of them as strings if zero
+	MetaPolicy MetaPolicy
+	// Optional, converts the fields of Advanced Custom Fields
+	ACFFields *ACFFields
+}
+
+// Summary of the pages having both an excerpt and a <!--more--> divider
+const (
+	// The excerpt is the summary, Hugo ignores the divider
+	SummaryExcerpt = "excerpt"
+	// The content before the divider is the summary, the excerpt is only the description
+	SummaryMore = "more"
+)
+
+func IsValidSummaryPolicy(policy string) bool {
+	return policy == SummaryExcerpt || policy == SummaryMore
+}
+
+// WordPress and Hugo share the summary divider
+// Ref: https://gohugo.io/content-management/summaries/#manual-summary
 const _WordPressMoreTag = "<!--more-->"
 
+// E.g. <!--more Continue reading-->, Hugo has no custom text
+var _wordPressMoreTagRegEx = regexp.MustCompile(`<!--more(?:\s.*?)?-->`)
+
 // In the next step, we will replace this as well
 const (
 	_customMoreTag   = "{{< more >}}"
@@ -66,6 +112,9 @@ var (
 	// follow certain punctuation (e.g. `"<a>`, `(<a>`).
 	// Ref: https://github.com/JohannesKaufmann/html-to-markdown/issues/95
 	_extraSpaceBeforeLinkRegex = regexp.MustCompile(`([\"'(]) \[`)
+
+	// Markdown escapes within Hugo shortcodes, e.g. {{< youtube 8K7PdBH3W\_I >}}
+	_hugoShortcodeEscapes = regexp.MustCompile(`\\([\\*_\[\]#+\-.!>|~` + "`" + `])`)
 )
 
 // Extracts "src" from Hugo figure shortcode
@@ -80,33 +129,47 @@ var _hugoAudioLinks = regexp.MustCompile(`{{< audio.*?src="([^\"]+?)".*? >}}`)
 // {{< video src="/wp-content/uploads/2026/05/video.mp4" >}}
 var _hugoVideoLinks = regexp.MustCompile(`{{< video.*?src="([^\"]+?)".*? >}}`)
 
+// Extracts "src" from Hugo cover and file shortcodes
+// {{< cover src="/wp-content/uploads/2024/01/header.jpg" dim="50" >}}
+// {{< file src="/wp-content/uploads/2024/01/report.zip" name="Report" >}}
+var (
+	_hugoCoverLinks = regexp.MustCompile(`{{< cover [^>]*?src="([^\"]+?)"`)
+	_hugoFileLinks  = regexp.MustCompile(`{{< file [^>]*?src="([^\"]+?)"`)
+)
+
 // {{< parallaxblur src="/wp-content/uploads/2018/12/bora%5Fbora%5F5%5Fresized.jpg" >}}
 var _hugoParallaxBlurLinks = regexp.MustCompile(`{{< parallaxblur.*?src="([^\"]+?)".*? >}}`)
 
-func NewPage(provider ImageURLProvider, pageURL url.URL, author string, title string, publishDate *time.Time,
+func NewPage(provider ImageURLProvider, pageURL url.URL, authors []wpparser.AuthorInfo, title string, publishDate *time.Time,
 	isDraft bool, categories []string, tags []string, attachments []wpparser.AttachmentInfo,
 	footnotes []wpparser.Footnote,
-	htmlContent string, guid *rss.GUID, featuredImageID *string, postFormat *string,
+	htmlContent string, excerpt string, guid *rss.GUID, featuredImageID *string, postFormat *string,
 	customMetaData []wpparser.CustomMetaDatum, taxinomies []wpparser.TaxonomyInfo,
-	postID string, parentPostID *string,
+	postID string, parentPostID *string, frontMatter FrontMatterMapper, options Options,
 ) (*Page, error) {
-	metadata, err := getMetadata(provider, pageURL, author, title, publishDate, isDraft, categories, tags, guid,
-		featuredImageID, postFormat, customMetaData, taxinomies, postID, parentPostID)
+	metadata, frontMatterMedia, err := getMetadata(provider, pageURL, authors, title, publishDate, isDraft, categories, tags, guid,
+		featuredImageID, postFormat, customMetaData, taxinomies, postID, parentPostID, frontMatter, options)
 	if err != nil {
 		return nil, err
 	}
 	page := Page{
-		absoluteURL: pageURL,
-		metadata:    metadata,
-		attachments: attachments,
+		absoluteURL:      pageURL,
+		metadata:         metadata,
+		attachments:      attachments,
+		frontMatterMedia: frontMatterMedia,
+		options:          options,
 	}
+	page.frontMatterMedia = append(page.frontMatterMedia, page.setSEOMetadata(customMetaData, frontMatter)...)
 	// htmlContent is the HTML content of the page that will be
 	// transformed to Markdown
-	markdown, err := page.getMarkdown(provider, htmlContent, footnotes)
+	markdown, err := page.getMarkdown(provider, htmlContent, footnotes, frontMatter)
 	if err != nil {
 		return nil, err
 	}
 	page.markdown = *markdown
+	if err := page.setExcerpt(excerpt); err != nil {
+		return nil, err
+	}
 	return &page, nil
 }
 
@@ -114,14 +177,31 @@ func (page *Page) Markdown() string {
 	return page.markdown
 }
 
+// UnconvertedBlocks returns the names of the Gutenberg blocks which have been kept as is, in order of appearance.
+// Embeds are reported as "embed:<provider>"
+func (page *Page) UnconvertedBlocks() []string {
+	return page.unconvertedBlocks
+}
+
+// MissingLinks returns the links to items of the WordPress website which are not exported
+func (page *Page) MissingLinks() []string {
+	return page.missingLinks
+}
+
+// Metadata returns the front matter of the page
+func (page *Page) Metadata() map[string]any {
+	return page.metadata
+}
+
 func (page *Page) Replace(replacementMap map[string]string) {
 	for old, new := range replacementMap {
 		page.markdown = strings.ReplaceAll(page.markdown, old, new)
 	}
 }
 
-func (page Page) Write(w io.Writer) error {
-	if err := page.writeMetadata(w); err != nil {
+// Write writes the page with its front matter in format, see utils.FormatYAML
+func (page Page) Write(w io.Writer, format string) error {
+	if err := page.writeMetadata(w, format); err != nil {
 		return err
 	}
 	if err := page.writeContent(w); err != nil {
@@ -137,12 +217,9 @@ func (page *Page) WPMediaLinks() []string {
 	arr4 := getMarkdownLinks(_hugoAudioLinks, page.markdown)
 	arr5 := getPDFLinks([]byte(page.markdown))
 	arr6 := getMarkdownLinks(_hugoVideoLinks, page.markdown)
-	coverImageURL := page.getCoverImageURL()
-	result := append(append(append(append(append(arr1, arr2...), arr3...), arr4...), arr5...), arr6...)
-	if coverImageURL != nil {
-		result = append(result, *coverImageURL)
-	}
-	return result
+	arr7 := getMarkdownLinks(_hugoCoverLinks, page.markdown)
+	arr8 := getMarkdownLinks(_hugoFileLinks, page.markdown)
+	return slices.Concat(arr1, arr2, arr3, arr4, arr5, arr6, arr7, arr8, page.frontMatterMedia)
 }
 
 func getImageLinks(content []byte) []string {
@@ -207,18 +284,41 @@ func UnserialiazePHParray(array string) any {
 			Msg("Failed to decode PHP serialized array")
 		return nil
 	} else {
-		return phpArray
+		return restorePHPArrayOrder(phpArray)
 	}
 }
 
-func getMetadata(provider ImageURLProvider, pageURL url.URL, author string, title string, publishDate *time.Time,
+// restorePHPArrayOrder undoes the sort of the indexes of the lists by gophp, as strings: 0, 1, 10, 2...
+func restorePHPArrayOrder(value any) any {
+	switch v := value.(type) {
+	case []any:
+		indexes := make([]string, len(v))
+		for i := range indexes {
+			indexes[i] = strconv.Itoa(i)
+		}
+		slices.Sort(indexes)
+		ordered := make([]any, len(v))
+		for i, index := range indexes {
+			position, _ := strconv.Atoi(index)
+			ordered[position] = restorePHPArrayOrder(v[i])
+		}
+		return ordered
+	case map[string]any:
+		for key, element := range v {
+			v[key] = restorePHPArrayOrder(element)
+		}
+	}
+	return value
+}
+
+func getMetadata(provider ImageURLProvider, pageURL url.URL, authors []wpparser.AuthorInfo, title string, publishDate *time.Time,
 	isDraft bool, categories []string, tags []string, guid *rss.GUID, featuredImageID *string,
 	postFormat *string, customMetaData []wpparser.CustomMetaDatum, taxinomies []wpparser.TaxonomyInfo,
-	postID string, parentPostID *string,
-) (map[string]any, error) {
+	postID string, parentPostID *string, frontMatter FrontMatterMapper, options Options,
+) (map[string]any, []string, error) {
 	metadata := make(map[string]any)
 	metadata["url"] = pageURL.Path // Relative URL
-	metadata["author"] = author
+	setAuthors(metadata, authors)
 	metadata["title"] = title
 	metadata["post_id"] = postID
 	metadata["parent_post_id"] = parentPostID
@@ -237,36 +337,27 @@ func getMetadata(provider ImageURLProvider, pageURL url.URL, author string, titl
 		metadata[TagName] = slices.Compact(tags)
 	}
 
+	// Terms are referred to by slug, for their URL to be the same as in WordPress
 	for _, taxinomy := range taxinomies {
+		term := taxinomy.Slug
+		if term == "" {
+			term = taxinomy.Name
+		}
 		if existing, ok := metadata[taxinomy.Taxonomy]; ok {
 			switch v := existing.(type) {
 			case []string:
-				metadata[taxinomy.Taxonomy] = append(v, taxinomy.Name)
+				if !slices.Contains(v, term) {
+					metadata[taxinomy.Taxonomy] = append(v, term)
+				}
 			default:
-				metadata[taxinomy.Taxonomy] = []string{taxinomy.Name}
+				metadata[taxinomy.Taxonomy] = []string{term}
 			}
 		} else {
-			metadata[taxinomy.Taxonomy] = []string{taxinomy.Name}
+			metadata[taxinomy.Taxonomy] = []string{term}
 		}
 	}
 
-	for _, metadatum := range customMetaData {
-		if strings.HasPrefix(metadatum.Value, "a:") {
-			phpArray := UnserialiazePHParray(metadatum.Value)
-			if phpArray != nil {
-				// Decoded array is a nested dictionnary
-				metadata[metadatum.Key] = phpArray
-			} else {
-				// Fallback to ugly serialized array
-				metadata[metadatum.Key] = metadatum.Value
-			}
-		} else {
-			// Simple string
-			metadata[metadatum.Key] = metadatum.Value
-		}
-		// Note: if any step of the PHP array reading/decoding failed,
-		// now we got the original serialized array
-	}
+	media := setCustomMetadata(metadata, customMetaData, options, pageURL.Host)
 
 	if guid != nil {
 		metadata["guid"] = guid.Value
@@ -279,59 +370,120 @@ func getMetadata(provider ImageURLProvider, pageURL url.URL, author string, titl
 				Str("imageID", *featuredImageID).
 				Msg("Image URL not found")
 		} else {
-			coverInfo := make(map[string]string)
 			imageURL, err := url.Parse(imageInfo.ImageURL)
 			if err != nil {
-				return nil, fmt.Errorf("error parsing image URL '%s': %w", imageInfo.ImageURL, err)
+				return nil, nil, fmt.Errorf("error parsing image URL '%s': %w", imageInfo.ImageURL, err)
 			}
+			coverURL := imageInfo.ImageURL
 			if imageURL.Host == pageURL.Host {
 				// If the image URL is on the same host as the page, we can use a relative URL
-				coverInfo["image"] = imageURL.Path
-			} else {
-				coverInfo["image"] = imageInfo.ImageURL
+				coverURL = imageURL.Path
 			}
-			coverInfo["alt"] = imageInfo.Title
-			metadata["cover"] = coverInfo
+			frontMatter.SetCoverImage(metadata, coverURL, imageInfo.Title)
+			media = append(media, coverURL)
 		}
 	}
 	if postFormat != nil {
 		metadata["type"] = *postFormat
 	}
-	return metadata, nil
+	return metadata, media, nil
 }
 
-func (page *Page) getCoverImageURL() *string {
-	if page.metadata == nil {
-		return nil
+// setAuthors sets the name of the authors, a list if there are several of them, and their slugs in the authors taxonomy
+func setAuthors(metadata map[string]any, authors []wpparser.AuthorInfo) {
+	names := make([]string, 0, len(authors))
+	slugs := make([]string, 0, len(authors))
+	for _, author := range authors {
+		names = append(names, author.Name())
+		slugs = append(slugs, author.Slug())
+	}
+	switch len(names) {
+	case 0:
+		metadata["author"] = ""
+		return
+	case 1:
+		metadata["author"] = names[0]
+	default:
+		metadata["author"] = names
+	}
+	metadata[AuthorName] = slugs
+}
+
+// NewAuthorPage returns the page of author in the authors taxonomy, WordPress does not export
+// biographies, its content is left empty
+func NewAuthorPage(author wpparser.AuthorInfo) *Page {
+	metadata := map[string]any{
+		"title": author.Name(),
+		// URL of the WordPress author archive
+		"aliases": []string{fmt.Sprintf("/author/%s/", author.Slug())},
 	}
-	cover, ok := page.metadata["cover"]
-	if !ok {
-		return nil
+	if author.FirstName != "" {
+		metadata["first_name"] = author.FirstName
 	}
-	coverInfo, ok := cover.(map[string]string)
-	if !ok {
-		return nil
+	if author.LastName != "" {
+		metadata["last_name"] = author.LastName
 	}
-	url1, ok := coverInfo["image"]
-	if !ok {
-		return nil
+	if email := strings.ToLower(strings.TrimSpace(author.Email)); email != "" {
+		// Same as the avatars of the comments
+		metadata["avatar"] = fmt.Sprintf("https://gravatar.com/avatar/%x", sha256.Sum256([]byte(email)))
+	}
+	return &Page{metadata: metadata}
+}
+
+// NewTermPage returns the page of a term of a taxonomy, parent is the name of the parent term, if any
+func NewTermPage(title string, description string, parent string) *Page {
+	metadata := map[string]any{
+		"title": title,
+	}
+	if description != "" {
+		metadata["description"] = description
 	}
-	return &url1
+	if parent != "" {
+		metadata["parent"] = parent
+	}
+	return &Page{metadata: metadata}
 }
 
-func (page *Page) writeMetadata(w io.Writer) error {
-	combinedMetadata, err := utils.GetYAML(page.metadata)
+// NewReusableBlockPage returns the page of a reusable block, which is not rendered on its own
+// but included in other pages, see the reusable-block shortcode
+func NewReusableBlockPage(provider ImageURLProvider, siteURL url.URL, title string, htmlContent string,
+	frontMatter FrontMatterMapper, options Options,
+) (*Page, error) {
+	page := Page{
+		absoluteURL: siteURL,
+		metadata: map[string]any{
+			"title": title,
+			// Ref: https://gohugo.io/content-management/build-options/
+			"build": map[string]any{"render": "never", "list": "never"},
+		},
+		options: options,
+	}
+	markdown, err := page.getMarkdown(provider, htmlContent, nil, frontMatter)
+	if err != nil {
+		return nil, err
+	}
+	page.markdown = *markdown
+	return &page, nil
+}
+
+func (page *Page) writeMetadata(w io.Writer, format string) error {
+	combinedMetadata, err := utils.Marshal(format, page.metadata)
 	if err != nil {
 		return fmt.Errorf("error marshalling metadata: %w", err)
 	}
-	combinedMetadataStr := fmt.Sprintf("---\n%s\n---\n", string(combinedMetadata))
+	combinedMetadataStr := string(combinedMetadata) + "\n"
+	if delimiter := utils.FrontMatterDelimiter(format); delimiter != "" {
+		combinedMetadataStr = fmt.Sprintf("%s\n%s\n%s\n", delimiter, string(combinedMetadata), delimiter)
+	}
 	if _, err := w.Write([]byte(combinedMetadataStr)); err != nil {
 		return fmt.Errorf("error writing to page file: %w", err)
 	}
 	return nil
 }
 
-func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, footnotes []wpparser.Footnote) (*string, error) {
+func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, footnotes []wpparser.Footnote,
+	frontMatter FrontMatterMapper,
+) (*string, error) {
 	if htmlContent == "" {
 		log.Error().
 			Any("page", page.metadata).
@@ -344,28 +496,33 @@ func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, foo
 		attachmentIDs = append(attachmentIDs, attachment.PostID)
 	}
 
-	converter := getMarkdownConverter()
+	converter := getMarkdownConverter(page.options)
 	htmlContent = improvePreTagsWithCode(htmlContent)
+	// Before the built-in conversions, so that rules can override them
+	htmlContent = applyShortcodeRules(page.options.ShortcodeRules, htmlContent)
+	htmlContent, page.unconvertedBlocks = convertBlocks(htmlContent, page.options)
+	if len(page.unconvertedBlocks) > 0 {
+		log.Warn().
+			Str("page", page.absoluteURL.String()).
+			Strs("blocks", page.unconvertedBlocks).
+			Msg("Gutenberg blocks without converter are kept as HTML")
+	}
 	htmlContent = replaceCaptionWithFigure(htmlContent)
-	htmlContent = replaceImageBlockWithFigure(htmlContent)
 	htmlContent = replaceAudioShortCode(htmlContent)
 	htmlContent = replaceVideoShortCode(htmlContent)
-	htmlContent = replaceGutembergGalleryWithFigure(htmlContent)
 	htmlContent = replaceGalleryWithFigure(provider, attachmentIDs, htmlContent)
 	htmlContent = replaceAWBWithParallaxBlur(provider, htmlContent)
-	htmlContent = strings.Replace(htmlContent, _WordPressMoreTag, _customMoreTag, 1)
+	htmlContent = _wordPressMoreTagRegEx.ReplaceAllString(htmlContent, _customMoreTag)
 
 	// We convert consecutive <br> to a custom tag
 	// then we convert <br> to "  \n" and then we convert the custom tag to "\n\n"
 	// It is convoluted but it works.
 	htmlContent = convertConsecutiveBRToCustomTag(htmlContent)
 
-	// This handling is specific to paperMod theme
-	// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#show-table-of-contents-toc-on-blog-post
+	// How the table of contents is shown depends on the theme
 	if strings.Contains(htmlContent, _wordPressTocTag) {
 		htmlContent = strings.Replace(htmlContent, _wordPressTocTag, "", 1)
-		page.metadata["ShowToc"] = true
-		page.metadata["TocOpen"] = true
+		frontMatter.SetTableOfContents(page.metadata)
 	}
 	markdown, err := converter.ConvertString(htmlContent)
 	log.Debug().
@@ -382,18 +539,19 @@ func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, foo
 			Str("page", page.absoluteURL.String()).
 			Msg("empty markdown")
 	}
-	if strings.Contains(markdown, _customMoreTag) {
-		// Ref: https://gohugo.io/content-management/summaries/#manual-summary-splitting
-		summary := strings.Split(markdown, _customMoreTag)[0]
-		markdown = strings.Replace(markdown, _customMoreTag, "", 1)
-		// Remove short codes from summary
-		// Ref: https://github.com/ashishb/wp2hugo/issues/13
-		page.metadata["summary"] = strings.TrimSpace(removeAllHugoShortcodes(summary))
-		log.Warn().
-			Msgf("Manual summary splitting is not supported: %s", page.metadata)
-	}
+	// Only the first divider counts
+	markdown = strings.Replace(markdown, _customMoreTag, _WordPressMoreTag, 1)
+	markdown = strings.ReplaceAll(markdown, _customMoreTag, "")
 
+	markdown = unescapeHugoShortcodes(markdown)
 	markdown = strings.ReplaceAll(markdown, _doubleSpaceWithNewline, "  \n")
+	markdown, page.missingLinks = page.options.LinkResolver.resolveLinks(markdown)
+	if len(page.missingLinks) > 0 {
+		log.Warn().
+			Str("page", page.absoluteURL.String()).
+			Strs("links", page.missingLinks).
+			Msg("Links to WordPress items which are not exported")
+	}
 	markdown = ReplaceAbsoluteLinksWithRelative(page.absoluteURL.Host, markdown)
 	markdown = replaceCatlistWithShortcode(markdown)
 	// Disabled for now, as it does not work well
@@ -419,7 +577,9 @@ func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, foo
 
 	markdown = replaceOrderedListNumbers(markdown)
 	markdown = replaceConsecutiveNewlines(markdown)
-	markdown = replacePlaintextYoutubeURL(markdown)
+	markdown = normalizeYoutubeURLs(markdown)
+	markdown = replacePlaintextEmbedURLs(markdown, page.options.EmbedPrivacy)
+	markdown = replacePlaintextYoutubeURL(markdown, page.options.EmbedPrivacy)
 	markdown = removeTrailingSpaces(markdown)
 	// Workaround for https://github.com/ashishb/wp2hugo/issues/11
 	markdown = removeExtraSpaceBeforeLinks(markdown)
@@ -427,9 +587,36 @@ func (page *Page) getMarkdown(provider ImageURLProvider, htmlContent string, foo
 	return &markdown, nil
 }
 
-func removeAllHugoShortcodes(summary string) string {
-	// Ref: https://gohugo.io/content-management/shortcodes/#remove-shortcodes
-	return _hugoShortCodeMatcher.ReplaceAllString(summary, " ")
+// setExcerpt writes the hand-written excerpt of the page, if any, as its summary and its description.
+// The summary is not written if the summary policy prefers the <!--more--> divider of the page
+func (page *Page) setExcerpt(excerpt string) error {
+	if strings.TrimSpace(excerpt) == "" {
+		return nil
+	}
+	summary, err := getMarkdownConverter(page.options).ConvertString(excerpt)
+	if err != nil {
+		return fmt.Errorf("error converting excerpt to Markdown: %w", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(excerpt))
+	if err != nil {
+		return fmt.Errorf("error parsing excerpt: %w", err)
+	}
+
+	if page.options.SummaryPolicy != SummaryMore || !strings.Contains(page.markdown, _WordPressMoreTag) {
+		page.metadata["summary"] = strings.TrimSpace(ReplaceAbsoluteLinksWithRelative(page.absoluteURL.Host, summary))
+	}
+	if _, ok := page.metadata["description"]; !ok {
+		page.metadata["description"] = strings.Join(strings.Fields(doc.Text()), " ")
+	}
+	return nil
+}
+
+// unescapeHugoShortcodes removes the Markdown escapes added by the HTML to Markdown conversion
+// within the shortcodes, which Hugo does not render as Markdown
+func unescapeHugoShortcodes(markdown string) string {
+	return _hugoShortCodeMatcher.ReplaceAllStringFunc(markdown, func(shortcode string) string {
+		return _hugoShortcodeEscapes.ReplaceAllString(shortcode, "$1")
+	})
 }
 
 // removeExtraSpaceBeforeLinks removes the spurious space that the html-to-markdown
@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
 	"github.com/stretchr/testify/require"
 )
 
@@ -81,9 +82,9 @@ func testMarkdownExtractor(t *testing.T, htmlInput string, markdownOutput string
 	t.Helper()
 	url1, err := url.Parse("https://example.com")
 	require.NoError(t, err)
-	page, err := NewPage(nil, *url1, "author", "Title", nil, false, nil, nil, nil, nil, htmlInput, nil, nil, nil, nil, nil, "0", nil)
+	page, err := NewPage(nil, *url1, []wpparser.AuthorInfo{{Login: "author"}}, "Title", nil, false, nil, nil, nil, nil, htmlInput, "", nil, nil, nil, nil, nil, "0", nil, PaperModFrontMatter{}, Options{})
 	require.NoError(t, err)
-	md, err := page.getMarkdown(nil, htmlInput, nil)
+	md, err := page.getMarkdown(nil, htmlInput, nil, PaperModFrontMatter{})
 	require.NoError(t, err)
 	require.Equal(t, markdownOutput, *md)
 }
@@ -102,3 +103,57 @@ func TestPreTagExtractor2(t *testing.T) {
 	require.Len(t, result3[0], 3)
 	require.Equal(t, "sh", result3[0][1])
 }
+
+func TestAuthorsMetadata(t *testing.T) {
+	t.Parallel()
+
+	metadata := make(map[string]any)
+	setAuthors(metadata, []wpparser.AuthorInfo{{Login: "jdoe", DisplayName: "John Doe"}})
+	require.Equal(t, "John Doe", metadata["author"])
+	require.Equal(t, []string{"jdoe"}, metadata[AuthorName])
+
+	setAuthors(metadata, []wpparser.AuthorInfo{
+		{Login: "jdoe", DisplayName: "John Doe"},
+		{Login: "Jane.Roe", FirstName: "Jane", LastName: "Roe"},
+	})
+	require.Equal(t, []string{"John Doe", "Jane Roe"}, metadata["author"])
+	require.Equal(t, []string{"jdoe", "jane-roe"}, metadata[AuthorName])
+
+	page := NewAuthorPage(wpparser.AuthorInfo{Login: "jdoe", Email: " JDoe@example.net", DisplayName: "John Doe"})
+	require.Equal(t, "John Doe", page.Metadata()["title"])
+	require.Equal(t, []string{"/author/jdoe/"}, page.Metadata()["aliases"])
+	require.Equal(t, "https://gravatar.com/avatar/0fe5e35cadf548edbc7c9cd6dfa46640f7510e9a3e69ed6b6a23c765efe24c87",
+		page.Metadata()["avatar"])
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+	url1, err := url.Parse("https://example.com/hello/")
+	require.NoError(t, err)
+	newPage := func(htmlInput string, excerpt string, policy string) *Page {
+		page, err := NewPage(nil, *url1, nil, "Title", nil, false, nil, nil, nil, nil, htmlInput, excerpt, nil, nil, nil,
+			nil, nil, "0", nil, PaperModFrontMatter{}, Options{SummaryPolicy: policy})
+		require.NoError(t, err)
+		return page
+	}
+	const htmlInput = "<p>Intro</p>\n<!--more Continue reading-->\n<p>Rest</p>"
+	const excerpt = `<p>A <a href="https://example.com/other/">hand-written</a>   excerpt</p>`
+
+	// The divider is kept for Hugo
+	page := newPage(htmlInput, "", SummaryExcerpt)
+	require.Equal(t, "Intro\n\n<!--more-->\n\nRest", page.Markdown())
+	require.NotContains(t, page.Metadata(), "summary")
+	require.NotContains(t, page.Metadata(), "description")
+
+	page = newPage(htmlInput, excerpt, SummaryExcerpt)
+	require.Equal(t, "A [hand-written](/other/) excerpt", page.Metadata()["summary"])
+	require.Equal(t, "A hand-written excerpt", page.Metadata()["description"])
+
+	page = newPage(htmlInput, excerpt, SummaryMore)
+	require.NotContains(t, page.Metadata(), "summary")
+	require.Equal(t, "A hand-written excerpt", page.Metadata()["description"])
+
+	// Without divider, the excerpt is the summary whatever the policy
+	page = newPage("<p>Intro</p>", excerpt, SummaryMore)
+	require.Equal(t, "A [hand-written](/other/) excerpt", page.Metadata()["summary"])
+}
@@ -0,0 +1,205 @@
+package hugopage
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// [text](/?p=123 "title")
+	_markdownLinkRegEx = regexp.MustCompile(`(\]\()([^)\s]+)((?:\s+"[^"]*")?\))`)
+	// <a href="/?p=123"> kept as HTML
+	_hrefRegEx = regexp.MustCompile(`(href=")([^"]+)(")`)
+	// Default permalinks of WordPress, e.g. /?p=123
+	_idQueryParams = []string{"p", "page_id", "attachment_id"}
+	// E.g. /2019/05/07/hello-world/, whose slug is not known
+	_datePermalinkRegEx = regexp.MustCompile(`^/\d{4}/\d{2}/(?:\d{2}/)?[^/]+/?$`)
+	// Paths which are not items, whose last segment is not a slug
+	_nonItemPaths = []string{"category", "tag", "author", "feed", "page", "comments", "search", "wp-admin", "wp-content",
+		"wp-includes", "wp-json"}
+)
+
+// LinkResolver rewrites the links to the WordPress website, by ID, GUID or permalink, old ones included,
+// to the URLs of the Hugo pages
+type LinkResolver struct {
+	hostName string
+	// URL paths by post ID, GUID, path and slug
+	byID   map[string]string
+	byGUID map[string]string
+	byPath map[string]string
+	bySlug map[string]string
+	// IDs of the exported items whose link has a query, e.g. drafts, which have no URL to resolve to
+	// but are not missing either
+	unresolvableIDs map[string]bool
+	// Report the links to items which are not exported, false if the items are not all known
+	reportMissing bool
+}
+
+// NewLinkResolver returns a resolver of the links to items and attachments of the website at siteURL
+func NewLinkResolver(siteURL url.URL, items []wpparser.CommonFields, attachments []wpparser.AttachmentInfo,
+	reportMissing bool,
+) *LinkResolver {
+	r := &LinkResolver{
+		hostName:        normalizeHostName(siteURL.Hostname()),
+		byID:            make(map[string]string),
+		byGUID:          make(map[string]string),
+		byPath:          make(map[string]string),
+		bySlug:          make(map[string]string),
+		unresolvableIDs: make(map[string]bool),
+		reportMissing:   reportMissing,
+	}
+	duplicateSlugs := make(map[string]bool)
+	for _, item := range items {
+		itemURL, err := url.Parse(item.Link)
+		// Links with a query, e.g. /?p=12 for drafts or /?post_type=product&p=666, have no page URL of their own
+		if err != nil || itemURL.Path == "" || itemURL.RawQuery != "" {
+			r.unresolvableIDs[item.PostID] = true
+			continue
+		}
+		target := itemURL.Path
+		r.byID[item.PostID] = target
+		r.byPath[normalizePath(target)] = target
+		if item.GUID != nil && item.GUID.Value != "" {
+			r.byGUID[strings.TrimSpace(item.GUID.Value)] = target
+		}
+
+		slugs := []string{path.Base(strings.TrimSuffix(target, "/"))}
+		for _, meta := range item.CustomMetaData {
+			if meta.Key == "_wp_old_slug" {
+				slugs = append(slugs, meta.Value)
+			}
+		}
+		for _, slug := range slugs {
+			if existing, ok := r.bySlug[slug]; ok && existing != target {
+				duplicateSlugs[slug] = true
+			}
+			r.bySlug[slug] = target
+		}
+	}
+	// Posts of different types can share a slug, their links are ambiguous
+	for slug := range duplicateSlugs {
+		delete(r.bySlug, slug)
+	}
+
+	for _, attachment := range attachments {
+		if fileURL := attachment.GetAttachmentURL(); fileURL != nil {
+			if u, err := url.Parse(*fileURL); err == nil && r.isInternal(u) {
+				r.byID[attachment.PostID] = u.Path
+			}
+		}
+	}
+	return r
+}
+
+// resolveLinks rewrites the internal links of markdown, and returns the links to items which are not exported
+func (r *LinkResolver) resolveLinks(markdown string) (string, []string) {
+	if r == nil {
+		return markdown, nil
+	}
+	var missing []string
+	resolve := func(groups []string) string {
+		link := groups[2]
+		resolved, ok := r.resolve(link)
+		if !ok {
+			if r.reportMissing && r.isMissing(link) && !slices.Contains(missing, link) {
+				missing = append(missing, link)
+			}
+			return groups[0]
+		}
+		return groups[1] + resolved + groups[3]
+	}
+	markdown = replaceAllStringSubmatchFunc(_markdownLinkRegEx, markdown, resolve)
+	markdown = replaceAllStringSubmatchFunc(_hrefRegEx, markdown, resolve)
+	return markdown, missing
+}
+
+// resolve returns the URL path of the Hugo page link points to, with its fragment
+func (r *LinkResolver) resolve(link string) (string, bool) {
+	u, err := url.Parse(strings.ReplaceAll(link, "&amp;", "&"))
+	if err != nil || !r.isInternal(u) {
+		return "", false
+	}
+	target, ok := r.findTarget(u)
+	if !ok {
+		return "", false
+	}
+	if u.Fragment != "" {
+		target += "#" + u.Fragment
+	}
+	if target != link {
+		log.Debug().
+			Str("link", link).
+			Str("target", target).
+			Msg("Internal link resolved")
+	}
+	return target, true
+}
+
+func (r *LinkResolver) findTarget(u *url.URL) (string, bool) {
+	for _, param := range _idQueryParams {
+		if id := u.Query().Get(param); id != "" {
+			target, ok := r.byID[id]
+			return target, ok
+		}
+	}
+	if u.Host != "" {
+		if target, ok := r.byGUID[u.String()]; ok {
+			return target, true
+		}
+	}
+	if target, ok := r.byPath[normalizePath(u.Path)]; ok {
+		return target, true
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) == 0 || slices.Contains(_nonItemPaths, segments[0]) || slices.Contains(segments, "feed") {
+		return "", false
+	}
+	// Old permalink structure or old slug
+	target, ok := r.bySlug[segments[len(segments)-1]]
+	return target, ok
+}
+
+// pageURL returns the URL path of the page, or of the file of the attachment, of postID
+func (r *LinkResolver) pageURL(postID string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	target, ok := r.byID[postID]
+	return target, ok
+}
+
+// isMissing returns true if link points to an item of the website, by ID or date-based permalink,
+// which has not been resolved
+func (r *LinkResolver) isMissing(link string) bool {
+	u, err := url.Parse(strings.ReplaceAll(link, "&amp;", "&"))
+	if err != nil || !r.isInternal(u) {
+		return false
+	}
+	for _, param := range _idQueryParams {
+		if id := u.Query().Get(param); id != "" {
+			return !r.unresolvableIDs[id]
+		}
+	}
+	return _datePermalinkRegEx.MatchString(u.Path)
+}
+
+func (r *LinkResolver) isInternal(u *url.URL) bool {
+	if u.Host == "" {
+		return u.Scheme == "" && (strings.HasPrefix(u.Path, "/") || (u.Path == "" && u.RawQuery != ""))
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && normalizeHostName(u.Hostname()) == r.hostName
+}
+
+func normalizeHostName(hostName string) string {
+	return strings.TrimPrefix(strings.ToLower(hostName), "www.")
+}
+
+func normalizePath(urlPath string) string {
+	return strings.TrimSuffix(urlPath, "/") + "/"
+}
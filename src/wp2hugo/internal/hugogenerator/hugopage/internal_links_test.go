@@ -0,0 +1,80 @@
+package hugopage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLinkResolver(t *testing.T, reportMissing bool) *LinkResolver {
+	t.Helper()
+	siteURL, err := url.Parse("https://www.example.com")
+	require.NoError(t, err)
+	items := []wpparser.CommonFields{
+		{
+			PostID: "123",
+			Link:   "https://www.example.com/hello-world/",
+			GUID:   &rss.GUID{Value: "https://www.example.com/?p=123"},
+			CustomMetaData: []wpparser.CustomMetaDatum{
+				{Key: "_wp_old_slug", Value: "hello"},
+			},
+		},
+		{
+			PostID: "45",
+			Link:   "https://www.example.com/about/team/",
+		},
+		{
+			PostID: "666",
+			Link:   "https://www.example.com/?post_type=product&p=666",
+		},
+	}
+	return NewLinkResolver(*siteURL, items, nil, reportMissing)
+}
+
+func TestLinkResolver_ResolveLinks(t *testing.T) {
+	t.Parallel()
+	r := newTestLinkResolver(t, true)
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`[Hello](https://www.example.com/?p=123)`, `[Hello](/hello-world/)`},
+		{`[Hello](/?p=123 "Hello")`, `[Hello](/hello-world/ "Hello")`},
+		{`[Hello](http://example.com/?p=123#comments)`, `[Hello](/hello-world/#comments)`},
+		{`[Team](https://example.com/?page_id=45)`, `[Team](/about/team/)`},
+		{`<a href="/?page_id=45&amp;lang=en">Team</a>`, `<a href="/about/team/">Team</a>`},
+		// Old date permalink and old slug
+		{`[Hello](https://www.example.com/2019/05/07/hello-world/)`, `[Hello](/hello-world/)`},
+		{`[Hello](https://www.example.com/2019/05/hello/)`, `[Hello](/hello-world/)`},
+		// Left as is
+		{`[Category](https://www.example.com/category/hello-world/)`, `[Category](https://www.example.com/category/hello-world/)`},
+		{`[Feed](https://www.example.com/hello-world/feed/)`, `[Feed](https://www.example.com/hello-world/feed/)`},
+		{`[Other](https://other.com/?p=123)`, `[Other](https://other.com/?p=123)`},
+		{`[Product](https://www.example.com/?p=666)`, `[Product](https://www.example.com/?p=666)`},
+	}
+	for _, testCase := range testCases {
+		markdown, _ := r.resolveLinks(testCase.input)
+		require.Equal(t, testCase.expected, markdown, "input: %s", testCase.input)
+	}
+}
+
+func TestLinkResolver_MissingLinks(t *testing.T) {
+	t.Parallel()
+	// 666 is exported, its link has a query
+	markdown := "[A](/?p=999) [B](https://example.com/2020/01/02/draft/) [C](/?p=999) [D](/unknown/) [E](/?p=123) " +
+		"[F](/?post_type=product&p=666) [G](/?p=666)"
+
+	_, missing := newTestLinkResolver(t, true).resolveLinks(markdown)
+	require.Equal(t, []string{"/?p=999", "https://example.com/2020/01/02/draft/"}, missing)
+
+	_, missing = newTestLinkResolver(t, false).resolveLinks(markdown)
+	require.Empty(t, missing)
+
+	var r *LinkResolver
+	output, missing := r.resolveLinks(markdown)
+	require.Equal(t, markdown, output)
+	require.Empty(t, missing)
+}
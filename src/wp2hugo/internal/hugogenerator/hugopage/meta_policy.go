@@ -0,0 +1,274 @@
+package hugopage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Types the values of the post meta are converted to, see MetaPolicy.Types
+const (
+	MetaTypeString = "string"
+	MetaTypeNumber = "number"
+	MetaTypeBool   = "bool"
+	MetaTypeDate   = "date"
+	MetaTypeJSON   = "json"
+	// Numbers, booleans, dates and JSON values are detected
+	MetaTypeAuto = "auto"
+)
+
+// MetaPolicy decides which post meta are written to the front matter of the posts, pages and custom posts, and how.
+// PHP serialized arrays are always decoded
+type MetaPolicy struct {
+	// Glob patterns of the meta keys to write, all of them if empty
+	Allow []string `yaml:"allow"`
+	// Glob patterns of the meta keys not to write, even if allowed
+	Deny []string `yaml:"deny"`
+	// Front matter keys by meta key, renamed keys are written at the top level of the front matter
+	Rename map[string]string `yaml:"rename"`
+	// Types by glob pattern of meta key, one of MetaType*, the values are strings by default
+	Types map[string]string `yaml:"types"`
+	// Optional, front matter key the meta are written under, e.g. "wp"
+	Namespace string `yaml:"namespace"`
+}
+
+// Meta of WordPress itself, of its editors and of page builders, which make no sense in Hugo
+var _defaultDeniedMeta = []string{
+	"_edit_lock", "_edit_last", "_wp_old_slug", "_wp_old_date", "_encloseme", "_pingme", "_thumbnail_id",
+	"_wp_page_template", "_wp_trash_*", "_wp_desired_post_slug", "_oembed_*", "_wp_attached_file",
+	"_wp_attachment_*", "_menu_item_*", "_elementor_*", "_wpb_*", "_vc_*", "_fusion*", "fusion_builder_status",
+	"_et_pb_*", "_et_builder_*", "panels_data", "_themify_builder_*", "_last_editor_used_jetpack",
+}
+
+// Date formats of the post meta, e.g. 20240102 for the date pickers of ACF
+var _metaDateFormats = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02", "20060102"}
+
+var (
+	_metaNumberRegEx = regexp.MustCompile(`^-?(?:0|[1-9]\d*)(?:\.\d+)?$`)
+	// Auto-detected dates, 20240102 is a number
+	_metaDateRegEx = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(?:[ T]\d{2}:\d{2}:\d{2}(?:Z|[+-]\d{2}:\d{2})?)?$`)
+)
+
+// DefaultMetaPolicy drops the meta of WordPress and of page builders, and keeps the others as is
+func DefaultMetaPolicy() MetaPolicy {
+	return MetaPolicy{Deny: _defaultDeniedMeta}
+}
+
+// LoadMetaPolicy reads the post meta policy of the YAML file at filePath, the fields it does not set
+// keep the values of DefaultMetaPolicy
+func LoadMetaPolicy(filePath string) (MetaPolicy, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return MetaPolicy{}, fmt.Errorf("error opening meta policy: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	policy := DefaultMetaPolicy()
+	if err := decoder.Decode(&policy); err != nil {
+		return MetaPolicy{}, fmt.Errorf("error parsing meta policy %s: %w", filePath, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return MetaPolicy{}, fmt.Errorf("invalid meta policy %s: %w", filePath, err)
+	}
+	return policy, nil
+}
+
+// Validate checks the glob patterns and the types of the policy
+func (p MetaPolicy) Validate() error {
+	var errs []error
+	patterns := append(append([]string{}, p.Allow...), p.Deny...)
+	for pattern, metaType := range p.Types {
+		patterns = append(patterns, pattern)
+		switch metaType {
+		case MetaTypeString, MetaTypeNumber, MetaTypeBool, MetaTypeDate, MetaTypeJSON, MetaTypeAuto:
+		default:
+			errs = append(errs, fmt.Errorf("invalid type %q of %q", metaType, pattern))
+		}
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("invalid pattern %q: %w", pattern, err))
+		}
+	}
+	for key, name := range p.Rename {
+		if strings.TrimSpace(name) == "" {
+			errs = append(errs, fmt.Errorf("empty front matter key for %q", key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isAllowed returns true if the meta key is written to the front matter
+func (p MetaPolicy) isAllowed(key string) bool {
+	return (len(p.Allow) == 0 || matchesAny(p.Allow, key)) && !matchesAny(p.Deny, key)
+}
+
+// getType returns the type of the meta key, of the longest pattern matching it if several do
+func (p MetaPolicy) getType(key string) string {
+	if metaType, ok := p.Types[key]; ok {
+		return metaType
+	}
+	metaType, longest := MetaTypeString, ""
+	for pattern, patternType := range p.Types {
+		if matched, _ := path.Match(pattern, key); matched &&
+			(len(pattern) > len(longest) || (len(pattern) == len(longest) && pattern < longest)) {
+			metaType, longest = patternType, pattern
+		}
+	}
+	return metaType
+}
+
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// setCustomMetadata writes the post meta allowed by the policy of options to metadata, the fields of
+// Advanced Custom Fields converted by options.ACFFields. It returns the media of the fields, hostName is
+// the one of the website
+func setCustomMetadata(metadata map[string]any, customMetaData []wpparser.CustomMetaDatum, options Options,
+	hostName string,
+) []string {
+	policy := options.MetaPolicy
+	fields := options.ACFFields.convert(customMetaData, hostName)
+	namespaced := make(map[string]any)
+	for _, metadatum := range customMetaData {
+		if isSEOMetaKey(metadatum.Key) {
+			// See setSEOMetadata
+			continue
+		}
+		if fields.consumed[metadatum.Key] {
+			// Reference of a field or value of a sub field
+			continue
+		}
+		if !policy.isAllowed(metadatum.Key) {
+			log.Trace().
+				Str("key", metadatum.Key).
+				Msg("Post meta not written to the front matter")
+			continue
+		}
+
+		value, ok := fields.values[metadatum.Key]
+		if !ok {
+			value = getMetaValue(metadatum, policy.getType(metadatum.Key))
+		}
+		if value == nil {
+			// Empty number, date or field
+			continue
+		}
+		if name, ok := policy.Rename[metadatum.Key]; ok {
+			metadata[name] = value
+		} else if policy.Namespace != "" {
+			namespaced[metadatum.Key] = value
+		} else {
+			metadata[metadatum.Key] = value
+		}
+	}
+	if len(namespaced) > 0 {
+		metadata[policy.Namespace] = namespaced
+	}
+	return fields.media
+}
+
+func getMetaValue(metadatum wpparser.CustomMetaDatum, metaType string) any {
+	if strings.HasPrefix(metadatum.Value, "a:") {
+		if phpArray := UnserialiazePHParray(metadatum.Value); phpArray != nil {
+			// Decoded array is a nested dictionnary
+			return phpArray
+		}
+		// Fallback to ugly serialized array
+		return metadatum.Value
+	}
+	value, err := coerceMetaValue(metadatum.Value, metaType)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("key", metadatum.Key).
+			Str("type", metaType).
+			Msg("Post meta kept as a string")
+		return metadatum.Value
+	}
+	return value
+}
+
+// coerceMetaValue converts value to metaType, one of MetaType*
+func coerceMetaValue(value string, metaType string) (any, error) {
+	trimmed := strings.TrimSpace(value)
+	switch metaType {
+	case MetaTypeNumber:
+		if trimmed == "" {
+			return nil, nil
+		}
+		return parseMetaNumber(trimmed)
+	case MetaTypeBool:
+		switch strings.ToLower(trimmed) {
+		case "1", "true", "yes", "on":
+			return true, nil
+		case "", "0", "false", "no", "off":
+			return false, nil
+		}
+		return nil, fmt.Errorf("not a boolean: %q", value)
+	case MetaTypeDate:
+		if trimmed == "" {
+			return nil, nil
+		}
+		for _, format := range _metaDateFormats {
+			if date, err := time.Parse(format, trimmed); err == nil {
+				return date, nil
+			}
+		}
+		return nil, fmt.Errorf("not a date: %q", value)
+	case MetaTypeJSON:
+		var decoded any
+		if err := json.Unmarshal([]byte(trimmed), &decoded); err != nil {
+			return nil, fmt.Errorf("not JSON: %w", err)
+		}
+		return decoded, nil
+	case MetaTypeAuto:
+		switch {
+		case trimmed == "true" || trimmed == "false":
+			return trimmed == "true", nil
+		case _metaNumberRegEx.MatchString(trimmed):
+			return parseMetaNumber(trimmed)
+		case _metaDateRegEx.MatchString(trimmed):
+			if date, err := coerceMetaValue(trimmed, MetaTypeDate); err == nil {
+				return date, nil
+			}
+		case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+			if decoded, err := coerceMetaValue(trimmed, MetaTypeJSON); err == nil {
+				return decoded, nil
+			}
+		}
+		return value, nil
+	}
+	return value, nil
+}
+
+func parseMetaNumber(value string) (any, error) {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("not a number: %q", value)
+	}
+	return f, nil
+}
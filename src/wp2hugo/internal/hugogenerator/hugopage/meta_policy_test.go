@@ -0,0 +1,121 @@
+package hugopage
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+var _testMetaData = []wpparser.CustomMetaDatum{
+	{Key: "_edit_lock", Value: "1700000000:1"},
+	{Key: "_elementor_data", Value: `[{"id":"1"}]`},
+	{Key: "subtitle_text", Value: "A subtitle"},
+	{Key: "price", Value: "12.50"},
+	{Key: "stock", Value: "007"},
+	{Key: "featured", Value: "1"},
+	{Key: "event_date", Value: "20240102"},
+	{Key: "settings", Value: `{"color":"red"}`},
+	{Key: "options", Value: `a:1:{s:5:"width";s:3:"100";}`},
+}
+
+func TestSetCustomMetadata_Default(t *testing.T) {
+	t.Parallel()
+	metadata := make(map[string]any)
+	setCustomMetadata(metadata, _testMetaData, Options{MetaPolicy: DefaultMetaPolicy()}, "example.com")
+	require.NotContains(t, metadata, "_edit_lock")
+	require.NotContains(t, metadata, "_elementor_data")
+	require.Equal(t, "12.50", metadata["price"])
+	require.Equal(t, `{"color":"red"}`, metadata["settings"])
+	require.Equal(t, map[string]any{"width": "100"}, metadata["options"])
+}
+
+func TestSetCustomMetadata_Policy(t *testing.T) {
+	t.Parallel()
+	policy := MetaPolicy{
+		Allow:  []string{"*"},
+		Deny:   []string{"_*", "options"},
+		Rename: map[string]string{"subtitle_text": "subtitle"},
+		Types: map[string]string{
+			"featured":   MetaTypeBool,
+			"event_date": MetaTypeDate,
+			"*":          MetaTypeAuto,
+		},
+		Namespace: "wp",
+	}
+	require.NoError(t, policy.Validate())
+	metadata := map[string]any{"title": "Title"}
+	setCustomMetadata(metadata, _testMetaData, Options{MetaPolicy: policy}, "example.com")
+	require.Equal(t, map[string]any{
+		"title":    "Title",
+		"subtitle": "A subtitle",
+		"wp": map[string]any{
+			"price":      12.5,
+			"stock":      "007",
+			"featured":   true,
+			"event_date": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			"settings":   map[string]any{"color": "red"},
+		},
+	}, metadata)
+}
+
+func TestCoerceMetaValue(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		value    string
+		metaType string
+		expected any
+	}{
+		{"42", MetaTypeAuto, int64(42)},
+		{"-1.5", MetaTypeAuto, -1.5},
+		{"true", MetaTypeAuto, true},
+		{"2024-01-02 03:04:05", MetaTypeAuto, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"[1, 2]", MetaTypeAuto, []any{float64(1), float64(2)}},
+		{"[not json", MetaTypeAuto, "[not json"},
+		{"0612345678", MetaTypeAuto, "0612345678"},
+		{"on", MetaTypeBool, true},
+		{"", MetaTypeBool, false},
+		{" 7 ", MetaTypeNumber, int64(7)},
+		{" 7 ", MetaTypeString, " 7 "},
+	}
+	for _, testCase := range testCases {
+		value, err := coerceMetaValue(testCase.value, testCase.metaType)
+		require.NoError(t, err, "value: %q", testCase.value)
+		require.Equal(t, testCase.expected, value, "value: %q", testCase.value)
+	}
+
+	_, err := coerceMetaValue("abc", MetaTypeNumber)
+	require.Error(t, err)
+	_, err = coerceMetaValue("maybe", MetaTypeBool)
+	require.Error(t, err)
+}
+
+func TestLoadMetaPolicy(t *testing.T) {
+	t.Parallel()
+	filePath := path.Join(t.TempDir(), "meta.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte(`
+rename:
+  subtitle_text: subtitle
+types:
+  "*_date": date
+namespace: wp
+`), 0o600))
+	policy, err := LoadMetaPolicy(filePath)
+	require.NoError(t, err)
+	// Not set by the file
+	require.Equal(t, DefaultMetaPolicy().Deny, policy.Deny)
+	require.Equal(t, "wp", policy.Namespace)
+	require.Equal(t, MetaTypeDate, policy.getType("event_date"))
+	require.Equal(t, MetaTypeString, policy.getType("price"))
+
+	require.NoError(t, os.WriteFile(filePath, []byte("types:\n  price: money\n"), 0o600))
+	_, err = LoadMetaPolicy(filePath)
+	require.ErrorContains(t, err, `invalid type "money"`)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("denied: [x]\n"), 0o600))
+	_, err = LoadMetaPolicy(filePath)
+	require.Error(t, err)
+}
@@ -0,0 +1,227 @@
+package hugopage
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Post meta of the SEO plugins Yoast, Rank Math and All in One SEO (v4 and older), mapped by getSEOMetadata
+// or not, they are not written as is to the front matter
+var _seoMetaKeyPrefixes = []string{"_yoast_wpseo_", "rank_math_", "_aioseo_", "_aioseop_"}
+
+// Variables of title templates, e.g. "%%title%% %%sep%% %%sitename%%" (Yoast), "%title%" (Rank Math)
+// or "#post_title #separator_sa #site_title" (All in One SEO)
+var _seoTitleVariableRegEx = regexp.MustCompile(`%%?[a-z_]+%%?|#[a-z_]+`)
+
+// seoMetadata is what the SEO plugins set for a page
+type seoMetadata struct {
+	title       string
+	description string
+	canonical   string
+	noIndex     bool
+	noFollow    bool
+	// Open Graph and Twitter images
+	images []string
+	// Term ID
+	primaryCategoryID string
+	keywords          []string
+}
+
+func isSEOMetaKey(key string) bool {
+	for _, prefix := range _seoMetaKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getSEOMetadata returns the metadata set by the SEO plugins in customMetaData,
+// the first plugin setting a field wins
+func getSEOMetadata(customMetaData []wpparser.CustomMetaDatum) seoMetadata {
+	var seo seoMetadata
+	setOnce := func(field *string, value string) {
+		if *field == "" {
+			*field = strings.TrimSpace(value)
+		}
+	}
+	for _, meta := range customMetaData {
+		value := strings.TrimSpace(meta.Value)
+		if value == "" {
+			continue
+		}
+		switch meta.Key {
+		case "_yoast_wpseo_title", "rank_math_title", "_aioseo_title", "_aioseop_title":
+			if _seoTitleVariableRegEx.MatchString(value) {
+				// Rendered by the plugin along with the website title, the page title is better
+				log.Debug().
+					Str("key", meta.Key).
+					Str("title", value).
+					Msg("Ignoring SEO title template")
+				continue
+			}
+			setOnce(&seo.title, value)
+		case "_yoast_wpseo_metadesc", "rank_math_description", "_aioseo_description", "_aioseop_description":
+			setOnce(&seo.description, value)
+		case "_yoast_wpseo_canonical", "rank_math_canonical_url", "_aioseo_canonical_url", "_aioseop_custom_link":
+			setOnce(&seo.canonical, value)
+		case "_yoast_wpseo_meta-robots-noindex":
+			// 2 means index, overriding the default of the post type
+			seo.noIndex = seo.noIndex || value == "1"
+		case "_yoast_wpseo_meta-robots-nofollow":
+			seo.noFollow = seo.noFollow || value == "1"
+		case "_aioseop_noindex", "_aioseo_robots_noindex":
+			seo.noIndex = seo.noIndex || value == "on" || value == "1"
+		case "_aioseop_nofollow", "_aioseo_robots_nofollow":
+			seo.noFollow = seo.noFollow || value == "on" || value == "1"
+		case "rank_math_robots":
+			// E.g. a:2:{i:0;s:7:"noindex";i:1;s:8:"nofollow";}
+			robots := getPHPArrayValues(value)
+			seo.noIndex = seo.noIndex || slices.Contains(robots, "noindex")
+			seo.noFollow = seo.noFollow || slices.Contains(robots, "nofollow")
+		case "_yoast_wpseo_opengraph-image", "_yoast_wpseo_twitter-image", "rank_math_facebook_image",
+			"rank_math_twitter_image", "_aioseo_og_image_custom_url", "_aioseo_twitter_image_custom_url":
+			if !slices.Contains(seo.images, value) {
+				seo.images = append(seo.images, value)
+			}
+		case "_yoast_wpseo_primary_category", "rank_math_primary_category":
+			setOnce(&seo.primaryCategoryID, value)
+		case "_yoast_wpseo_focuskw", "rank_math_focus_keyword", "_aioseo_keywords", "_aioseop_keywords":
+			for _, keyword := range getSEOKeywords(value) {
+				if !slices.Contains(seo.keywords, keyword) {
+					seo.keywords = append(seo.keywords, keyword)
+				}
+			}
+		}
+	}
+	return seo
+}
+
+// getSEOKeywords returns the keywords of value, a CSV list or the JSON of All in One SEO v4,
+// e.g. [{"label":"hugo","value":"hugo"}]
+func getSEOKeywords(value string) []string {
+	var keywords []string
+	var tags []struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(value), &tags); err == nil {
+		for _, tag := range tags {
+			keywords = append(keywords, tag.Value)
+		}
+	} else {
+		keywords = strings.Split(value, ",")
+	}
+	result := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			result = append(result, keyword)
+		}
+	}
+	return result
+}
+
+// getPHPArrayValues returns the values of the PHP serialized array value, in the order of their keys,
+// numeric keys first
+func getPHPArrayValues(value string) []string {
+	var values []string
+	switch array := UnserialiazePHParray(value).(type) {
+	case []any:
+		for _, v := range array {
+			values = append(values, fmt.Sprint(v))
+		}
+	case map[string]any:
+		keys := slices.Collect(maps.Keys(array))
+		slices.SortFunc(keys, comparePHPArrayKeys)
+		for _, key := range keys {
+			values = append(values, fmt.Sprint(array[key]))
+		}
+	}
+	return values
+}
+
+func comparePHPArrayKeys(a, b string) int {
+	i, errA := strconv.Atoi(a)
+	j, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(i, j)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// setSEOMetadata writes the metadata of the SEO plugins to the front matter, the images on the
+// website are returned to be downloaded
+func (page *Page) setSEOMetadata(customMetaData []wpparser.CustomMetaDatum, frontMatter FrontMatterMapper) []string {
+	seo := getSEOMetadata(customMetaData)
+	metadata := page.metadata
+	if seo.title != "" {
+		metadata["title"] = seo.title
+	}
+	if seo.description != "" {
+		metadata["description"] = seo.description
+	}
+	if seo.canonical != "" {
+		metadata["canonicalURL"] = seo.canonical
+	}
+	if seo.noIndex || seo.noFollow {
+		var robots []string
+		if seo.noIndex {
+			robots = append(robots, "noindex")
+			frontMatter.SetNoIndex(metadata)
+		}
+		if seo.noFollow {
+			robots = append(robots, "nofollow")
+		}
+		metadata["robots"] = strings.Join(robots, ", ")
+	}
+	if seo.primaryCategoryID != "" {
+		if name, ok := page.options.CategoryNames[seo.primaryCategoryID]; ok {
+			metadata["primary_category"] = name
+		} else {
+			log.Debug().
+				Str("categoryID", seo.primaryCategoryID).
+				Msg("Primary category not found")
+		}
+	}
+	if len(seo.keywords) > 0 {
+		metadata["keywords"] = seo.keywords
+	}
+
+	var mediaLinks []string
+	if len(seo.images) > 0 {
+		// Used by Hugo's embedded Open Graph and Twitter Cards templates
+		// Ref: https://gohugo.io/templates/embedded/#open-graph
+		images := make([]string, 0, len(seo.images))
+		for _, image := range seo.images {
+			if imageURL, err := url.Parse(image); err == nil && imageURL.Host == page.absoluteURL.Host {
+				image = imageURL.Path
+				mediaLinks = append(mediaLinks, image)
+			}
+			images = append(images, image)
+		}
+		// E.g. the featured image, see HugoFrontMatter
+		existing, _ := metadata["images"].([]string)
+		for _, image := range existing {
+			if !slices.Contains(images, image) {
+				images = append(images, image)
+			}
+		}
+		metadata["images"] = images
+	}
+	return mediaLinks
+}
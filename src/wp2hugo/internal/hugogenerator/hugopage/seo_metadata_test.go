@@ -0,0 +1,103 @@
+package hugopage
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func newSEOTestPage(t *testing.T, customMetaData []wpparser.CustomMetaDatum, frontMatter FrontMatterMapper) *Page {
+	t.Helper()
+	pageURL, err := url.Parse("https://example.com/hello/")
+	require.NoError(t, err)
+	page, err := NewPage(nil, *pageURL, nil, "Hello", nil, false, nil, nil, nil, nil, "<p>Content</p>",
+		"<p>The excerpt</p>", nil, nil, nil, customMetaData, nil, "1", nil, frontMatter,
+		Options{CategoryNames: map[string]string{"7": "travel"}})
+	require.NoError(t, err)
+	return page
+}
+
+func TestSEOMetadata_Yoast(t *testing.T) {
+	t.Parallel()
+	page := newSEOTestPage(t, []wpparser.CustomMetaDatum{
+		{Key: "_yoast_wpseo_title", Value: "Hello, the SEO title"},
+		{Key: "_yoast_wpseo_metadesc", Value: "The meta description"},
+		{Key: "_yoast_wpseo_canonical", Value: "https://other.com/hello/"},
+		{Key: "_yoast_wpseo_meta-robots-noindex", Value: "1"},
+		{Key: "_yoast_wpseo_opengraph-image", Value: "https://example.com/wp-content/uploads/og.jpg"},
+		{Key: "_yoast_wpseo_opengraph-image-id", Value: "12"},
+		{Key: "_yoast_wpseo_twitter-image", Value: "https://cdn.com/twitter.jpg"},
+		{Key: "_yoast_wpseo_primary_category", Value: "7"},
+		{Key: "_yoast_wpseo_focuskw", Value: "hello world"},
+		{Key: "_yoast_wpseo_content_score", Value: "90"},
+		{Key: "other_meta", Value: "kept"},
+	}, HugoFrontMatter{})
+	metadata := page.Metadata()
+	require.Equal(t, "Hello, the SEO title", metadata["title"])
+	require.Equal(t, "The meta description", metadata["description"])
+	require.Equal(t, "The excerpt", metadata["summary"])
+	require.Equal(t, "https://other.com/hello/", metadata["canonicalURL"])
+	require.Equal(t, "noindex", metadata["robots"])
+	require.Equal(t, map[string]any{"disable": true}, metadata["sitemap"])
+	require.Equal(t, []string{"/wp-content/uploads/og.jpg", "https://cdn.com/twitter.jpg"}, metadata["images"])
+	require.Equal(t, "travel", metadata["primary_category"])
+	require.Equal(t, []string{"hello world"}, metadata["keywords"])
+	require.Equal(t, "kept", metadata["other_meta"])
+	require.NotContains(t, metadata, "_yoast_wpseo_content_score")
+	require.NotContains(t, metadata, "_yoast_wpseo_opengraph-image-id")
+	require.Contains(t, page.WPMediaLinks(), "/wp-content/uploads/og.jpg")
+}
+
+func TestSEOMetadata_RankMath(t *testing.T) {
+	t.Parallel()
+	page := newSEOTestPage(t, []wpparser.CustomMetaDatum{
+		{Key: "rank_math_title", Value: "%title% %sep% %sitename%"},
+		{Key: "rank_math_description", Value: "The Rank Math description"},
+		{Key: "rank_math_robots", Value: `a:2:{i:0;s:7:"noindex";i:1;s:8:"nofollow";}`},
+		{Key: "rank_math_focus_keyword", Value: "hugo, wordpress"},
+		{Key: "rank_math_seo_score", Value: "75"},
+	}, PaperModFrontMatter{})
+	metadata := page.Metadata()
+	// Title templates are ignored
+	require.Equal(t, "Hello", metadata["title"])
+	require.Equal(t, "The Rank Math description", metadata["description"])
+	require.Equal(t, "noindex, nofollow", metadata["robots"])
+	require.Equal(t, true, metadata["robotsNoIndex"])
+	require.Equal(t, []string{"hugo", "wordpress"}, metadata["keywords"])
+	require.NotContains(t, metadata, "rank_math_seo_score")
+}
+
+func TestSEOMetadata_AllInOneSEO(t *testing.T) {
+	t.Parallel()
+	page := newSEOTestPage(t, []wpparser.CustomMetaDatum{
+		{Key: "_aioseo_title", Value: "#post_title #separator_sa #site_title"},
+		{Key: "_aioseop_title", Value: "The old AIOSEO title"},
+		{Key: "_aioseo_keywords", Value: `[{"label":"hugo","value":"hugo"}]`},
+		{Key: "_aioseop_nofollow", Value: "on"},
+	}, PaperModFrontMatter{})
+	metadata := page.Metadata()
+	require.Equal(t, "The old AIOSEO title", metadata["title"])
+	// Without a meta description, the excerpt is the description
+	require.Equal(t, "The excerpt", metadata["description"])
+	require.Equal(t, "nofollow", metadata["robots"])
+	require.NotContains(t, metadata, "robotsNoIndex")
+	require.Equal(t, []string{"hugo"}, metadata["keywords"])
+}
+
+func TestGetPHPArrayValues_Order(t *testing.T) {
+	t.Parallel()
+	var list strings.Builder
+	values := make([]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		value := fmt.Sprintf("v%d", i)
+		fmt.Fprintf(&list, "i:%d;s:%d:%q;", i, len(value), value)
+		values = append(values, value)
+	}
+	require.Equal(t, values, getPHPArrayValues(fmt.Sprintf("a:12:{%s}", list.String())))
+	// Non-sequential indexes, e.g. after a row was removed
+	require.Equal(t, []string{"b", "c", "a"}, getPHPArrayValues(`a:3:{i:10;s:1:"a";i:2;s:1:"b";i:5;s:1:"c";}`))
+}
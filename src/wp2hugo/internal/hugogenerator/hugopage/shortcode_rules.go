@@ -0,0 +1,184 @@
+package hugopage
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// ShortcodeRule converts a WordPress shortcode of a plugin, e.g. [su_note], to Hugo
+type ShortcodeRule struct {
+	// Name of the WordPress shortcode, e.g. "su_note"
+	Name string `yaml:"name"`
+	// The shortcode wraps content, e.g. [su_note]content[/su_note]
+	Enclosing bool `yaml:"enclosing"`
+	// Default values of the attributes
+	Attributes map[string]string `yaml:"attributes"`
+	// Hugo shortcode or Markdown to write instead, ${name} is replaced with the attribute name,
+	// ${0} with the first positional attribute and ${content} with the Markdown of the content
+	Template string `yaml:"template"`
+	// Optional, Hugo shortcode written along with the content
+	Layout *ShortcodeLayout `yaml:"layout"`
+
+	regex *regexp.Regexp
+}
+
+// ShortcodeLayout is a layout written to layouts/shortcodes/
+type ShortcodeLayout struct {
+	// File name, e.g. "note.html"
+	File    string `yaml:"file"`
+	Content string `yaml:"content"`
+}
+
+type shortcodeRulesFile struct {
+	Shortcodes []ShortcodeRule `yaml:"shortcodes"`
+}
+
+// Template text within this tag is written as is, without Markdown escapes
+const _rawTag = "wp2hugo-raw"
+
+var (
+	_shortcodeNameRegEx       = regexp.MustCompile(`^[\w-]+$`)
+	_shortcodeRulePlaceholder = regexp.MustCompile(`\$\{([\w-]+)\}`)
+	// Named attributes with double, single or no quotes, then positional attributes
+	// Ref: https://developer.wordpress.org/reference/functions/get_shortcode_atts_regex/
+	_shortcodeAttrRegEx = regexp.MustCompile(`([\w-]+)\s*=\s*"([^"]*)"|([\w-]+)\s*=\s*'([^']*)'|([\w-]+)\s*=\s*([^\s'"]+)|"([^"]*)"|'([^']*)'|(\S+)`)
+)
+
+// LoadShortcodeRules reads the shortcode rules of the YAML file at filePath, see doc/shortcodes.md
+func LoadShortcodeRules(filePath string) ([]ShortcodeRule, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening shortcode rules: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	var rulesFile shortcodeRulesFile
+	if err := decoder.Decode(&rulesFile); err != nil {
+		return nil, fmt.Errorf("error parsing shortcode rules %s: %w", filePath, err)
+	}
+	if err := CompileShortcodeRules(rulesFile.Shortcodes); err != nil {
+		return nil, fmt.Errorf("invalid shortcode rules %s: %w", filePath, err)
+	}
+	return rulesFile.Shortcodes, nil
+}
+
+// CompileShortcodeRules validates rules and prepares them to be applied
+func CompileShortcodeRules(rules []ShortcodeRule) error {
+	names := make(map[string]bool, len(rules))
+	var errs []error
+	for i := range rules {
+		rule := &rules[i]
+		if !_shortcodeNameRegEx.MatchString(rule.Name) {
+			errs = append(errs, fmt.Errorf("rule %d: invalid shortcode name %q", i+1, rule.Name))
+			continue
+		}
+		if names[rule.Name] {
+			errs = append(errs, fmt.Errorf("rule %d: duplicate shortcode %q", i+1, rule.Name))
+		}
+		names[rule.Name] = true
+		if rule.Layout != nil && (path.Ext(rule.Layout.File) != ".html" || path.Base(rule.Layout.File) != rule.Layout.File) {
+			errs = append(errs, fmt.Errorf("rule %s: layout file must be a .html file name, got %q", rule.Name, rule.Layout.File))
+		}
+
+		name := regexp.QuoteMeta(rule.Name)
+		if rule.Enclosing {
+			// WordPress allows to omit the closing tag
+			rule.regex = regexp.MustCompile(`(?s)\[` + name + `((?:\s[^\]]*)?)\](?:(.*?)\[/` + name + `\])?`)
+		} else {
+			rule.regex = regexp.MustCompile(`\[` + name + `((?:\s[^\]]*)?)\]`)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// applyShortcodeRules replaces the WordPress shortcodes of rules in htmlData
+func applyShortcodeRules(rules []ShortcodeRule, htmlData string) string {
+	for _, rule := range rules {
+		htmlData = replaceAllStringSubmatchFunc(rule.regex, htmlData, func(groups []string) string {
+			log.Debug().
+				Str("shortcode", rule.Name).
+				Msg("Applying shortcode rule")
+			values := parseShortcodeAttrs(groups[1])
+			for name, value := range rule.Attributes {
+				if _, ok := values[strings.ToLower(name)]; !ok {
+					values[strings.ToLower(name)] = value
+				}
+			}
+			content := ""
+			if rule.Enclosing {
+				content = groups[2]
+			}
+			return renderShortcodeRule(rule.Template, values, content)
+		})
+	}
+	return htmlData
+}
+
+// parseShortcodeAttrs returns the attributes of a shortcode by name, positional ones by index
+func parseShortcodeAttrs(attrs string) map[string]string {
+	attrs = strings.TrimSuffix(strings.TrimSpace(attrs), "/")
+	values := make(map[string]string)
+	position := 0
+	for _, match := range _shortcodeAttrRegEx.FindAllStringSubmatch(attrs, -1) {
+		switch {
+		case match[1] != "":
+			values[strings.ToLower(match[1])] = match[2]
+		case match[3] != "":
+			values[strings.ToLower(match[3])] = match[4]
+		case match[5] != "":
+			values[strings.ToLower(match[5])] = match[6]
+		default:
+			values[strconv.Itoa(position)] = match[7] + match[8] + match[9]
+			position++
+		}
+	}
+	return values
+}
+
+// renderShortcodeRule returns template with its placeholders replaced, the text of template is kept
+// as is by the Markdown conversion while content is converted
+func renderShortcodeRule(template string, values map[string]string, content string) string {
+	var output strings.Builder
+	for i, part := range strings.Split(template, "${content}") {
+		if i > 0 {
+			output.WriteString(content)
+		}
+		part = _shortcodeRulePlaceholder.ReplaceAllStringFunc(part, func(placeholder string) string {
+			return values[strings.ToLower(placeholder[2:len(placeholder)-1])]
+		})
+		if part != "" {
+			fmt.Fprintf(&output, "<%s>%s</%s>", _rawTag, html.EscapeString(part), _rawTag)
+		}
+	}
+	return output.String()
+}
+
+// getRawTextConverter writes the text of the shortcode rules as is
+func getRawTextConverter() md.Plugin {
+	return func(c *md.Converter) []md.Rule {
+		return []md.Rule{
+			{
+				Filter: []string{_rawTag},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					text := selec.Text()
+					return &text
+				},
+			},
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package hugopage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const _sampleShortcodeRules = `shortcodes:
+  - name: su_note
+    enclosing: true
+    attributes:
+      note_color: "#ffff66"
+    template: '{{< note color="${note_color}" >}}${content}{{< /note >}}'
+    layout:
+      file: note.html
+      content: |
+        <div class="note" style="background: {{ .Get "color" }}">{{ .Inner | .Page.RenderString }}</div>
+  - name: button
+    template: '[**${0}**](${url})'
+  - name: tabs
+    enclosing: true
+    template: "${content}"
+`
+
+func TestShortcodeRules(t *testing.T) {
+	t.Parallel()
+	rulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(rulesPath, []byte(_sampleShortcodeRules), 0o600))
+	rules, err := LoadShortcodeRules(rulesPath)
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+	require.Equal(t, "note.html", rules[0].Layout.File)
+
+	const htmlInput = `<p>[su_note note_color='#d9ecff']Some <em>important</em> text_here[/su_note]</p>
+<p>[su_note]Default[/su_note]</p>
+<p>[button "Sign_up" url=/sign-up/ /]</p>
+<p>[buttons]</p>
+<!-- wp:shortcode -->
+[tabs]
+<!-- /wp:shortcode -->
+<p>Tab</p>
+<!-- wp:shortcode -->
+[/tabs]
+<!-- /wp:shortcode -->`
+	page := Page{options: Options{ShortcodeRules: rules}}
+	markdown, err := page.getMarkdown(nil, htmlInput, nil, PaperModFrontMatter{})
+	require.NoError(t, err)
+	require.Equal(t, `{{< note color="#d9ecff" >}}Some _important_ text\_here{{< /note >}}
+
+{{< note color="#ffff66" >}}Default{{< /note >}}
+
+[**Sign_up**](/sign-up/)
+
+\[buttons\]
+
+Tab`, *markdown)
+	require.Empty(t, page.UnconvertedBlocks())
+}
+
+func TestShortcodeRules_Invalid(t *testing.T) {
+	t.Parallel()
+	err := CompileShortcodeRules([]ShortcodeRule{
+		{Name: "a b", Template: "x"},
+		{Name: "note", Template: "x", Layout: &ShortcodeLayout{File: "../note.html"}},
+		{Name: "note", Template: "y"},
+	})
+	require.ErrorContains(t, err, `invalid shortcode name "a b"`)
+	require.ErrorContains(t, err, `layout file must be a .html file name`)
+	require.ErrorContains(t, err, `duplicate shortcode "note"`)
+}
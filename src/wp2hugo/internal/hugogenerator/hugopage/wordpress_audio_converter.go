@@ -40,6 +40,18 @@ func replaceAudioShortCode(htmlData string) string {
 	return htmlData
 }
 
+// Example:
+// <!-- wp:audio {"id":1337} -->
+// <figure class="wp-block-audio"><audio controls src="/wp-content/uploads/sites/3/2020/07/session_2020-07-02.mp3"></audio></figure>
+// <!-- /wp:audio -->
+func convertAudioBlock(_ *blockRenderer, block *Block) (string, bool) {
+	audio := findElement(parseBlockHTML(block), "audio")
+	if audio == nil || getAttr(audio, "src") == "" {
+		return "", false
+	}
+	return printAudioShortCode(getAttr(audio, "src")), true
+}
+
 func printAudioShortCode(src string) string {
 	// These characters create problems in Hugo's markdown
 	src = strings.ReplaceAll(src, " ", "%20")
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/rs/zerolog/log"
+	"golang.org/x/net/html"
 )
 
 // Example:  [caption id="" align="aligncenter" width="599"]<img class=""
@@ -31,27 +32,6 @@ var _CaptionRegEx2 = regexp.MustCompile(`\[caption [^ ]* align="([^"]+)" width="
 	`(.+?)` +
 	`\[/caption\]`)
 
-// Gutenberg image blocs, no figcaption :
-// <!-- wp:image {"align":"center","id":3875,"sizeSlug":"large","className":"is-style-default"} -->
-// <div class="wp-block-image is-style-default"><figure class="aligncenter size-large"><img src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2016/03/Shooting-Minh-Ly-0155-_DSC0155-Minh-Ly-WEB-1100x1100.jpg" alt="" class="wp-image-3875"/></figure></div>
-// <!-- /wp:image -->
-var _FigureRegexNoCaption = regexp.MustCompile(`(?ms)<!-- wp:image.*?-->` +
-	`.*?<figure.*?` +
-	`<img.*?src="([^"]+)".*?alt="([^"]*)".*?>` +
-	`</figure>.*?` +
-	`<!-- /wp:image -->`)
-
-// Gutenberg image blocs, with figcaption :
-// <!-- wp:image {"align":"center","id":3875,"sizeSlug":"large","className":"is-style-default"} -->
-// <div class="wp-block-image is-style-default"><figure class="aligncenter size-large"><img src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2016/03/Shooting-Minh-Ly-0155-_DSC0155-Minh-Ly-WEB-1100x1100.jpg" alt="" class="wp-image-3875"/><figcaption>Minh-Ly</figcaption></figure></div>
-// <!-- /wp:image -->
-var _FigureRegexCaption = regexp.MustCompile(`(?ms)<!-- wp:image.*?-->` +
-	`.*?<figure.*?` +
-	`<img.*?src="([^"]+)".*?alt="([^"]*)".*?>` +
-	`<figcaption>(.*?)</figcaption>` +
-	`</figure>.*?` +
-	`<!-- /wp:image -->`)
-
 // Converts the WordPress's caption shortcode to Hugo shortcode "figure"
 // https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-faq/#centering-image-in-markdown
 func replaceCaptionWithFigure(htmlData string) string {
@@ -63,13 +43,26 @@ func replaceCaptionWithFigure(htmlData string) string {
 	return htmlData
 }
 
-func replaceImageBlockWithFigure(htmlData string) string {
-	log.Debug().
-		Msg("Replacing Gutenberg image with figure")
+// Example:
+// <!-- wp:image {"align":"center","id":3875,"sizeSlug":"large","className":"is-style-default"} -->
+// <div class="wp-block-image is-style-default"><figure class="aligncenter size-large"><img src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2016/03/Shooting-Minh-Ly-0155-_DSC0155-Minh-Ly-WEB-1100x1100.jpg" alt="" class="wp-image-3875"/><figcaption>Minh-Ly</figcaption></figure></div>
+// <!-- /wp:image -->
+func convertImageBlock(_ *blockRenderer, block *Block) (string, bool) {
+	return getFigureShortCode(parseBlockHTML(block))
+}
 
-	htmlData = replaceAllStringSubmatchFunc(_FigureRegexNoCaption, htmlData, imageBlockReplacementFunction)
-	htmlData = replaceAllStringSubmatchFunc(_FigureRegexCaption, htmlData, imageBlockReplacementFunction)
-	return htmlData
+// getFigureShortCode returns the figure shortcode of the first image of node and its caption, if any
+func getFigureShortCode(node *html.Node) (string, bool) {
+	img := findElement(node, "img")
+	if img == nil || getAttr(img, "src") == "" {
+		return "", false
+	}
+	var caption *string
+	if figcaption := findElement(node, "figcaption"); figcaption != nil {
+		text := strings.TrimSpace(renderChildren(figcaption))
+		caption = &text
+	}
+	return printFigureShortCode(getAttr(img, "src"), getAttr(img, "alt"), caption), true
 }
 
 func sanitizeLinks(src string) string {
@@ -81,32 +74,23 @@ func sanitizeLinks(src string) string {
 
 func sanitizeQuotes(alt string) string {
 	const replacementQuote = "'"
-	for _, s := range []string{"\"", "“", "”", "&quot;"} {
+	for _, s := range []string{"\"", "“", "”", "&quot;", "&#34;"} {
 		alt = strings.ReplaceAll(alt, s, replacementQuote)
 	}
 	return alt
 }
 
-func imageBlockReplacementFunction(groups []string) string {
-	src := sanitizeLinks(groups[1])
-	alt := ""
-	var caption string
-
-	if len(groups) > 2 {
-		alt = groups[2]
-	}
-	if len(groups) > 3 {
-		caption = groups[3]
-		if alt == "" {
-			alt = caption
-		}
-	} else {
-		caption = alt
+// printFigureShortCode returns the figure shortcode of an image, the alt text is the caption if there is none
+func printFigureShortCode(src string, alt string, caption *string) string {
+	src = sanitizeLinks(src)
+	if caption == nil {
+		caption = &alt
+	} else if alt == "" {
+		alt = *caption
 	}
 
 	alt = sanitizeQuotes(alt)
-	caption = sanitizeQuotes(caption)
-	return fmt.Sprintf(`{{< figure src="%s" alt="%s" caption="%s" >}}`, src, alt, caption)
+	return fmt.Sprintf(`{{< figure src="%s" alt="%s" caption="%s" >}}`, src, alt, sanitizeQuotes(*caption))
 }
 
 func captionReplacementFunction(groups []string) string {
@@ -37,10 +37,6 @@ func TestRegExMatches(t *testing.T) {
 	require.False(t, _CaptionRegEx1.MatchString(example3), "RegEx should match")
 
 	require.True(t, _CaptionRegEx2.MatchString(example3), "RegEx should match")
-
-	require.True(t, _FigureRegexCaption.MatchString(example5), "Regex should match")
-	// This test is failing see https://github.com/ashishb/wp2hugo/pull/177
-	// require.False(t, _FigureRegexNoCaption.MatchString(example5), "Regex should match")
 }
 
 func TestCaption4Replace(t *testing.T) {
@@ -49,8 +45,15 @@ func TestCaption4Replace(t *testing.T) {
 	require.Equal(t, expected, replaceCaptionWithFigure(example4))
 }
 
-// This test is failing see https://github.com/ashishb/wp2hugo/pull/177
-// func TestFigure5Replace(t *testing.T) {
-//	expected := "\n{{< figure src=\"https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2016/03/Shooting-Minh-Ly-0155-%5FDSC0155-Minh-Ly-WEB-1100x1100.jpg\" alt=\"\" caption=\"Minh-Ly\" >}}\n"
-//	require.Equal(t, expected, replaceImageBlockWithFigure(example5))
-//}
+func TestFigure5Replace(t *testing.T) {
+	t.Parallel()
+	// Ref: https://github.com/ashishb/wp2hugo/pull/177
+	expected := "\n{{< figure src=\"https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2016/03/Shooting-Minh-Ly-0155-%5FDSC0155-Minh-Ly-WEB-1100x1100.jpg\" alt=\"Minh-Ly\" caption=\"Minh-Ly\" >}}\n"
+	converted, unconverted := convertBlocks(example5, Options{})
+	require.Equal(t, expected, converted)
+	require.Empty(t, unconverted)
+
+	// No caption, alt text with quotes
+	converted, _ = convertBlocks(`<!-- wp:image {"id":1} --><figure class="wp-block-image"><img src="/a.jpg" alt="A &quot;b&quot;"/></figure><!-- /wp:image -->`, Options{})
+	require.Equal(t, `{{< figure src="/a.jpg" alt="A 'b'" caption="A 'b'" >}}`, converted)
+}
@@ -0,0 +1,226 @@
+package hugopage
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/rs/zerolog/log"
+)
+
+// embedProvider converts the URLs of a third-party provider to a Hugo shortcode
+type embedProvider struct {
+	// Slug of the provider in Gutenberg embed blocks, e.g. "vimeo"
+	slug string
+	// Shown by the privacy placeholder
+	name string
+	// URLs of the provider, the shortcode and the player are built from its groups
+	urlRegex  *regexp.Regexp
+	shortcode func(groups []string) string
+	// URL of the iframe of the player, loaded on click in privacy mode
+	playerURL func(groups []string) string
+}
+
+// Providers of the embeds, Hugo has built-in shortcodes for YouTube, Vimeo, X and Instagram,
+// WP2Hugo writes the other ones
+// Ref: https://gohugo.io/shortcodes/
+var _embedProviders = []embedProvider{
+	{
+		slug:     "youtube",
+		name:     "YouTube",
+		urlRegex: regexp.MustCompile(`^https?://(?:www\.|m\.)?(?:youtube(?:-nocookie)?\.com/(?:watch\?(?:.*&)?v=|embed/|shorts/|live/)|youtu\.be/)([\w-]+)`),
+		shortcode: func(groups []string) string {
+			return fmt.Sprintf(`{{< youtube %s >}}`, groups[1])
+		},
+		playerURL: func(groups []string) string {
+			return "https://www.youtube-nocookie.com/embed/" + groups[1]
+		},
+	},
+	{
+		slug:     "vimeo",
+		name:     "Vimeo",
+		urlRegex: regexp.MustCompile(`^https?://(?:www\.|player\.)?vimeo\.com/(?:video/)?(\d+)`),
+		shortcode: func(groups []string) string {
+			return fmt.Sprintf(`{{< vimeo %s >}}`, groups[1])
+		},
+		playerURL: func(groups []string) string {
+			return "https://player.vimeo.com/video/" + groups[1] + "?dnt=1"
+		},
+	},
+	{
+		slug:     "twitter",
+		name:     "X",
+		urlRegex: regexp.MustCompile(`^https?://(?:www\.|mobile\.)?(?:twitter|x)\.com/(\w+)/status(?:es)?/(\d+)`),
+		shortcode: func(groups []string) string {
+			return fmt.Sprintf(`{{< x user="%s" id="%s" >}}`, groups[1], groups[2])
+		},
+		playerURL: func(groups []string) string {
+			return "https://platform.twitter.com/embed/Tweet.html?dnt=true&id=" + groups[2]
+		},
+	},
+	{
+		slug:     "instagram",
+		name:     "Instagram",
+		urlRegex: regexp.MustCompile(`^https?://(?:www\.)?instagram\.com/(?:[\w.]+/)?(?:p|reel|tv)/([\w-]+)`),
+		shortcode: func(groups []string) string {
+			return fmt.Sprintf(`{{< instagram %s >}}`, groups[1])
+		},
+		playerURL: func(groups []string) string {
+			return "https://www.instagram.com/p/" + groups[1] + "/embed"
+		},
+	},
+	{
+		slug:     "soundcloud",
+		name:     "SoundCloud",
+		urlRegex: regexp.MustCompile(`^(https?://(?:www\.|m\.)?soundcloud\.com/[\w-]+/[\w/-]+)`),
+		shortcode: func(groups []string) string {
+			return fmt.Sprintf(`{{< soundcloud url="%s" >}}`, groups[1])
+		},
+		playerURL: func(groups []string) string {
+			return "https://w.soundcloud.com/player/?url=" + url.QueryEscape(groups[1])
+		},
+	},
+	{
+		slug:     "spotify",
+		name:     "Spotify",
+		urlRegex: regexp.MustCompile(`^https?://open\.spotify\.com/(?:embed/)?(track|album|playlist|artist|episode|show)/(\w+)`),
+		shortcode: func(groups []string) string {
+			return fmt.Sprintf(`{{< spotify type="%s" id="%s" >}}`, groups[1], groups[2])
+		},
+		playerURL: func(groups []string) string {
+			return "https://open.spotify.com/embed/" + groups[1] + "/" + groups[2]
+		},
+	},
+	{
+		slug:     "tiktok",
+		name:     "TikTok",
+		urlRegex: regexp.MustCompile(`^https?://(?:www\.)?tiktok\.com/(?:@[\w.-]+/video|embed/v2)/(\d+)`),
+		shortcode: func(groups []string) string {
+			return fmt.Sprintf(`{{< tiktok id="%s" >}}`, groups[1])
+		},
+		playerURL: func(groups []string) string {
+			return "https://www.tiktok.com/embed/v2/" + groups[1]
+		},
+	},
+	{
+		slug:     "codepen",
+		name:     "CodePen",
+		urlRegex: regexp.MustCompile(`^https?://codepen\.io/([\w-]+)/(?:pen|embed)/(\w+)`),
+		shortcode: func(groups []string) string {
+			return fmt.Sprintf(`{{< codepen user="%s" id="%s" >}}`, groups[1], groups[2])
+		},
+		playerURL: func(groups []string) string {
+			return "https://codepen.io/" + groups[1] + "/embed/" + groups[2] + "?default-tab=result"
+		},
+	},
+}
+
+var (
+	// Plain-text URL on its own line, which WordPress turns into an embed
+	_embedURLLineRegEx = regexp.MustCompile(`(?m)^[ \t]*(https?://\S+?)[ \t]*$`)
+	// [embed]https://vimeo.com/76979871[/embed], with the brackets escaped in Markdown
+	_embedTagRegEx = regexp.MustCompile(`(?m)\\?\[embed\\?](.*?)\\?\[/embed\\?]`)
+)
+
+// getEmbedShortCode returns the shortcode of the embed of embedURL, or a placeholder loading it on click
+// in privacy mode. The provider of slug is tried first, it returns false if no provider matches
+func getEmbedShortCode(slug string, embedURL string, privacy bool) (string, bool) {
+	embedURL = strings.TrimSpace(embedURL)
+	for _, provider := range getEmbedProviders(slug) {
+		groups := provider.urlRegex.FindStringSubmatch(embedURL)
+		if groups == nil {
+			continue
+		}
+		log.Debug().
+			Str("provider", provider.slug).
+			Str("url", embedURL).
+			Msg("Embed found")
+		if privacy {
+			return printEmbedPlaceholder(provider.name, provider.playerURL(groups), embedURL), true
+		}
+		return provider.shortcode(groups), true
+	}
+	return "", false
+}
+
+func getEmbedProviders(slug string) []embedProvider {
+	providers := make([]embedProvider, 0, len(_embedProviders))
+	for _, provider := range _embedProviders {
+		if provider.slug == slug {
+			providers = append([]embedProvider{provider}, providers...)
+		} else {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+func printEmbedPlaceholder(name string, playerURL string, embedURL string) string {
+	return fmt.Sprintf(`{{< embed-placeholder provider="%s" src="%s" url="%s" >}}`, name, playerURL, embedURL)
+}
+
+// Gutenberg embed into figure:
+// <!-- wp:embed {"url":"https://www.youtube.com/watch?v=7l6FjphZXsk","type":"video","providerNameSlug":"youtube","responsive":true,"align":"full","className":"wp-embed-aspect-16-9 wp-has-aspect-ratio"} -->
+// <figure class="wp-block-embed alignfull is-type-video is-provider-youtube wp-block-embed-youtube wp-embed-aspect-16-9 wp-has-aspect-ratio"><div class="wp-block-embed__wrapper">
+// https://www.youtube.com/watch?v=7l6FjphZXsk
+// </div></figure>
+// <!-- /wp:embed -->
+func convertEmbedBlock(r *blockRenderer, block *Block) (string, bool) {
+	embedURL := block.AttrString("url")
+	if embedURL == "" {
+		embedURL = getText(findElementWithClass(parseBlockHTML(block), "wp-block-embed__wrapper"))
+	}
+	return getEmbedShortCode(getEmbedProvider(block), embedURL, r.options.EmbedPrivacy)
+}
+
+// getEmbedProvider returns the provider of an embed block, e.g. "youtube"
+func getEmbedProvider(block *Block) string {
+	if provider := block.AttrString("providerNameSlug"); provider != "" {
+		return provider
+	}
+	if provider, ok := strings.CutPrefix(block.Name, "core-embed/"); ok {
+		return provider
+	}
+	if embedURL, err := url.Parse(block.AttrString("url")); err == nil && embedURL.Host != "" {
+		return strings.TrimPrefix(embedURL.Hostname(), "www.")
+	}
+	return "unknown"
+}
+
+// replacePlaintextEmbedURLs replaces the URLs of known providers on their own line, within [embed] or not,
+// with their shortcode
+func replacePlaintextEmbedURLs(markdown string, privacy bool) string {
+	log.Debug().
+		Msg("Replacing embed URLs with shortcodes")
+
+	markdown = _embedTagRegEx.ReplaceAllString(markdown, "$1")
+	return _embedURLLineRegEx.ReplaceAllStringFunc(markdown, func(line string) string {
+		// Undo the Markdown escapes, e.g. https://youtu.be/8K7PdBH3W\_I
+		embedURL := _hugoShortcodeEscapes.ReplaceAllString(strings.TrimSpace(line), "$1")
+		if shortcode, ok := getEmbedShortCode("", embedURL, privacy); ok {
+			return shortcode
+		}
+		return line
+	})
+}
+
+// getEmbedIframeConverter converts the iframes of the players of known providers
+func getEmbedIframeConverter(privacy bool) md.Plugin {
+	return func(c *md.Converter) []md.Rule {
+		return []md.Rule{
+			{
+				Filter: []string{"iframe"},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					shortcode, ok := getEmbedShortCode("", selec.AttrOr("src", ""), privacy)
+					if !ok {
+						return nil
+					}
+					return &shortcode
+				},
+			},
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEmbedShortCode(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{"https://youtu.be/8K7PdBH3W_I", `{{< youtube 8K7PdBH3W_I >}}`},
+		{"https://www.youtube.com/shorts/abc-123", `{{< youtube abc-123 >}}`},
+		{"https://vimeo.com/76979871", `{{< vimeo 76979871 >}}`},
+		{"https://player.vimeo.com/video/76979871?h=1", `{{< vimeo 76979871 >}}`},
+		{"https://twitter.com/GoHugoIO/status/1315233626070503424", `{{< x user="GoHugoIO" id="1315233626070503424" >}}`},
+		{"https://x.com/GoHugoIO/status/1315233626070503424", `{{< x user="GoHugoIO" id="1315233626070503424" >}}`},
+		{"https://www.instagram.com/p/CxOWiQNP2MO/", `{{< instagram CxOWiQNP2MO >}}`},
+		{"https://soundcloud.com/artist/a-track", `{{< soundcloud url="https://soundcloud.com/artist/a-track" >}}`},
+		{"https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=1", `{{< spotify type="track" id="4uLU6hMCjMI75M1A2tKUQC" >}}`},
+		{"https://www.tiktok.com/@someone/video/7106594312292453675", `{{< tiktok id="7106594312292453675" >}}`},
+		{"https://codepen.io/someone/pen/abcDEF", `{{< codepen user="someone" id="abcDEF" >}}`},
+	}
+	for _, testCase := range testCases {
+		shortcode, ok := getEmbedShortCode("", testCase.url, false)
+		require.True(t, ok, testCase.url)
+		require.Equal(t, testCase.expected, shortcode)
+	}
+
+	_, ok := getEmbedShortCode("flickr", "https://www.flickr.com/photos/a/1", false)
+	require.False(t, ok)
+
+	shortcode, ok := getEmbedShortCode("vimeo", "https://vimeo.com/76979871", true)
+	require.True(t, ok)
+	require.Equal(t, `{{< embed-placeholder provider="Vimeo" src="https://player.vimeo.com/video/76979871?dnt=1" url="https://vimeo.com/76979871" >}}`, shortcode)
+}
+
+func TestEmbeds(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:embed {"url":"https://vimeo.com/76979871","type":"video","providerNameSlug":"vimeo"} -->
+<figure class="wp-block-embed is-type-video is-provider-vimeo"><div class="wp-block-embed__wrapper">
+https://vimeo.com/76979871
+</div></figure>
+<!-- /wp:embed -->
+
+<p>[embed]https://open.spotify.com/album/1DFixLWuPkv3KT3TnV35m3[/embed]</p>
+
+<p>https://www.instagram.com/p/CxOWiQNP2MO/</p>
+
+<p>Read https://vimeo.com/1 later</p>
+
+<iframe src="https://player.vimeo.com/video/2" width="640" height="360"></iframe>`
+	testMarkdownExtractor(t, htmlInput, `{{< vimeo 76979871 >}}
+
+{{< spotify type="album" id="1DFixLWuPkv3KT3TnV35m3" >}}
+
+{{< instagram CxOWiQNP2MO >}}
+
+Read https://vimeo.com/1 later
+
+{{< vimeo 2 >}}`)
+}
+
+func TestEmbeds_Privacy(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:embed {"url":"https://www.youtube.com/watch?v=7l6FjphZXsk","type":"video","providerNameSlug":"youtube"} -->
+<figure class="wp-block-embed"><div class="wp-block-embed__wrapper">
+https://www.youtube.com/watch?v=7l6FjphZXsk
+</div></figure>
+<!-- /wp:embed -->
+
+<p>https://youtu.be/8K7PdBH3W_I</p>
+
+<iframe src="https://www.youtube.com/embed/Wz6ml5SpkKM"></iframe>`
+	page := Page{options: Options{EmbedPrivacy: true}}
+	markdown, err := page.getMarkdown(nil, htmlInput, nil, PaperModFrontMatter{})
+	require.NoError(t, err)
+	require.Equal(t, `{{< embed-placeholder provider="YouTube" src="https://www.youtube-nocookie.com/embed/7l6FjphZXsk" url="https://www.youtube.com/watch?v=7l6FjphZXsk" >}}
+
+{{< embed-placeholder provider="YouTube" src="https://www.youtube-nocookie.com/embed/8K7PdBH3W_I" url="https://youtu.be/8K7PdBH3W_I" >}}
+
+{{< embed-placeholder provider="YouTube" src="https://www.youtube-nocookie.com/embed/Wz6ml5SpkKM" url="https://www.youtube.com/embed/Wz6ml5SpkKM" >}}`, *markdown)
+}
+
+func TestEmbeds_PrivacyYoutubeTags(t *testing.T) {
+	t.Parallel()
+	// Privacy mode converts the same inputs as the normal mode
+	const htmlInput = `<p>[youtube http://www.youtube.com/watch?v=1cNDSPutas8]</p>
+
+<p>httpv://www.youtube.com/watch?v=8K7PdBH3W_I</p>
+
+<p>Listen to httpa://www.youtube.com/watch?v=gJ7AAJXHeeg now</p>`
+	page := Page{options: Options{}}
+	markdown, err := page.getMarkdown(nil, htmlInput, nil, PaperModFrontMatter{})
+	require.NoError(t, err)
+	require.Equal(t, `{{< youtube 1cNDSPutas8 >}}
+
+{{< youtube 8K7PdBH3W_I >}}
+
+Listen to {{< youtube gJ7AAJXHeeg >}} now`, *markdown)
+
+	page = Page{options: Options{EmbedPrivacy: true}}
+	markdown, err = page.getMarkdown(nil, htmlInput, nil, PaperModFrontMatter{})
+	require.NoError(t, err)
+	require.Equal(t, `{{< embed-placeholder provider="YouTube" src="https://www.youtube-nocookie.com/embed/1cNDSPutas8" url="http://www.youtube.com/watch?v=1cNDSPutas8" >}}
+
+{{< embed-placeholder provider="YouTube" src="https://www.youtube-nocookie.com/embed/8K7PdBH3W_I" url="https://www.youtube.com/watch?v=8K7PdBH3W_I" >}}
+
+Listen to {{< embed-placeholder provider="YouTube" src="https://www.youtube-nocookie.com/embed/gJ7AAJXHeeg" url="https://www.youtube.com/watch?v=gJ7AAJXHeeg" >}} now`, *markdown)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -26,16 +27,14 @@ var (
 // <!-- wp:gallery {"ids":[14951,14949],"imageCrop":false,"linkTo":"file","sizeSlug":"full","align":"wide"} -->
 // <figure class="wp-block-gallery alignwide columns-2"><ul class="blocks-gallery-grid"><li class="blocks-gallery-item"><figure><a href="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/haute-diffusion-1.jpg"><img src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/haute-diffusion-1.jpg" alt="" data-id="14951" data-full-url="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/haute-diffusion-1.jpg" data-link="https://photo.aurelienpierre.com/la-photo-de-studio-pour-les-pauvres/haute-diffusion-1/" class="wp-image-14951"/></a><figcaption class="blocks-gallery-item__caption">Lumière fortement diffusée</figcaption></figure></li><li class="blocks-gallery-item"><figure><a href="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/faible-diffusion.jpg"><img src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/faible-diffusion.jpg" alt="" data-id="14949" data-link="https://photo.aurelienpierre.com/la-photo-de-studio-pour-les-pauvres/faible-diffusion/" class="wp-image-14949"/></a><figcaption class="blocks-gallery-item__caption">Lumière faiblement diffusée<br /></figcaption></figure></li></ul></figure>
 // <!-- /wp:gallery -->
-var _GutenbergGalleryRegEx = regexp.MustCompile(`(?ms)<!-- wp:gallery.*?-->(.*?)<!-- /wp:gallery -->`)
-
-var _innerFigureNoCaption = regexp.MustCompile(`(?ms)<figure.*?` +
-	`<img.*?src="([^"]+)".*?alt="([^"]*)".*?>.*?` +
-	`.*?</figure>`)
-
-var _innerFigureCaption = regexp.MustCompile(`(?ms)<figure.*?` +
-	`<img.*?src="([^"]+)".*?alt="([^"]*)".*?>.*?` +
-	`<figcaption.*?>(.*?)</figcaption>` +
-	`.*?</figure>`)
+//
+// Since WordPress 5.9, the images are inner image blocks:
+// <!-- wp:gallery {"columns":2,"linkTo":"none"} -->
+// <figure class="wp-block-gallery has-nested-images columns-2"><!-- wp:image {"id":14951} -->
+// <figure class="wp-block-image"><img src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/haute-diffusion-1.jpg" alt="" class="wp-image-14951"/></figure>
+// <!-- /wp:image --></figure>
+// <!-- /wp:gallery -->
+var _galleryColumnsRegEx = regexp.MustCompile(`columns-(\d+)`)
 
 var errGalleryWithNoIDs = errors.New("no image IDs found in gallery shortcode")
 
@@ -58,11 +57,27 @@ func replaceGalleryWithFigure(provider ImageURLProvider, attachmentIDs []string,
 	return htmlData
 }
 
-func replaceGutembergGalleryWithFigure(htmlData string) string {
-	log.Debug().
-		Msg("Replacing Gutenberg gallery with figures")
+func convertGalleryBlock(r *blockRenderer, block *Block) (string, bool) {
+	var figures []string
+	for _, inner := range block.InnerBlocks {
+		if inner.Name != "core/image" {
+			continue
+		}
+		if figure, ok := getFigureShortCode(parseBlockHTML(inner)); ok {
+			figures = append(figures, figure)
+		}
+	}
+	if len(figures) == 0 {
+		// Images of the HTML of the block
+		return gutenbergGalleryReplacementFunction(r.renderInner(block)), true
+	}
 
-	return replaceAllStringSubmatchFunc(_GutenbergGalleryRegEx, htmlData, gutenbergGalleryReplacementFunction)
+	// WordPress shows up to 3 columns by default
+	cols := strconv.Itoa(min(len(figures), 3))
+	if columns := block.AttrString("columns"); columns != "" {
+		cols = columns
+	}
+	return printGalleryShortCode(cols, figures), true
 }
 
 // Recursively find <figure> nodes
@@ -85,21 +100,12 @@ func renderNode(n *html.Node) string {
 	}
 }
 
-func replaceGalleryFigure(htmlData string) string {
-	log.Debug().
-		Msg("Replacing Gutenberg image with figure")
-
-	htmlData = replaceAllStringSubmatchFunc(_innerFigureCaption, htmlData, imageBlockReplacementFunction)
-	htmlData = replaceAllStringSubmatchFunc(_innerFigureNoCaption, htmlData, imageBlockReplacementFunction)
-	return htmlData
-}
-
-func gutenbergGalleryReplacementFunction(groups []string) string {
+func gutenbergGalleryReplacementFunction(galleryHTML string) string {
 	// Because <figure> elements can be recursively nested,
 	// we can't use RegEx, we need an HTML parser.
-	doc, err := html.Parse(strings.NewReader(groups[1]))
+	doc, err := html.Parse(strings.NewReader(galleryHTML))
 	if err != nil {
-		return groups[0]
+		return galleryHTML
 	}
 	// Produce a flat list of (possibly nested) figures
 	var figures []*html.Node
@@ -122,8 +128,7 @@ func gutenbergGalleryReplacementFunction(groups []string) string {
 		}
 
 		if classAttr != "" {
-			re := regexp.MustCompile(`columns-(\d+)`)
-			matches := re.FindStringSubmatch(classAttr)
+			matches := _galleryColumnsRegEx.FindStringSubmatch(classAttr)
 			if len(matches) == 2 {
 				cols = matches[1]
 			} else {
@@ -135,16 +140,22 @@ func gutenbergGalleryReplacementFunction(groups []string) string {
 
 		// If we have an inner figure, parse it with our standard methods
 		if isInner {
-			inners = append(inners, replaceGalleryFigure(renderNode(f)))
+			if figure, ok := getFigureShortCode(f); ok {
+				inners = append(inners, figure)
+			}
 		}
 	}
+	return printGalleryShortCode(cols, inners)
+}
 
+// printGalleryShortCode returns the gallery shortcode of figures, which are figure shortcodes
+func printGalleryShortCode(cols string, figures []string) string {
 	var output strings.Builder
 	output.WriteString("<br>") // This will get converted to newline later on
 	fmt.Fprintf(&output, `{{< gallery cols="%s" >}}`, cols)
 	output.WriteString("<br>") // This will get converted to newline later on
 
-	for _, f := range inners {
+	for _, f := range figures {
 		output.WriteString(f)
 		output.WriteString("<br>") // This will get converted to newline later on
 	}
@@ -27,5 +27,6 @@ func TestReplaceGutenbergGallery(t *testing.T) {
 </figure>
 <!-- /wp:gallery -->`
 	const expected = `<br>{{< gallery cols="2" >}}<br>{{< figure src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/haute-diffusion-1.jpg" alt="Lumière fortement diffusée" caption="Lumière fortement diffusée" >}}<br>{{< figure src="https://photo.aurelienpierre.com/wp-content/uploads/sites/3/2020/02/faible-diffusion.jpg" alt="Lumière faiblement diffusée<br/>" caption="Lumière faiblement diffusée<br/>" >}}<br>{{< /gallery >}}<br>`
-	require.Equal(t, expected, replaceGutembergGalleryWithFigure(htmlData))
+	converted, _ := convertBlocks(htmlData, Options{})
+	require.Equal(t, expected, converted)
 }
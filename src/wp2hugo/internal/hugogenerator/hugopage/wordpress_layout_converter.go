@@ -0,0 +1,140 @@
+package hugopage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Example:
+// <!-- wp:columns -->
+// <div class="wp-block-columns"><!-- wp:column {"width":"33.33%"} -->
+// <div class="wp-block-column" style="flex-basis:33.33%"><!-- wp:paragraph --><p>One</p><!-- /wp:paragraph --></div>
+// <!-- /wp:column --></div>
+// <!-- /wp:columns -->
+func convertColumnsBlock(r *blockRenderer, block *Block) (string, bool) {
+	return printEnclosingShortCode("columns", "", r.renderInnerBlocks(block)), true
+}
+
+func convertColumnBlock(r *blockRenderer, block *Block) (string, bool) {
+	width := block.AttrString("width")
+	if _, ok := block.Attrs["width"].(json.Number); ok {
+		// Before WordPress 5.5, the width was a percentage
+		width += "%"
+	}
+	return printColumnShortCode(width, r.renderInnerBlocks(block)), true
+}
+
+func printColumnShortCode(width string, content string) string {
+	params := ""
+	if width != "" {
+		params = fmt.Sprintf(` width="%s"`, width)
+	}
+	return printEnclosingShortCode("column", params, content)
+}
+
+// Example:
+// <!-- wp:media-text {"mediaId":7,"mediaType":"image","mediaPosition":"right","mediaWidth":40} -->
+// <div class="wp-block-media-text has-media-on-the-right"><div class="wp-block-media-text__content"><!-- wp:paragraph --><p>Text</p><!-- /wp:paragraph --></div><figure class="wp-block-media-text__media"><img src="/wp-content/uploads/a.jpg" alt="A" class="wp-image-7"/></figure></div>
+// <!-- /wp:media-text -->
+func convertMediaTextBlock(r *blockRenderer, block *Block) (string, bool) {
+	media := findElementWithClass(parseBlockHTML(block), "wp-block-media-text__media")
+	var mediaShortCode string
+	if video := findElement(media, "video"); video != nil && getAttr(video, "src") != "" {
+		mediaShortCode = printVideoShortCode(getAttr(video, "src"))
+	} else if figure, ok := getFigureShortCode(media); ok {
+		mediaShortCode = figure
+	} else {
+		return "", false
+	}
+
+	mediaWidth := 50
+	if _, ok := block.Attrs["mediaWidth"]; ok {
+		mediaWidth = block.AttrInt("mediaWidth")
+	}
+	columns := []string{
+		printColumnShortCode(strconv.Itoa(mediaWidth)+"%", "<p>"+mediaShortCode+"</p>"),
+		printColumnShortCode(strconv.Itoa(100-mediaWidth)+"%", r.renderInnerBlocks(block)),
+	}
+	if block.AttrString("mediaPosition") == "right" {
+		columns[0], columns[1] = columns[1], columns[0]
+	}
+	return printEnclosingShortCode("columns", "", strings.Join(columns, "")), true
+}
+
+// Example:
+// <!-- wp:buttons -->
+// <div class="wp-block-buttons"><!-- wp:button -->
+// <div class="wp-block-button"><a class="wp-block-button__link wp-element-button" href="https://example.com/signup" target="_blank" rel="noreferrer noopener">Sign up</a></div>
+// <!-- /wp:button --></div>
+// <!-- /wp:buttons -->
+func convertButtonsBlock(r *blockRenderer, block *Block) (string, bool) {
+	return printEnclosingShortCode("buttons", "", r.renderInnerBlocks(block)), true
+}
+
+func convertButtonBlock(_ *blockRenderer, block *Block) (string, bool) {
+	link := findElementWithClass(parseBlockHTML(block), "wp-block-button__link")
+	if link == nil {
+		return "", false
+	}
+	params := fmt.Sprintf(`href="%s" text="%s"`, getAttr(link, "href"), sanitizeQuotes(strings.TrimSpace(getText(link))))
+	if getAttr(link, "target") == "_blank" {
+		params += ` target="_blank"`
+	}
+	return fmt.Sprintf(`<p>{{< button %s >}}</p>`, params), true
+}
+
+// Example:
+// <!-- wp:cover {"url":"/wp-content/uploads/a.jpg","id":9,"dimRatio":30} -->
+// <div class="wp-block-cover"><span aria-hidden="true" class="wp-block-cover__background has-background-dim-30 has-background-dim"></span><img class="wp-block-cover__image-background wp-image-9" alt="" src="/wp-content/uploads/a.jpg" data-object-fit="cover"/><div class="wp-block-cover__inner-container"><!-- wp:paragraph --><p>Title</p><!-- /wp:paragraph --></div></div>
+// <!-- /wp:cover -->
+func convertCoverBlock(r *blockRenderer, block *Block) (string, bool) {
+	if block.AttrString("backgroundType") == "video" {
+		return "", false
+	}
+	doc := parseBlockHTML(block)
+	src := block.AttrString("url")
+	if img := findElementWithClass(doc, "wp-block-cover__image-background"); src == "" && img != nil {
+		src = getAttr(img, "src")
+	}
+	dim := 50
+	if _, ok := block.Attrs["dimRatio"]; ok {
+		dim = block.AttrInt("dimRatio")
+	}
+
+	content := r.renderInnerBlocks(block)
+	if len(block.InnerBlocks) == 0 {
+		// Before WordPress 5.3, the cover had a single paragraph
+		if text := findElementWithClass(doc, "wp-block-cover-text"); text != nil {
+			content = "<p>" + renderChildren(text) + "</p>"
+		}
+	}
+
+	params := fmt.Sprintf(` dim="%d"`, dim)
+	if src != "" {
+		params = fmt.Sprintf(` src="%s"`, sanitizeLinks(src)) + params
+	}
+	return printEnclosingShortCode("cover", params, content), true
+}
+
+// printEnclosingShortCode returns the shortcode name around content, each on its own line
+func printEnclosingShortCode(name string, params string, content string) string {
+	return fmt.Sprintf("<p>{{< %s%s >}}</p>%s<p>{{< /%s >}}</p>", name, params, content, name)
+}
+
+// getChildElements returns the elements directly inside of node
+func getChildElements(node *html.Node) []*html.Node {
+	var elements []*html.Node
+	if node == nil {
+		return elements
+	}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			elements = append(elements, c)
+		}
+	}
+	return elements
+}
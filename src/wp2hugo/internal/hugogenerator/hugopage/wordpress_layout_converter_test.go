@@ -0,0 +1,104 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnsBlock(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:columns -->
+<div class="wp-block-columns"><!-- wp:column {"width":"33.33%"} -->
+<div class="wp-block-column" style="flex-basis:33.33%"><!-- wp:paragraph --><p>One <em>1</em></p><!-- /wp:paragraph --></div>
+<!-- /wp:column -->
+
+<!-- wp:column {"width":66.66} -->
+<div class="wp-block-column" style="flex-basis:66.66%"><!-- wp:image {"id":1} --><figure class="wp-block-image"><img src="/wp-content/uploads/a_b.jpg" alt="A"/></figure><!-- /wp:image --></div>
+<!-- /wp:column --></div>
+<!-- /wp:columns -->`
+	testMarkdownExtractor(t, htmlInput, `{{< columns >}}
+
+{{< column width="33.33%" >}}
+
+One _1_
+
+{{< /column >}}
+
+{{< column width="66.66%" >}}
+
+{{< figure src="/wp-content/uploads/a%5Fb.jpg" alt="A" caption="A" >}}
+
+{{< /column >}}
+
+{{< /columns >}}`)
+}
+
+func TestMediaTextBlock(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:media-text {"mediaId":7,"mediaType":"image","mediaPosition":"right","mediaWidth":40} -->
+<div class="wp-block-media-text has-media-on-the-right"><div class="wp-block-media-text__content"><!-- wp:paragraph --><p>Text</p><!-- /wp:paragraph --></div><figure class="wp-block-media-text__media"><img src="/wp-content/uploads/a.jpg" alt="A" class="wp-image-7"/></figure></div>
+<!-- /wp:media-text -->`
+	testMarkdownExtractor(t, htmlInput, `{{< columns >}}
+
+{{< column width="60%" >}}
+
+Text
+
+{{< /column >}}
+
+{{< column width="40%" >}}
+
+{{< figure src="/wp-content/uploads/a.jpg" alt="A" caption="A" >}}
+
+{{< /column >}}
+
+{{< /columns >}}`)
+}
+
+func TestButtonsBlock(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:buttons -->
+<div class="wp-block-buttons"><!-- wp:button -->
+<div class="wp-block-button"><a class="wp-block-button__link wp-element-button" href="https://example.com/sign_up" target="_blank" rel="noreferrer noopener">Sign <strong>up</strong> "now"</a></div>
+<!-- /wp:button -->
+
+<!-- wp:button -->
+<div class="wp-block-button"><a class="wp-block-button__link wp-element-button" href="/about/">About</a></div>
+<!-- /wp:button --></div>
+<!-- /wp:buttons -->`
+	testMarkdownExtractor(t, htmlInput, `{{< buttons >}}
+
+{{< button href="/sign_up" text="Sign up 'now'" target="_blank" >}}
+
+{{< button href="/about/" text="About" >}}
+
+{{< /buttons >}}`)
+}
+
+func TestCoverBlock(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:cover {"url":"https://example.com/wp-content/uploads/a.jpg","id":9,"dimRatio":30} -->
+<div class="wp-block-cover"><span aria-hidden="true" class="wp-block-cover__background has-background-dim-30 has-background-dim"></span><img class="wp-block-cover__image-background wp-image-9" alt="" src="https://example.com/wp-content/uploads/a.jpg" data-object-fit="cover"/><div class="wp-block-cover__inner-container"><!-- wp:heading --><h2>Title</h2><!-- /wp:heading --></div></div>
+<!-- /wp:cover -->`
+	const markdownOutput = `{{< cover src="/wp-content/uploads/a.jpg" dim="30" >}}
+
+## Title
+
+{{< /cover >}}`
+	testMarkdownExtractor(t, htmlInput, markdownOutput)
+
+	page := Page{markdown: markdownOutput}
+	require.Equal(t, []string{"/wp-content/uploads/a.jpg"}, page.WPMediaLinks())
+
+	// Before WordPress 5.3
+	converted, unconverted := convertBlocks(`<!-- wp:cover {"url":"/a.jpg"} --><div class="wp-block-cover has-background-dim" style="background-image:url(/a.jpg)"><p class="wp-block-cover-text">Old</p></div><!-- /wp:cover -->`, Options{})
+	require.Equal(t, `<p>{{< cover src="/a.jpg" dim="50" >}}</p><p>Old</p><p>{{< /cover >}}</p>`, converted)
+	require.Empty(t, unconverted)
+}
+
+func TestCoverBlock_Video(t *testing.T) {
+	t.Parallel()
+	_, unconverted := convertBlocks(`<!-- wp:cover {"url":"/a.mp4","backgroundType":"video"} --><div class="wp-block-cover"></div><!-- /wp:cover -->`, Options{})
+	require.Equal(t, []string{"cover"}, unconverted)
+}
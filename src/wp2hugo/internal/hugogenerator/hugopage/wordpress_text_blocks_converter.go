@@ -0,0 +1,98 @@
+package hugopage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Example:
+// <!-- wp:pullquote -->
+// <figure class="wp-block-pullquote"><blockquote><p>Quote</p><cite>Author</cite></blockquote></figure>
+// <!-- /wp:pullquote -->
+//
+// Since WordPress 6.0, the paragraphs of quotes are inner blocks:
+// <!-- wp:quote -->
+// <blockquote class="wp-block-quote"><!-- wp:paragraph --><p>Quote</p><!-- /wp:paragraph --><cite>Author</cite></blockquote>
+// <!-- /wp:quote -->
+func convertQuoteBlock(r *blockRenderer, block *Block) (string, bool) {
+	blockquote := findElement(parseBlockHTML(block), "blockquote")
+	if blockquote == nil {
+		return "", false
+	}
+	cite := findElement(blockquote, "cite")
+	if cite != nil {
+		cite.Parent.RemoveChild(cite)
+	}
+
+	content := r.renderInnerBlocks(block)
+	if len(block.InnerBlocks) == 0 {
+		content = renderChildren(blockquote)
+	}
+	if cite != nil && strings.TrimSpace(getText(cite)) != "" {
+		content += "<p>— " + renderChildren(cite) + "</p>"
+	}
+	return "<blockquote>" + content + "</blockquote>", true
+}
+
+// Converted to the details shortcode of Hugo
+// Example:
+// <!-- wp:details {"showContent":true} -->
+// <details class="wp-block-details" open><summary>Question</summary><!-- wp:paragraph --><p>Answer</p><!-- /wp:paragraph --></details>
+// <!-- /wp:details -->
+func convertDetailsBlock(r *blockRenderer, block *Block) (string, bool) {
+	summary := getText(findElement(parseBlockHTML(block), "summary"))
+	params := fmt.Sprintf(` summary="%s"`, sanitizeQuotes(strings.TrimSpace(summary)))
+	if block.AttrString("showContent") == "true" {
+		params += " open=true"
+	}
+	return printEnclosingShortCode("details", params, r.renderInnerBlocks(block)), true
+}
+
+// Example:
+// <!-- wp:file {"id":12,"href":"https://example.com/wp-content/uploads/2024/01/report.pdf"} -->
+// <div class="wp-block-file"><a id="wp-block-file--media-1" href="https://example.com/wp-content/uploads/2024/01/report.pdf">Report</a><a href="https://example.com/wp-content/uploads/2024/01/report.pdf" class="wp-block-file__button wp-element-button" download aria-describedby="wp-block-file--media-1">Download</a></div>
+// <!-- /wp:file -->
+func convertFileBlock(_ *blockRenderer, block *Block) (string, bool) {
+	src := block.AttrString("href")
+	name := ""
+	for _, link := range getChildElements(findElementWithClass(parseBlockHTML(block), "wp-block-file")) {
+		if link.Data != "a" || hasClass(link, "wp-block-file__button") {
+			continue
+		}
+		name = getText(link)
+		if src == "" {
+			src = getAttr(link, "href")
+		}
+		break
+	}
+	if src == "" {
+		return "", false
+	}
+	return printFileShortCode(src, name), true
+}
+
+func printFileShortCode(src string, name string) string {
+	return fmt.Sprintf(`<p>{{< file src="%s" name="%s" >}}</p>`, sanitizeLinks(src), sanitizeQuotes(strings.TrimSpace(name)))
+}
+
+// Converted to a Markdown table, the caption follows the table
+// Example:
+// <!-- wp:table -->
+// <figure class="wp-block-table"><table><thead><tr><th>Name</th></tr></thead><tbody><tr><td>Value</td></tr></tbody></table><figcaption class="wp-element-caption">Caption</figcaption></figure>
+// <!-- /wp:table -->
+func convertTableBlock(_ *blockRenderer, block *Block) (string, bool) {
+	doc := parseBlockHTML(block)
+	table := findElement(doc, "table")
+	if table == nil {
+		return "", false
+	}
+	output := renderNode(table)
+	if figcaption := findElement(doc, "figcaption"); figcaption != nil {
+		output += "<p>" + renderChildren(figcaption) + "</p>"
+	}
+	return output, true
+}
+
+func convertSeparatorBlock(_ *blockRenderer, _ *Block) (string, bool) {
+	return "<hr>", true
+}
@@ -0,0 +1,67 @@
+package hugopage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteBlocks(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:quote -->
+<blockquote class="wp-block-quote"><!-- wp:paragraph --><p>Quote</p><!-- /wp:paragraph --><cite>Author, <em>Book</em></cite></blockquote>
+<!-- /wp:quote -->
+
+<!-- wp:pullquote -->
+<figure class="wp-block-pullquote"><blockquote><p>Pull</p><cite>Someone</cite></blockquote></figure>
+<!-- /wp:pullquote -->`
+	testMarkdownExtractor(t, htmlInput, `> Quote
+>
+> — Author, _Book_
+
+> Pull
+>
+> — Someone`)
+}
+
+func TestDetailsBlock(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:details {"showContent":true} -->
+<details class="wp-block-details" open><summary>Why "this"?</summary><!-- wp:paragraph --><p>Answer</p><!-- /wp:paragraph --></details>
+<!-- /wp:details -->`
+	testMarkdownExtractor(t, htmlInput, `{{< details summary="Why 'this'?" open=true >}}
+
+Answer
+
+{{< /details >}}`)
+}
+
+func TestFileBlock(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:file {"id":12,"href":"https://example.com/wp-content/uploads/2024/01/my_report.zip"} -->
+<div class="wp-block-file"><a id="wp-block-file--media-1" href="https://example.com/wp-content/uploads/2024/01/my_report.zip">My report</a><a href="https://example.com/wp-content/uploads/2024/01/my_report.zip" class="wp-block-file__button wp-element-button" download aria-describedby="wp-block-file--media-1">Download</a></div>
+<!-- /wp:file -->`
+	const markdownOutput = `{{< file src="/wp-content/uploads/2024/01/my%5Freport.zip" name="My report" >}}`
+	testMarkdownExtractor(t, htmlInput, markdownOutput)
+
+	page := Page{markdown: markdownOutput}
+	require.Equal(t, []string{"/wp-content/uploads/2024/01/my%5Freport.zip"}, page.WPMediaLinks())
+}
+
+func TestTableAndSeparatorBlocks(t *testing.T) {
+	t.Parallel()
+	const htmlInput = `<!-- wp:table -->
+<figure class="wp-block-table"><table><thead><tr><th>Name</th><th>Value</th></tr></thead><tbody><tr><td>a|b</td><td><strong>1</strong></td></tr></tbody></table><figcaption class="wp-element-caption">Caption</figcaption></figure>
+<!-- /wp:table -->
+
+<!-- wp:separator -->
+<hr class="wp-block-separator has-alpha-channel-opacity"/>
+<!-- /wp:separator -->`
+	testMarkdownExtractor(t, htmlInput, `| Name | Value |
+| --- | --- |
+| a\|b | **1** |
+
+Caption
+
+* * *`)
+}
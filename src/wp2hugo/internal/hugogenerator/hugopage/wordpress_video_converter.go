@@ -37,6 +37,18 @@ func replaceVideoShortCode(htmlData string) string {
 	return htmlData
 }
 
+// Example:
+// <!-- wp:video {"id":1338} -->
+// <figure class="wp-block-video"><video controls src="/wp-content/uploads/2026/05/video.mp4"></video></figure>
+// <!-- /wp:video -->
+func convertVideoBlock(_ *blockRenderer, block *Block) (string, bool) {
+	video := findElement(parseBlockHTML(block), "video")
+	if video == nil || getAttr(video, "src") == "" {
+		return "", false
+	}
+	return printVideoShortCode(getAttr(video, "src")), true
+}
+
 func printVideoShortCode(src string) string {
 	// These characters create problems in Hugo's markdown
 	src = strings.ReplaceAll(src, " ", "%20")
@@ -12,34 +12,52 @@ import (
 // https://wordpress.com/plugins/wp-youtube-lyte
 var _YoutubeRegEx = regexp.MustCompile(`(?m)(^|\s)http[sav]?://(?:m\.|www\.)?(?:youtu\.be|youtube\.com)/(?:watch|w)\?v=([^&\s]+)`)
 
-// Gutenberg Youtube embed into figure:
-// <!-- wp:embed {"url":"https://www.youtube.com/watch?v=7l6FjphZXsk","type":"video","providerNameSlug":"youtube","responsive":true,"align":"full","className":"wp-embed-aspect-16-9 wp-has-aspect-ratio"} -->
-// <figure class="wp-block-embed alignfull is-type-video is-provider-youtube wp-block-embed-youtube wp-embed-aspect-16-9 wp-has-aspect-ratio"><div class="wp-block-embed__wrapper">
-// https://www.youtube.com/watch?v=7l6FjphZXsk
-// </div></figure>
-// <!-- /wp:embed -->
+var _YoutubeEmbedRegEx = regexp.MustCompile(`(?m)(^|\s)(?:[embed])http[sav]?://(?:m\.|www\.)?(?:youtu\.be|youtube\.com)/(?:watch|w)\?v=([^&\s]+)(?:[/embed])`)
+
 var (
-	_YoutubeGutenbergRegEx = regexp.MustCompile(`(?ms)(^|\s)<!-- wp:embed {"url":"[^"]+v=([^"]+)".*?<!-- /wp:embed -->`)
-	_YoutubeEmbedRegEx     = regexp.MustCompile(`(?m)(^|\s)(?:[embed])http[sav]?://(?:m\.|www\.)?(?:youtu\.be|youtube\.com)/(?:watch|w)\?v=([^&\s]+)(?:[/embed])`)
+	// [youtube http://www.youtube.com/watch?v=1cNDSPutas8], with the brackets escaped in Markdown
+	// Ref: https://github.com/ashishb/wp2hugo/issues/268
+	_youtubeTagRegEx = regexp.MustCompile(`(?m)\\?\[youtube\s+(.*?)\\?]`)
+	// YouTube Lyte links, e.g. httpv://www.youtube.com/watch?v=1cNDSPutas8
+	_youtubeLyteRegEx = regexp.MustCompile(`(?m)(^|\s)http[av]://((?:m\.|www\.)?(?:youtu\.be|youtube\.com)/)`)
 )
 
-func replacePlaintextYoutubeURL(htmlData string) string {
+// normalizeYoutubeURLs unwraps the [youtube] tags and replaces the scheme of the YouTube Lyte links with https,
+// so that they are embedded like plain URLs
+func normalizeYoutubeURLs(markdown string) string {
+	markdown = _youtubeTagRegEx.ReplaceAllString(markdown, "$1")
+	return _youtubeLyteRegEx.ReplaceAllString(markdown, "${1}https://$2")
+}
+
+// replacePlaintextYoutubeURL replaces the YouTube URLs, in privacy mode with a placeholder loading the video on click
+func replacePlaintextYoutubeURL(htmlData string, privacy bool) string {
 	log.Debug().
 		Msg("Replacing Youtube URLs with embeds")
 
 	// Replace "[embed](.*)[/embed]" with $1, to remove the embed tags and leave the URL for the next regex to process
 	htmlData = regexp.MustCompile(`(?m)\[embed](.*?)\[/embed]`).ReplaceAllString(htmlData, "$1")
 
-	// Replace [youtube http://www.youtube.com/watch?v=1cNDSPutas8] with just the URL for the next regex to process
-	// Ref: https://github.com/ashishb/wp2hugo/issues/268
-	htmlData = regexp.MustCompile(`(?m)\[youtube (.*?)]`).ReplaceAllString(htmlData, "$1")
+	htmlData = normalizeYoutubeURLs(htmlData)
 
-	htmlData = replaceAllStringSubmatchFunc(_YoutubeGutenbergRegEx, htmlData, YoutubeReplacementFunction)
-	htmlData = replaceAllStringSubmatchFunc(_YoutubeRegEx, htmlData, YoutubeReplacementFunction)
-	htmlData = replaceAllStringSubmatchFunc(_YoutubeEmbedRegEx, htmlData, YoutubeReplacementFunction)
+	replacementFunction := YoutubeReplacementFunction
+	if privacy {
+		replacementFunction = youtubePlaceholderFunction
+	}
+	htmlData = replaceAllStringSubmatchFunc(_YoutubeRegEx, htmlData, replacementFunction)
+	htmlData = replaceAllStringSubmatchFunc(_YoutubeEmbedRegEx, htmlData, replacementFunction)
 	return htmlData
 }
 
 func YoutubeReplacementFunction(groups []string) string {
 	return fmt.Sprintf(`%s{{< youtube %s >}}`, groups[1], groups[2])
 }
+
+func youtubePlaceholderFunction(groups []string) string {
+	// Undo the Markdown escapes, e.g. 8K7PdBH3W\_I
+	videoURL := "https://www.youtube.com/watch?v=" + _hugoShortcodeEscapes.ReplaceAllString(groups[2], "$1")
+	placeholder, ok := getEmbedShortCode("youtube", videoURL, true)
+	if !ok {
+		return groups[0]
+	}
+	return groups[1] + placeholder
+}
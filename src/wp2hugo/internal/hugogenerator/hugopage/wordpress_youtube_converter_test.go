@@ -10,28 +10,28 @@ func TestReplaceYoutubeURL1(t *testing.T) {
 	t.Parallel()
 	const htmlData = "This is a test with a youtube link:\nhttps://www.youtube.com/watch?v=gL0-m1Qlohg"
 	const expected = "This is a test with a youtube link:\n{{< youtube gL0-m1Qlohg >}}"
-	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData))
+	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData, false))
 }
 
 func TestReplaceYoutubeURL2(t *testing.T) {
 	t.Parallel()
 	const htmlData = "This is a test with a youtube link: https://www.youtube.com/watch?v=8K7PdBH3W_I"
 	const expected = "This is a test with a youtube link: {{< youtube 8K7PdBH3W_I >}}"
-	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData))
+	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData, false))
 }
 
 func TestReplaceYoutubeURL3(t *testing.T) {
 	t.Parallel()
 	const htmlData = "This is a test with a youtube link:\thttps://www.youtube.com/watch?v=gJ7AAJXHeeg whatever"
 	const expected = "This is a test with a youtube link:\t{{< youtube gJ7AAJXHeeg >}} whatever"
-	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData))
+	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData, false))
 }
 
 func TestReplaceYoutubeURL4(t *testing.T) {
 	t.Parallel()
 	const htmlData = "[embed]https://www.youtube.com/watch?v=gJ7AAJXHeeg[/embed]"
 	const expected = "{{< youtube gJ7AAJXHeeg >}}"
-	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData))
+	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData, false))
 }
 
 func TestReplaceNonPlaintextYouTubeURL(t *testing.T) {
@@ -40,7 +40,7 @@ func TestReplaceNonPlaintextYouTubeURL(t *testing.T) {
 		embed <iframe width="560" height="315" src="https://www.youtube.com/embed/Wz6ml5SpkKM?si=rrx_5_80TE3Mz7Co"
 		title="YouTube video player" frameborder="0" allowfullscreen></iframe>`
 	// Assert that the function does not replace the youtube URL in the iframe or the link
-	require.Equal(t, htmlData, replacePlaintextYoutubeURL(htmlData))
+	require.Equal(t, htmlData, replacePlaintextYoutubeURL(htmlData, false))
 }
 
 func TestReplaceYoutubeURL5(t *testing.T) {
@@ -51,7 +51,8 @@ func TestReplaceYoutubeURL5(t *testing.T) {
 	</div></figure>
 	<!-- /wp:embed -->`
 	const expected = "{{< youtube 7l6FjphZXsk >}}"
-	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData))
+	converted, _ := convertBlocks(htmlData, Options{})
+	require.Equal(t, expected, converted)
 }
 
 func TestReplaceYoutubeURL6(t *testing.T) {
@@ -59,5 +60,5 @@ func TestReplaceYoutubeURL6(t *testing.T) {
 	// Ref: https://github.com/ashishb/wp2hugo/issues/268
 	const htmlData = "[youtube http://www.youtube.com/watch?v=1cNDSPutas8]"
 	const expected = "{{< youtube 1cNDSPutas8 >}}"
-	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData))
+	require.Equal(t, expected, replacePlaintextYoutubeURL(htmlData, false))
 }
@@ -0,0 +1,204 @@
+package hugogenerator
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// The manifest lives in the data directory so that it is kept along with the site
+const _importManifestPath = "data/wp2hugo-manifest.json"
+
+// importManifest records which file has been generated from which WordPress item,
+// including the collision suffix getFilePath may have added, and which media have been downloaded for it.
+// It is meant for auditing a migration and for tooling, it is also what allows re-importing
+// into an existing site, see siteUpdater.
+// Pages are written while the next ones are submitted, so the items are guarded by mutex
+type importManifest struct {
+	siteDir string
+
+	mutex sync.Mutex
+	items map[string]importManifestItem // Keyed by post ID
+}
+
+type importManifestItem struct {
+	PostID   string `json:"post_id"`
+	PostType string `json:"post_type"`
+	Link     string `json:"link"`
+	GUID     string `json:"guid,omitempty"`
+	// Relative to the site directory, empty for attachments
+	Path         string     `json:"path,omitempty"`
+	LastModified *time.Time `json:"last_modified,omitempty"`
+	// SHA-256 of the file as it was written
+	ContentHash string `json:"content_hash,omitempty"`
+	// Downloaded media files, relative to the site directory
+	Media []string `json:"media,omitempty"`
+}
+
+type _ImportManifestFile struct {
+	Items []importManifestItem `json:"items"`
+}
+
+// readImportManifest reads the manifest of siteDir, a site without manifest gets an empty one
+func readImportManifest(siteDir string) (*importManifest, error) {
+	manifest := &importManifest{
+		siteDir: siteDir,
+		items:   make(map[string]importManifestItem),
+	}
+
+	data, err := os.ReadFile(path.Join(siteDir, _importManifestPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading import manifest: %w", err)
+	}
+
+	var file _ImportManifestFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing import manifest: %w", err)
+	}
+	for _, item := range file.Items {
+		manifest.items[item.PostID] = item
+	}
+	return manifest, nil
+}
+
+func (m *importManifest) get(postID string) (importManifestItem, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, ok := m.items[postID]
+	return item, ok
+}
+
+// add records that filePath, along with mediaPaths, has been generated from page
+func (m *importManifest) add(page wpparser.CommonFields, filePath string, content []byte, mediaPaths []string) error {
+	item := newImportManifestItem(page)
+	relativePath, err := m.relativePath(filePath)
+	if err != nil {
+		return err
+	}
+	item.Path = relativePath
+	item.LastModified = page.LastModifiedDate
+	item.ContentHash = getContentHash(content)
+	for _, mediaPath := range mediaPaths {
+		if item.Media, err = m.appendMedia(item.Media, mediaPath); err != nil {
+			return err
+		}
+	}
+	m.mutex.Lock()
+	m.items[page.PostID] = item
+	m.mutex.Unlock()
+	return nil
+}
+
+// addMedia records that mediaPath has been downloaded for the attachment
+func (m *importManifest) addMedia(attachment wpparser.CommonFields, mediaPath string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, ok := m.items[attachment.PostID]
+	if !ok {
+		item = newImportManifestItem(attachment)
+	}
+	var err error
+	if item.Media, err = m.appendMedia(item.Media, mediaPath); err != nil {
+		return err
+	}
+	m.items[attachment.PostID] = item
+	return nil
+}
+
+func (m *importManifest) appendMedia(media []string, mediaPath string) ([]string, error) {
+	relativePath, err := m.relativePath(mediaPath)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(media, relativePath) {
+		return media, nil
+	}
+	return append(media, relativePath), nil
+}
+
+func newImportManifestItem(page wpparser.CommonFields) importManifestItem {
+	item := importManifestItem{
+		PostID: page.PostID,
+		Link:   page.Link,
+	}
+	if page.PostType != nil {
+		item.PostType = *page.PostType
+	}
+	if page.GUID != nil {
+		item.GUID = page.GUID.Value
+	}
+	return item
+}
+
+// rename follows a generated file being moved, see sanitizePageBundles
+func (m *importManifest) rename(oldPath string, newPath string) error {
+	oldRelativePath, err := m.relativePath(oldPath)
+	if err != nil {
+		return err
+	}
+	newRelativePath, err := m.relativePath(newPath)
+	if err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for postID, item := range m.items {
+		if item.Path == oldRelativePath {
+			item.Path = newRelativePath
+			m.items[postID] = item
+		}
+	}
+	return nil
+}
+
+func (m *importManifest) write() error {
+	m.mutex.Lock()
+	file := _ImportManifestFile{
+		Items: slices.Collect(maps.Values(m.items)),
+	}
+	m.mutex.Unlock()
+	// Post IDs are numeric, shorter ones first keeps them in numeric order
+	slices.SortFunc(file.Items, func(a, b importManifestItem) int {
+		return cmp.Or(cmp.Compare(len(a.PostID), len(b.PostID)), cmp.Compare(a.PostID, b.PostID))
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling import manifest: %w", err)
+	}
+	manifestPath := path.Join(m.siteDir, _importManifestPath)
+	if err := utils.CreateDirIfNotExist(path.Dir(manifestPath)); err != nil {
+		return err
+	}
+	return writeFile(manifestPath, append(data, '\n'))
+}
+
+func (m *importManifest) relativePath(filePath string) (string, error) {
+	relativePath, err := filepath.Rel(m.siteDir, filePath)
+	if err != nil {
+		return "", fmt.Errorf("error getting path of %s relative to %s: %w", filePath, m.siteDir, err)
+	}
+	return filepath.ToSlash(relativePath), nil
+}
+
+func getContentHash(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,59 @@
+package hugogenerator
+
+import (
+	"path"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportManifest(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	manifest, err := readImportManifest(siteDir)
+	require.NoError(t, err)
+
+	pageType := "page"
+	attachmentType := "attachment"
+	page := wpparser.CommonFields{
+		PostID:   "12",
+		PostType: &pageType,
+		Link:     "https://example.com/about/",
+		GUID:     &rss.GUID{Value: "https://example.com/?page_id=12"},
+	}
+	attachment := wpparser.CommonFields{
+		PostID:   "13",
+		PostType: &attachmentType,
+		Link:     "https://example.com/about/photo/",
+	}
+
+	oldPath := path.Join(siteDir, "content", "pages", "about", "_index.md")
+	newPath := path.Join(siteDir, "content", "pages", "about", "index.md")
+	mediaPath := siteDir + "/static//wp-content/uploads/photo.jpg"
+	require.NoError(t, manifest.add(page, oldPath, []byte("content"), []string{mediaPath, mediaPath}))
+	require.NoError(t, manifest.addMedia(attachment, mediaPath))
+	require.NoError(t, manifest.rename(oldPath, newPath))
+	require.NoError(t, manifest.write())
+
+	manifest, err = readImportManifest(siteDir)
+	require.NoError(t, err)
+	item, ok := manifest.get("12")
+	require.True(t, ok)
+	require.Equal(t, importManifestItem{
+		PostID:      "12",
+		PostType:    "page",
+		Link:        "https://example.com/about/",
+		GUID:        "https://example.com/?page_id=12",
+		Path:        "content/pages/about/index.md",
+		ContentHash: getContentHash([]byte("content")),
+		Media:       []string{"static/wp-content/uploads/photo.jpg"},
+	}, item)
+
+	item, ok = manifest.get("13")
+	require.True(t, ok)
+	require.Empty(t, item.Path)
+	require.Equal(t, "attachment", item.PostType)
+	require.Equal(t, []string{"static/wp-content/uploads/photo.jpg"}, item.Media)
+}
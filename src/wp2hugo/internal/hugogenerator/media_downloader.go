@@ -1,17 +1,82 @@
 package hugogenerator
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path"
 	"regexp"
+	"sync"
 
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
 	"github.com/rs/zerolog/log"
 )
 
+// mediaDownloader bounds the number of media downloaded concurrently, across all the pages
+// being converted, and downloads a link requested by several pages at the same time only once
+type mediaDownloader struct {
+	slots chan struct{}
+
+	mutex    sync.Mutex
+	inFlight map[string]*mediaDownload
+}
+
+type mediaDownload struct {
+	done           chan struct{}
+	mediaPath      string
+	urlReplacement map[string]string
+	err            error
+}
+
+func newMediaDownloader(workers int) *mediaDownloader {
+	return &mediaDownloader{
+		slots:    make(chan struct{}, max(workers, 1)),
+		inFlight: make(map[string]*mediaDownload),
+	}
+}
+
+// download downloads link with downloadLink, or waits for the download of link already in progress
+func (d *mediaDownloader) download(ctx context.Context, link string,
+	downloadLink func() (string, map[string]string, error),
+) (string, map[string]string, error) {
+	d.mutex.Lock()
+	current, ok := d.inFlight[link]
+	if !ok {
+		current = &mediaDownload{done: make(chan struct{})}
+		d.inFlight[link] = current
+	}
+	d.mutex.Unlock()
+
+	if ok {
+		select {
+		case <-current.done:
+			return current.mediaPath, current.urlReplacement, current.err
+		case <-ctx.Done():
+			return "", nil, ctx.Err()
+		}
+	}
+
+	defer func() {
+		d.mutex.Lock()
+		delete(d.inFlight, link)
+		d.mutex.Unlock()
+		close(current.done)
+	}()
+	select {
+	case d.slots <- struct{}{}:
+	case <-ctx.Done():
+		current.err = ctx.Err()
+		return "", nil, current.err
+	}
+	defer func() {
+		<-d.slots
+	}()
+	current.mediaPath, current.urlReplacement, current.err = downloadLink()
+	return current.mediaPath, current.urlReplacement, current.err
+}
+
 func writeFavicon(outputDirPath string, faviconData io.Reader) error {
 	log.Debug().Msg("Writing favicon")
 	return download(path.Join(outputDirPath, "favicon.ico"), faviconData)
@@ -41,19 +106,35 @@ func download(destFilePath string, reader io.Reader) error {
 		destFilePath = path.Join(path.Dir(destFilePath), fileName)
 	}
 
-	file, err := os.OpenFile(destFilePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	// Written to a temporary file first, different links may be downloaded to the same file concurrently,
+	// e.g. a thumbnail replaced by the full-resolution image
+	file, err := os.CreateTemp(path.Dir(destFilePath), "."+fileName+".*.tmp")
 	if err != nil {
 		return fmt.Errorf("error opening file %s: %w", destFilePath, err)
 	}
 
 	_, err = io.Copy(file, reader)
 	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
 		return fmt.Errorf("error writing to file %s: %w", destFilePath, err)
 	}
 
 	if err := file.Close(); err != nil {
 		return fmt.Errorf("error closing file %s: %w", destFilePath, err)
 	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return fmt.Errorf("error setting permissions of file %s: %w", destFilePath, err)
+	}
+	if err := os.Rename(file.Name(), destFilePath); err != nil {
+		return fmt.Errorf("error moving file %s: %w", destFilePath, err)
+	}
+	return nil
+}
 
-	return err
+// closeMedia closes media if it has to be, e.g. a file of the media cache
+func closeMedia(media io.Reader) {
+	if closer, ok := media.(io.Closer); ok {
+		_ = closer.Close()
+	}
 }
@@ -0,0 +1,181 @@
+package hugogenerator
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Name of the menu of the Hugo themes
+const _mainMenuName = "main"
+
+// getNavMenus returns the classic menus of info by Hugo menu name, with their links resolved to the URLs
+// of the generated site. Unless the site has a block theme navigation, the menu whose slug is mainMenu,
+// or the first one looking like the main one, becomes the main menu.
+func getNavMenus(info wpparser.WebsiteInfo, taxonomyNames map[string]TaxonomyName, mainMenu string) map[string][]_HugoNavMenu {
+	items := slices.Clone(info.NavMenuItems())
+	slices.SortStableFunc(items, func(a, b wpparser.NavMenuItem) int {
+		return cmp.Compare(a.Order, b.Order)
+	})
+
+	var menuSlugs []string
+	itemMenus := make(map[string]string, len(items))
+	for _, item := range items {
+		if !slices.Contains(menuSlugs, item.MenuSlug) {
+			menuSlugs = append(menuSlugs, item.MenuSlug)
+		}
+		itemMenus[item.ID] = item.MenuSlug
+	}
+	if len(info.NavigationLinks()) == 0 {
+		if mainMenu == "" {
+			mainMenu = guessMainMenu(menuSlugs)
+		}
+		if !slices.Contains(menuSlugs, mainMenu) {
+			log.Warn().
+				Str("mainMenu", mainMenu).
+				Strs("menus", menuSlugs).
+				Msg("Main menu not found")
+		}
+	}
+
+	menus := make(map[string][]_HugoNavMenu, len(menuSlugs))
+	for _, item := range items {
+		name, link, err := resolveNavMenuItem(info, taxonomyNames, item)
+		if err != nil {
+			log.Warn().
+				Str("menu", item.MenuSlug).
+				Str("id", item.ID).
+				Err(err).
+				Msg("Ignoring menu item")
+			continue
+		}
+		menuName := item.MenuSlug
+		if menuName == mainMenu && len(info.NavigationLinks()) == 0 {
+			menuName = _mainMenuName
+		}
+		entry := _HugoNavMenu{
+			Name:       name,
+			URL:        link,
+			Weight:     item.Order,
+			Identifier: item.ID,
+		}
+		// Hugo fails on parents of other menus
+		if itemMenus[item.ParentID] == item.MenuSlug {
+			entry.Parent = item.ParentID
+		}
+		menus[menuName] = append(menus[menuName], entry)
+	}
+	return menus
+}
+
+// guessMainMenu returns the first menu whose slug looks like the one of a main menu, or else the first menu
+func guessMainMenu(menuSlugs []string) string {
+	for _, slug := range menuSlugs {
+		for _, hint := range []string{"main", "primary", "header", "top"} {
+			if strings.Contains(slug, hint) {
+				return slug
+			}
+		}
+	}
+	if len(menuSlugs) > 0 {
+		return menuSlugs[0]
+	}
+	return ""
+}
+
+// resolveNavMenuItem returns the name and the URL in the generated site of the menu item
+func resolveNavMenuItem(info wpparser.WebsiteInfo, taxonomyNames map[string]TaxonomyName,
+	item wpparser.NavMenuItem,
+) (string, string, error) {
+	name, link := item.Title, ""
+	switch item.Type {
+	case wpparser.NavMenuItemTypeCustom:
+		link = hugopage.ReplaceAbsoluteLinksWithRelative(info.Link().Host, item.URL)
+	case wpparser.NavMenuItemTypePost:
+		page, ok := findPage(info, item.ObjectID)
+		if !ok {
+			return "", "", fmt.Errorf("%s %s not found", item.Object, item.ObjectID)
+		}
+		pageURL, err := url.Parse(page.Link)
+		if err != nil {
+			return "", "", fmt.Errorf("error parsing link of %s %s: %w", item.Object, item.ObjectID, err)
+		}
+		name = cmp.Or(name, page.Title)
+		link = pageURL.Path
+	case wpparser.NavMenuItemTypeTaxonomy:
+		termName, termURL, ok := findTerm(info, taxonomyNames, item.Object, item.ObjectID)
+		if !ok {
+			return "", "", fmt.Errorf("%s %s not found", item.Object, item.ObjectID)
+		}
+		name = cmp.Or(name, termName)
+		link = termURL
+	default:
+		return "", "", fmt.Errorf("unsupported menu item type %q", item.Type)
+	}
+	if name == "" || link == "" {
+		return "", "", fmt.Errorf("menu item without title or URL")
+	}
+	return name, link, nil
+}
+
+// findPage returns the page, post or custom post postID, the streamed posts included
+func findPage(info wpparser.WebsiteInfo, postID string) (wpparser.PostLink, bool) {
+	for _, page := range info.Pages() {
+		if page.PostID == postID {
+			return wpparser.PostLink{PostID: postID, Title: page.Title, Link: page.Link}, true
+		}
+	}
+	for _, post := range info.Posts() {
+		if post.PostID == postID {
+			return wpparser.PostLink{PostID: postID, Title: post.Title, Link: post.Link}, true
+		}
+	}
+	for _, customPost := range info.CustomPosts() {
+		if customPost.PostID == postID {
+			return wpparser.PostLink{PostID: postID, Title: customPost.Title, Link: customPost.Link}, true
+		}
+	}
+	for _, post := range info.StreamedPosts() {
+		if post.PostID == postID {
+			return post, true
+		}
+	}
+	return wpparser.PostLink{}, false
+}
+
+// findTerm returns the title and the URL of the page of a term, see writeTermPages
+func findTerm(info wpparser.WebsiteInfo, taxonomyNames map[string]TaxonomyName, taxonomy string,
+	termID string,
+) (string, string, bool) {
+	switch taxonomy {
+	case "category":
+		for _, category := range info.Categories() {
+			if category.ID == termID {
+				return category.Title, fmt.Sprintf("/%s/%s/", hugopage.CategoryName, category.Name), true
+			}
+		}
+	case "post_tag":
+		for _, tag := range info.Tags() {
+			if tag.ID == termID {
+				return tag.Title, fmt.Sprintf("/%s/%s/", hugopage.TagName, tag.Name), true
+			}
+		}
+	default:
+		name, ok := getCustomTaxonomyName(taxonomyNames, taxonomy)
+		if !ok {
+			return "", "", false
+		}
+		for _, term := range info.Taxonomies() {
+			if term.Taxonomy == taxonomy && fmt.Sprint(term.ID) == termID {
+				return term.Name, fmt.Sprintf("/%s/%s/", name.Plural, term.Slug), true
+			}
+		}
+	}
+	return "", "", false
+}
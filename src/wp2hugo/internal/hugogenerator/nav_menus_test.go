@@ -0,0 +1,49 @@
+package hugogenerator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNavMenus(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/nav_menus.WordPress.xml")
+	require.NoError(t, err)
+	info, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, info.NavMenuItems(), 6)
+
+	mainMenu := []_HugoNavMenu{
+		{Name: "Travel", URL: "/categories/travel/", Weight: 1, Identifier: "101"},
+		{Name: "About me", URL: "/about/", Weight: 2, Identifier: "100", Parent: "101"},
+		{Name: "Home", URL: "/", Weight: 3, Identifier: "102"},
+		{Name: "Hello", URL: "/2024/01/hello/", Weight: 5, Identifier: "104"},
+	}
+	socialMenu := []_HugoNavMenu{
+		{Name: "Mastodon", URL: "https://mastodon.social/@example", Weight: 1, Identifier: "105"},
+	}
+	require.Equal(t, map[string][]_HugoNavMenu{"main": mainMenu, "social": socialMenu}, getNavMenus(*info, nil, ""))
+	require.Equal(t, map[string][]_HugoNavMenu{"primary": mainMenu, "main": socialMenu}, getNavMenus(*info, nil, "social"))
+
+	var config _HugoConfig
+	addNavigationLinks(*info, &config, "/search/", getNavMenus(*info, nil, ""))
+	require.Equal(t, _HugoNavMenu{Name: "🔍", URL: "/search/", Weight: 6}, config.Menu["main"][4])
+	require.Len(t, config.Menu["social"], 1)
+}
+
+func TestGetNavMenus_StreamedPosts(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/nav_menus.WordPress.xml")
+	require.NoError(t, err)
+	// First pass of --stream-posts, the posts are written during the second one
+	info, err := wpparser.NewParser().ParseStream(file, nil, nil, wpparser.StreamHandler{
+		OnPost: func(wpparser.PostInfo) error { return nil },
+	})
+	require.NoError(t, err)
+	require.Empty(t, info.Posts())
+	require.Contains(t, getNavMenus(*info, nil, "")["main"],
+		_HugoNavMenu{Name: "Hello", URL: "/2024/01/hello/", Weight: 5, Identifier: "104"})
+}
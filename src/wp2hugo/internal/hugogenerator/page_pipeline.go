@@ -0,0 +1,96 @@
+package hugogenerator
+
+import (
+	"context"
+)
+
+// pagePipeline converts up to `workers` pages concurrently, and writes them one at a time in the
+// order they were submitted. Paths are thus picked, collision suffixes included, and the comments
+// data file is updated exactly as in a sequential run.
+// After an error, the pages not converted yet are skipped and nothing more is written.
+type pagePipeline struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	// Semaphore of the conversions in progress
+	slots chan struct{}
+	// Converted or being converted pages, in submission order
+	queue chan *pipelinePage
+	// Closed once all the pages have been written
+	done chan struct{}
+	err  error
+}
+
+type pipelinePage struct {
+	converted chan struct{}
+	write     func() error
+	err       error
+}
+
+// pageConverter converts a page, and returns the function writing it
+type pageConverter func(ctx context.Context) (func() error, error)
+
+func newPagePipeline(ctx context.Context, workers int) *pagePipeline {
+	workers = max(workers, 1)
+	ctx, cancel := context.WithCancelCause(ctx)
+	p := &pagePipeline{
+		ctx:    ctx,
+		cancel: cancel,
+		slots:  make(chan struct{}, workers),
+		// Bounds the number of converted pages held in memory while waiting for a slow one
+		queue: make(chan *pipelinePage, workers),
+		done:  make(chan struct{}),
+	}
+	go p.writeAll()
+	return p
+}
+
+// submit converts the page in the background, it blocks while all the workers are busy
+func (p *pagePipeline) submit(convert pageConverter) error {
+	select {
+	case p.slots <- struct{}{}:
+	case <-p.ctx.Done():
+		return context.Cause(p.ctx)
+	}
+
+	page := &pipelinePage{converted: make(chan struct{})}
+	go func() {
+		defer func() {
+			<-p.slots
+		}()
+		defer close(page.converted)
+		if err := p.ctx.Err(); err != nil {
+			page.err = err
+			return
+		}
+		page.write, page.err = convert(p.ctx)
+	}()
+	p.queue <- page
+	return nil
+}
+
+func (p *pagePipeline) writeAll() {
+	defer close(p.done)
+	for page := range p.queue {
+		<-page.converted
+		if p.err != nil {
+			continue
+		}
+		err := page.err
+		if err == nil {
+			err = page.write()
+		}
+		if err != nil {
+			p.err = err
+			p.cancel(err)
+		}
+	}
+}
+
+// wait waits for all the submitted pages to be written, and returns the first error, if any.
+// No page can be submitted afterward.
+func (p *pagePipeline) wait() error {
+	close(p.queue)
+	<-p.done
+	p.cancel(nil)
+	return p.err
+}
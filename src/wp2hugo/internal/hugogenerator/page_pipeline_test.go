@@ -0,0 +1,97 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagePipeline_WritesInSubmissionOrder(t *testing.T) {
+	t.Parallel()
+	pipeline := newPagePipeline(context.Background(), 4)
+	var written []int
+	for i := range 20 {
+		require.NoError(t, pipeline.submit(func(context.Context) (func() error, error) {
+			// The first pages are the slowest to convert
+			time.Sleep(time.Duration(20-i) * time.Millisecond)
+			return func() error {
+				written = append(written, i)
+				return nil
+			}, nil
+		}))
+	}
+	require.NoError(t, pipeline.wait())
+
+	expected := make([]int, 20)
+	for i := range expected {
+		expected[i] = i
+	}
+	require.Equal(t, expected, written)
+}
+
+func TestPagePipeline_StopsAfterError(t *testing.T) {
+	t.Parallel()
+	errConversion := errors.New("conversion failed")
+	pipeline := newPagePipeline(context.Background(), 2)
+	var written []int
+	var submitErr error
+	for i := range 100 {
+		submitErr = pipeline.submit(func(ctx context.Context) (func() error, error) {
+			if i == 3 {
+				return nil, errConversion
+			}
+			select {
+			case <-time.After(time.Millisecond):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return func() error {
+				written = append(written, i)
+				return nil
+			}, nil
+		})
+		if submitErr != nil {
+			break
+		}
+	}
+	require.ErrorIs(t, pipeline.wait(), errConversion)
+	require.ErrorIs(t, submitErr, errConversion)
+	require.Equal(t, []int{0, 1, 2}, written)
+}
+
+func TestMediaDownloader_DownloadsOnce(t *testing.T) {
+	t.Parallel()
+	downloader := newMediaDownloader(2)
+	var numDownloads atomic.Int32
+	release := make(chan struct{})
+	results := make(chan string, 3)
+	download := func() {
+		mediaPath, _, err := downloader.download(context.Background(), "/wp-content/uploads/a.jpg", func() (string, map[string]string, error) {
+			numDownloads.Add(1)
+			<-release
+			return "static/wp-content/uploads/a.jpg", nil, nil
+		})
+		if err != nil {
+			mediaPath = err.Error()
+		}
+		results <- mediaPath
+	}
+
+	go download()
+	require.Eventually(t, func() bool {
+		return numDownloads.Load() == 1
+	}, time.Second, time.Millisecond)
+	// Requested while the first download is in progress
+	go download()
+	go download()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	for range 3 {
+		require.Equal(t, "static/wp-content/uploads/a.jpg", <-results)
+	}
+	require.Equal(t, int32(1), numDownloads.Load())
+}
@@ -0,0 +1,121 @@
+package hugogenerator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// How the reusable blocks, aka synced patterns, are converted
+const (
+	// The content of the block is copied into every page using it
+	ReusableBlocksInline = "inline"
+	// The block is written once and pages include it with the reusable-block shortcode
+	ReusableBlocksShortcode = "shortcode"
+)
+
+// Pages of the reusable blocks, in shortcode mode
+const _reusableBlocksDir = "reusable-blocks"
+
+// E.g. <!-- wp:block {"ref":123} /-->
+var _reusableBlockRef = regexp.MustCompile(`<!-- wp:block (\{.*?\}) /-->`)
+
+func IsValidReusableBlocksMode(mode string) bool {
+	return mode == ReusableBlocksInline || mode == ReusableBlocksShortcode
+}
+
+// SetReusableBlocks sets how the reusable blocks are converted, one of ReusableBlocks*
+func (g *Generator) SetReusableBlocks(mode string) {
+	g.reusableBlocks = mode
+}
+
+// expandReusableBlocks replaces the references to reusable blocks in the HTML content of a page
+func (g Generator) expandReusableBlocks(htmlContent string) string {
+	return expandReusableBlocks(g.wpInfo, htmlContent, g.reusableBlocks, nil)
+}
+
+// expandReusableBlocks replaces the references to the blocks of info in htmlContent, with their content
+// or with a shortcode depending on mode. Blocks can be nested, parents are the blocks being expanded
+func expandReusableBlocks(info wpparser.WebsiteInfo, htmlContent string, mode string, parents []string) string {
+	if len(info.ReusableBlocks()) == 0 {
+		return htmlContent
+	}
+	return _reusableBlockRef.ReplaceAllStringFunc(htmlContent, func(match string) string {
+		var attrs struct {
+			Ref json.Number `json:"ref"`
+		}
+		if err := json.Unmarshal([]byte(_reusableBlockRef.FindStringSubmatch(match)[1]), &attrs); err != nil {
+			log.Warn().
+				Err(err).
+				Str("block", match).
+				Msg("Invalid reusable block reference")
+			return match
+		}
+		id := attrs.Ref.String()
+		block := info.GetReusableBlock(id)
+		if block == nil {
+			log.Warn().
+				Str("ref", id).
+				Msg("Reusable block not found")
+			return match
+		}
+		if slices.Contains(parents, id) {
+			log.Warn().
+				Str("ref", id).
+				Msg("Reusable block includes itself")
+			return ""
+		}
+		if mode == ReusableBlocksShortcode {
+			return fmt.Sprintf(`{{< reusable-block slug="%s" >}}`, block.Slug)
+		}
+		return expandReusableBlocks(info, block.Content, mode, append(parents, id))
+	})
+}
+
+// writeReusableBlocks writes the reusable blocks as pages included by the reusable-block shortcode
+func (g Generator) writeReusableBlocks(ctx context.Context, siteDir string, info wpparser.WebsiteInfo) error {
+	if g.reusableBlocks != ReusableBlocksShortcode {
+		return nil
+	}
+	siteURL := *info.Link()
+	for _, block := range info.ReusableBlocks() {
+		pagePath := path.Join(siteDir, "content", _reusableBlocksDir, block.Slug, "index.md")
+		if g.siteUpdater != nil && utils.FileExists(pagePath) {
+			log.Debug().
+				Str("path", pagePath).
+				Msg("Keeping existing reusable block")
+			continue
+		}
+		p, err := hugopage.NewReusableBlockPage(g.imageURLProvider, siteURL, block.Title,
+			g.expandReusableBlocks(block.Content), g.frontMatter(), g.pageOptions())
+		if err != nil {
+			return fmt.Errorf("error converting reusable block %s: %w", block.Slug, err)
+		}
+		if g.downloadMedia {
+			urlReplacements, _, err := g.downloadPageMedia(ctx, siteDir, p, &siteURL)
+			if err != nil {
+				return err
+			}
+			p.Replace(urlReplacements)
+		}
+		content, err := g.renderPage(p)
+		if err != nil {
+			return err
+		}
+		if err = utils.CreateDirIfNotExist(path.Dir(pagePath)); err != nil {
+			return err
+		}
+		if err = writeFile(pagePath, content); err != nil {
+			return fmt.Errorf("error writing reusable block %s: %w", block.Slug, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandReusableBlocks(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/reusable_blocks.WordPress.xml")
+	require.NoError(t, err)
+	info, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	content := `<p>Intro</p><!-- wp:block {"ref":200} /--><!-- wp:block {"ref":404} /-->`
+	require.Equal(t, `<p>Intro</p><p>Subscribe to <a href="https://example.net/newsletter/">the newsletter</a></p>
+<p><em>No spam</em></p><!-- wp:block {"ref":404} /-->`,
+		expandReusableBlocks(*info, content, ReusableBlocksInline, nil))
+	require.Equal(t, `<p>Intro</p>{{< reusable-block slug="newsletter" >}}<!-- wp:block {"ref":404} /-->`,
+		expandReusableBlocks(*info, content, ReusableBlocksShortcode, nil))
+}
+
+func TestWriteReusableBlocks(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/reusable_blocks.WordPress.xml")
+	require.NoError(t, err)
+	info, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	siteDir := t.TempDir()
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, ContentDateFolderStructureFlat, *info)
+	require.NoError(t, generator.writeReusableBlocks(context.Background(), siteDir, *info))
+	require.NoDirExists(t, path.Join(siteDir, "content", _reusableBlocksDir))
+
+	generator.SetReusableBlocks(ReusableBlocksShortcode)
+	require.NoError(t, generator.writeReusableBlocks(context.Background(), siteDir, *info))
+	content, err := os.ReadFile(path.Join(siteDir, "content", _reusableBlocksDir, "newsletter", "index.md"))
+	require.NoError(t, err)
+	require.Equal(t, `---
+build:
+  list: never
+  render: never
+title: Newsletter signup
+
+---
+Subscribe to [the newsletter](/newsletter/)
+
+{{< reusable-block slug="disclaimer" >}}
+`, string(content))
+	require.FileExists(t, path.Join(siteDir, "content", _reusableBlocksDir, "disclaimer", "index.md"))
+}
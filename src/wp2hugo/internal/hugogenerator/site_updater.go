@@ -0,0 +1,188 @@
+package hugogenerator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrg/frontmatter"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+var _hugoConfigFiles = []string{
+	"hugo.yaml", "hugo.yml", "hugo.toml", "hugo.json",
+	"config.yaml", "config.yml", "config.toml", "config.json",
+}
+
+// siteUpdater decides which pages of an existing Hugo site are re-generated on a re-import.
+// Existing files are matched to WordPress items with the `post_id` front matter key,
+// a file is only rewritten when the item was modified in WordPress since the last import
+// and the file was not edited by hand since then.
+type siteUpdater struct {
+	siteDir  string
+	manifest *importManifest
+	// Post ID -> Markdown file with that post_id in its front matter
+	existingFiles map[string]string
+
+	numUnchanged int
+	numUpdated   int
+	// Files that have not been overwritten as they were modified since the last import
+	handEditedFiles []string
+}
+
+func newSiteUpdater(siteDir string, manifest *importManifest) (*siteUpdater, error) {
+	if !isHugoSite(siteDir) {
+		return nil, fmt.Errorf("%s is not a Hugo site, none of %s found", siteDir, strings.Join(_hugoConfigFiles, ", "))
+	}
+	existingFiles, err := getFilesByPostID(path.Join(siteDir, "content"))
+	if err != nil {
+		return nil, err
+	}
+	log.Info().
+		Str("siteDir", siteDir).
+		Int("numExistingFiles", len(existingFiles)).
+		Msg("Updating existing Hugo site")
+	return &siteUpdater{
+		siteDir:       siteDir,
+		manifest:      manifest,
+		existingFiles: existingFiles,
+	}, nil
+}
+
+func isHugoSite(siteDir string) bool {
+	for _, configFile := range _hugoConfigFiles {
+		if stat, err := os.Stat(path.Join(siteDir, configFile)); err == nil && !stat.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// getFilesByPostID maps the `post_id` front matter key of the Markdown files in contentDir to their path
+func getFilesByPostID(contentDir string) (map[string]string, error) {
+	filesByPostID := make(map[string]string)
+	err := filepath.WalkDir(contentDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && filePath == contentDir {
+			return fs.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			return nil
+		}
+
+		postID, err := getFrontMatterPostID(filePath)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("path", filePath).
+				Msg("error reading front matter, file is ignored")
+			return nil
+		}
+		if postID == "" {
+			return nil
+		}
+		if existing, ok := filesByPostID[postID]; ok {
+			log.Warn().
+				Str("postID", postID).
+				Str("path", filePath).
+				Str("existingPath", existing).
+				Msg("Several files have the same post_id, only the first one is updated")
+			return nil
+		}
+		filesByPostID[postID] = filePath
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing files of %s: %w", contentDir, err)
+	}
+	return filesByPostID, nil
+}
+
+func getFrontMatterPostID(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var matter map[string]any
+	if _, err := frontmatter.Parse(file, &matter); err != nil {
+		return "", err
+	}
+	postID, ok := matter["post_id"]
+	if !ok || postID == nil {
+		return "", nil
+	}
+	// Hand-edited files may have the ID as a number
+	return fmt.Sprint(postID), nil
+}
+
+// getPagePath returns the existing file page has to be written to, if any,
+// and whether page has to be written at all
+func (u *siteUpdater) getPagePath(page wpparser.CommonFields) (string, bool) {
+	existingPath, exists := u.existingFiles[page.PostID]
+	previous, imported := u.manifest.get(page.PostID)
+	if !exists {
+		if imported {
+			// Don't bring back what has been deleted on purpose
+			u.handEditedFiles = append(u.handEditedFiles, path.Join(u.siteDir, previous.Path))
+			return "", false
+		}
+		// New WordPress item
+		return "", true
+	}
+
+	if !imported || !u.isUnmodified(existingPath, previous) {
+		u.handEditedFiles = append(u.handEditedFiles, existingPath)
+		return existingPath, false
+	}
+	if isSameTime(previous.LastModified, page.LastModifiedDate) {
+		u.numUnchanged++
+		return existingPath, false
+	}
+	u.numUpdated++
+	return existingPath, true
+}
+
+// isUnmodified returns true if filePath still is the file written during the previous import
+func (u *siteUpdater) isUnmodified(filePath string, previous importManifestItem) bool {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("path", filePath).
+			Msg("error reading existing file")
+		return false
+	}
+	return getContentHash(content) == previous.ContentHash
+}
+
+func isSameTime(t1 *time.Time, t2 *time.Time) bool {
+	if t1 == nil || t2 == nil {
+		return t1 == t2
+	}
+	return t1.Equal(*t2)
+}
+
+func (u *siteUpdater) report() {
+	for _, filePath := range u.handEditedFiles {
+		log.Warn().
+			Str("path", filePath).
+			Msg("File was modified or deleted since the last import, it has not been overwritten")
+	}
+	log.Info().
+		Int("numUpdated", u.numUpdated).
+		Int("numUnchanged", u.numUnchanged).
+		Int("numHandEdited", len(u.handEditedFiles)).
+		Msg("Existing Hugo site updated")
+}
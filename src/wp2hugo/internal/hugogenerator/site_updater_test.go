@@ -0,0 +1,116 @@
+package hugogenerator
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteUpdater(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	require.NoError(t, writeFile(path.Join(siteDir, "hugo.yaml"), []byte("title: Example\n")))
+
+	file, err := os.Open("./testdata/testcase.WordPress_2.xml")
+	require.NoError(t, err)
+	websiteInfo, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	generator := NewGenerator(siteDir, "", nil, false, false, false, false, ContentDateFolderStructureFlat, *websiteInfo)
+	generator.SetUpdateSiteDir(siteDir)
+	require.NoError(t, generator.Generate(context.Background()))
+
+	post := websiteInfo.Posts()[0]
+	manifest, err := readImportManifest(siteDir)
+	require.NoError(t, err)
+	item, ok := manifest.get(post.PostID)
+	require.True(t, ok)
+	postPath := path.Join(siteDir, item.Path)
+	require.FileExists(t, postPath)
+
+	updater, err := newSiteUpdater(siteDir, manifest)
+	require.NoError(t, err)
+
+	// Unchanged in WordPress
+	existingPath, write := updater.getPagePath(post.CommonFields)
+	require.Equal(t, postPath, existingPath)
+	require.False(t, write)
+
+	// Modified in WordPress
+	modified := post.CommonFields
+	lastModified := time.Now()
+	modified.LastModifiedDate = &lastModified
+	existingPath, write = updater.getPagePath(modified)
+	require.Equal(t, postPath, existingPath)
+	require.True(t, write)
+
+	// Modified in WordPress and by hand
+	handEdited, err := os.OpenFile(postPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = handEdited.WriteString("\nAdded by hand\n")
+	require.NoError(t, err)
+	require.NoError(t, handEdited.Close())
+	_, write = updater.getPagePath(modified)
+	require.False(t, write)
+	require.Equal(t, []string{postPath}, updater.handEditedFiles)
+
+	// New in WordPress
+	newPost := post.CommonFields
+	newPost.PostID = "12345"
+	existingPath, write = updater.getPagePath(newPost)
+	require.Empty(t, existingPath)
+	require.True(t, write)
+}
+
+// The manifest is read when submitting the pages while it is written along with them,
+// run with -race to catch unsynchronized accesses
+func TestSiteUpdater_ConcurrentWorkers(t *testing.T) {
+	t.Parallel()
+	const numPosts = 60
+	export, err := os.ReadFile("./testdata/many_posts.WordPress.xml")
+	require.NoError(t, err)
+	siteDir := t.TempDir()
+	require.NoError(t, writeFile(path.Join(siteDir, "hugo.yaml"), []byte("title: Example\n")))
+
+	generate := func(modifiedDate string) {
+		modifiedExport := strings.ReplaceAll(string(export), "2024-07-01 08:49:15", modifiedDate)
+		websiteInfo, err := wpparser.NewParser().Parse(strings.NewReader(modifiedExport), nil, nil)
+		require.NoError(t, err)
+		generator := NewGenerator(siteDir, "", nil, false, false, false, false, ContentDateFolderStructureFlat, *websiteInfo)
+		generator.SetUpdateSiteDir(siteDir)
+		generator.SetWorkers(8, 4)
+		require.NoError(t, generator.Generate(context.Background()))
+	}
+
+	generate("2024-07-01 08:49:15")
+	manifest, err := readImportManifest(siteDir)
+	require.NoError(t, err)
+	require.Len(t, manifest.items, numPosts)
+
+	// Every post was modified in WordPress
+	generate("2024-08-01 08:49:15")
+	manifest, err = readImportManifest(siteDir)
+	require.NoError(t, err)
+	require.Len(t, manifest.items, numPosts)
+	for postID, item := range manifest.items {
+		require.FileExists(t, path.Join(siteDir, item.Path), postID)
+		require.Equal(t, 2024, item.LastModified.Year())
+		require.Equal(t, time.August, item.LastModified.Month(), postID)
+	}
+}
+
+func TestNewSiteUpdater_NotAHugoSite(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	manifest, err := readImportManifest(siteDir)
+	require.NoError(t, err)
+
+	_, err = newSiteUpdater(siteDir, manifest)
+	require.ErrorContains(t, err, "is not a Hugo site")
+}
@@ -0,0 +1,208 @@
+package hugogenerator
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// TaxonomyName is the name of a taxonomy in Hugo, the plural is the front matter key and the URL of its terms
+// Ref: https://gohugo.io/content-management/taxonomies/#configure-taxonomies
+type TaxonomyName struct {
+	Singular string
+	Plural   string
+}
+
+// Taxonomies of WordPress and plugins, which do not classify the content. The author terms of Co-Authors Plus
+// are the authors taxonomy, see getPageAuthors
+var _internalTaxonomies = []string{
+	"author", "link_category", "nav_menu", "post_format", "product_type", "product_visibility", "wp_pattern_category",
+	"wp_template_part_area", "wp_theme",
+}
+
+// Taxonomies of every site, singular name -> plural name
+var _builtinTaxonomies = map[string]string{
+	"category": hugopage.CategoryName,
+	"tag":      hugopage.TagName,
+	"author":   hugopage.AuthorName,
+}
+
+// ParseTaxonomyNames parses a CSV list of taxonomy:singular:plural, e.g. "portfolio_skills:skill:skills"
+func ParseTaxonomyNames(csv string) (map[string]TaxonomyName, error) {
+	names := make(map[string]TaxonomyName)
+	var singulars, plurals []string
+	for singular, plural := range _builtinTaxonomies {
+		singulars = append(singulars, singular)
+		plurals = append(plurals, plural)
+	}
+	for entry := range strings.SplitSeq(csv, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid taxonomy name %q, expected taxonomy:singular:plural", entry)
+		}
+		if slices.Contains(singulars, parts[1]) {
+			return nil, fmt.Errorf("taxonomy %s cannot be renamed to %s, it is already used", parts[0], parts[1])
+		}
+		if slices.Contains(plurals, parts[2]) {
+			return nil, fmt.Errorf("taxonomy %s cannot be renamed to %s, it is already used", parts[0], parts[2])
+		}
+		singulars = append(singulars, parts[1])
+		plurals = append(plurals, parts[2])
+		names[parts[0]] = TaxonomyName{Singular: parts[1], Plural: parts[2]}
+	}
+	return names, nil
+}
+
+// SetTaxonomyNames renames the taxonomies of WordPress, other than categories and tags, in Hugo
+func (g *Generator) SetTaxonomyNames(names map[string]TaxonomyName) {
+	g.taxonomyNames = names
+}
+
+// getTaxonomyName returns the name of taxonomy in Hugo, by default the one of WordPress
+func getTaxonomyName(names map[string]TaxonomyName, taxonomy string) TaxonomyName {
+	if name, ok := names[taxonomy]; ok {
+		return name
+	}
+	return TaxonomyName{Singular: taxonomy, Plural: taxonomy}
+}
+
+// getCustomTaxonomyName returns the name of taxonomy in Hugo, unless it is internal or its name is the one of
+// categories, tags or authors
+func getCustomTaxonomyName(names map[string]TaxonomyName, taxonomy string) (TaxonomyName, bool) {
+	if taxonomy == "" || slices.Contains(_internalTaxonomies, taxonomy) {
+		return TaxonomyName{}, false
+	}
+	name := getTaxonomyName(names, taxonomy)
+	for singular, plural := range _builtinTaxonomies {
+		if name.Singular == singular || name.Plural == plural || name.Singular == plural || name.Plural == singular {
+			return TaxonomyName{}, false
+		}
+	}
+	return name, true
+}
+
+// getHugoTaxonomies returns the taxonomies of the config, singular name -> plural name
+func getHugoTaxonomies(info wpparser.WebsiteInfo, names map[string]TaxonomyName) map[string]string {
+	taxonomies := maps.Clone(_builtinTaxonomies)
+	ignored := make(map[string]bool)
+	for _, term := range info.Taxonomies() {
+		name, ok := getCustomTaxonomyName(names, term.Taxonomy)
+		if ok {
+			taxonomies[name.Singular] = name.Plural
+		} else if !ignored[term.Taxonomy] && term.Taxonomy != "" && !slices.Contains(_internalTaxonomies, term.Taxonomy) {
+			log.Warn().
+				Str("taxonomy", term.Taxonomy).
+				Msg("Ignoring taxonomy named like categories, tags or authors, rename it with --taxonomy-names")
+		}
+		ignored[term.Taxonomy] = !ok
+	}
+	return taxonomies
+}
+
+// getPageTaxonomies returns the terms of the custom taxonomies of page, with the names of the taxonomies in Hugo
+func (g Generator) getPageTaxonomies(page wpparser.CommonFields) []wpparser.TaxonomyInfo {
+	terms := make([]wpparser.TaxonomyInfo, 0, len(page.Taxonomies))
+	for _, term := range page.Taxonomies {
+		name, ok := getCustomTaxonomyName(g.taxonomyNames, term.Taxonomy)
+		if !ok {
+			continue
+		}
+		term.Taxonomy = name.Plural
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// SetCategoryAncestors adds the ancestors of their categories to the posts, so that like in WordPress,
+// the page of a category lists the posts of its subcategories too
+func (g *Generator) SetCategoryAncestors(categoryAncestors bool) {
+	g.categoryAncestors = categoryAncestors
+}
+
+func (g Generator) getPageCategories(page wpparser.CommonFields) []string {
+	if !g.categoryAncestors {
+		return page.Categories
+	}
+	categories := slices.Clone(page.Categories)
+	for _, category := range page.Categories {
+		for _, ancestor := range g.wpInfo.GetCategoryAncestors(category) {
+			if !slices.Contains(categories, ancestor) {
+				categories = append(categories, ancestor)
+			}
+		}
+	}
+	return categories
+}
+
+// writeTermPages writes the page of every category, tag and term of the other taxonomies,
+// e.g. content/categories/travel/_index.md, with its title, description and parent.
+// Categories and tags are written under their normalized name rather than their slug, as the front matter
+// of the pages: the categories of the items are parsed without their nicename attribute,
+// see wpparser.NormalizeCategoryName
+func writeTermPages(siteDir string, info wpparser.WebsiteInfo, names map[string]TaxonomyName, format string) error {
+	categoryNames := make(map[string]string, len(info.Categories()))
+	for _, category := range info.Categories() {
+		categoryNames[category.NiceName] = category.Name
+	}
+	for _, category := range info.Categories() {
+		page := hugopage.NewTermPage(category.Title, category.Description, categoryNames[category.Parent])
+		if err := writeTermPage(siteDir, hugopage.CategoryName, category.Name, page, format); err != nil {
+			return err
+		}
+	}
+
+	for _, tag := range info.Tags() {
+		page := hugopage.NewTermPage(tag.Title, tag.Description, "")
+		if err := writeTermPage(siteDir, hugopage.TagName, tag.Name, page, format); err != nil {
+			return err
+		}
+	}
+
+	// Terms are referred to by slug, as in the front matter of the pages
+	for _, term := range info.Taxonomies() {
+		name, ok := getCustomTaxonomyName(names, term.Taxonomy)
+		if !ok {
+			continue
+		}
+		page := hugopage.NewTermPage(term.Name, term.Description, term.Parent)
+		if err := writeTermPage(siteDir, name.Plural, term.Slug, page, format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTermPage writes page as content/<taxonomy>/<term>/_index.md. Existing pages are kept,
+// they may have been completed by hand.
+func writeTermPage(siteDir string, taxonomy string, term string, page *hugopage.Page, format string) error {
+	if term == "" {
+		return nil
+	}
+	pagePath := path.Join(siteDir, "content", taxonomy, term, "_index.md")
+	if utils.FileExists(pagePath) {
+		log.Debug().
+			Str("path", pagePath).
+			Msg("Keeping existing term page")
+		return nil
+	}
+	if err := utils.CreateDirIfNotExist(path.Dir(pagePath)); err != nil {
+		return err
+	}
+	var content bytes.Buffer
+	if err := page.Write(&content, format); err != nil {
+		return fmt.Errorf("error writing page of %s %s: %w", taxonomy, term, err)
+	}
+	return writeFile(pagePath, content.Bytes())
+}
@@ -0,0 +1,96 @@
+package hugogenerator
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTermPages(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/terms.WordPress.xml")
+	require.NoError(t, err)
+	info, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	siteDir := t.TempDir()
+	require.NoError(t, writeTermPages(siteDir, *info, nil, utils.FormatYAML))
+
+	for pagePath, expected := range map[string]string{
+		"categories/travel/_index.md":       "---\ndescription: Trips & holidays\ntitle: Travel\n\n---\n\n",
+		"categories/south-italy/_index.md":  "---\nparent: europe\ntitle: South Italy\n\n---\n\n",
+		"tags/food/_index.md":               "---\ndescription: Recipes\ntitle: Food\n\n---\n\n",
+		"portfolio_skills/design/_index.md": "---\ntitle: Web Design\n\n---\n\n",
+		"portfolio_skills/logo/_index.md":   "---\ndescription: Logos\nparent: design\ntitle: Logo\n\n---\n\n",
+	} {
+		content, err := os.ReadFile(path.Join(siteDir, "content", pagePath))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content), pagePath)
+	}
+	// Co-Authors Plus terms, WooCommerce internals and taxonomies named like the built-in ones
+	for _, dirPath := range []string{"author", "product_visibility", "tag"} {
+		require.NoDirExists(t, path.Join(siteDir, "content", dirPath))
+	}
+}
+
+func TestTaxonomyNames(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/terms.WordPress.xml")
+	require.NoError(t, err)
+	info, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	names, err := ParseTaxonomyNames("portfolio_skills:skill:skills, product_tag:product-tag:product-tags")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"category": "categories",
+		"tag":      "tags",
+		"author":   "authors",
+		"skill":    "skills",
+	}, getHugoTaxonomies(*info, names))
+
+	siteDir := t.TempDir()
+	require.NoError(t, writeTermPages(siteDir, *info, names, utils.FormatYAML))
+	require.FileExists(t, path.Join(siteDir, "content", "skills", "logo", "_index.md"))
+
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, ContentDateFolderStructureFlat, *info)
+	generator.SetTaxonomyNames(names)
+	page := wpparser.CommonFields{Taxonomies: []wpparser.TaxonomyInfo{
+		{Taxonomy: "portfolio_skills", Slug: "logo", Name: "Logo"},
+		{Taxonomy: "nav_menu", Slug: "main", Name: "Main"},
+	}}
+	require.Equal(t, []wpparser.TaxonomyInfo{{Taxonomy: "skills", Slug: "logo", Name: "Logo"}}, generator.getPageTaxonomies(page))
+
+	_, err = ParseTaxonomyNames("portfolio_skills:skills")
+	require.ErrorContains(t, err, "expected taxonomy:singular:plural")
+	_, err = ParseTaxonomyNames("portfolio_skills:tag:tags")
+	require.ErrorContains(t, err, "already used")
+	_, err = ParseTaxonomyNames("portfolio_skills:author:writers")
+	require.ErrorContains(t, err, "already used")
+	_, err = ParseTaxonomyNames("portfolio_skills:skill:skills,product_tag:skill:product-skills")
+	require.ErrorContains(t, err, "already used")
+
+	// A taxonomy named like a built-in one is kept if renamed
+	names, err = ParseTaxonomyNames("tag:topic:topics")
+	require.NoError(t, err)
+	require.Equal(t, "topics", getHugoTaxonomies(*info, names)["topic"])
+}
+
+func TestGetPageCategories(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/terms.WordPress.xml")
+	require.NoError(t, err)
+	info, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	generator := NewGenerator(t.TempDir(), "", nil, false, false, false, false, ContentDateFolderStructureFlat, *info)
+	page := wpparser.CommonFields{Categories: []string{"south-italy", "europe"}}
+	require.Equal(t, []string{"south-italy", "europe"}, generator.getPageCategories(page))
+
+	generator.SetCategoryAncestors(true)
+	require.Equal(t, []string{"south-italy", "europe", "travel"}, generator.getPageCategories(page))
+}
@@ -0,0 +1,112 @@
+package hugogenerator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ThemePaperMod = "papermod"
+	ThemeAnanke   = "ananke"
+	// ThemeNone generates the content only, the user brings their own theme
+	ThemeNone = "none"
+)
+
+// Theme is the Hugo theme the generated website is set up for
+type Theme interface {
+	// Name is the directory of the theme in themes/, as set in the Hugo config, empty for no theme
+	Name() string
+	// Install installs the theme into siteDir, a newly created Hugo site
+	Install(ctx context.Context, siteDir string) error
+	// UpdateConfig sets the theme-specific parameters of the Hugo config
+	UpdateConfig(config *_HugoConfig)
+	// FrontMatter maps the theme-specific front matter keys of the pages, like the cover image
+	FrontMatter() hugopage.FrontMatterMapper
+	// SearchURL is the URL of the search page added to the menu, empty if the theme has none
+	SearchURL() string
+	// SetupLayouts writes the theme-specific pages and layout overrides, like archives, search and fonts
+	SetupLayouts(siteDir string, fontName string) error
+	// RSSTemplate returns the RSS template used by the theme, see setupRssFeedFormat
+	RSSTemplate(siteDir string) ([]byte, error)
+}
+
+var _themes = map[string]Theme{
+	ThemePaperMod: paperModTheme{},
+	ThemeAnanke:   anankeTheme{},
+	ThemeNone:     noTheme{},
+}
+
+// GetTheme returns the theme named name, see ThemeNames
+func GetTheme(name string) (Theme, error) {
+	theme, ok := _themes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q (allowed: %v)", name, ThemeNames())
+	}
+	return theme, nil
+}
+
+func ThemeNames() []string {
+	names := make([]string, 0, len(_themes))
+	for name := range _themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// gitCloneTheme clones the theme repository into themes/<name> of siteDir, without its git history
+func gitCloneTheme(ctx context.Context, siteDir string, name string, repositoryURL string) error {
+	commands := []string{
+		"git version",
+		fmt.Sprintf("cd %s && git clone %s themes/%s --depth=1", siteDir, repositoryURL, name),
+	}
+	if err := runCommands(ctx, commands); err != nil {
+		return err
+	}
+	gitDir := fmt.Sprintf("%s/themes/%s/.git", siteDir, name)
+	if err := os.RemoveAll(gitDir); err != nil {
+		log.Error().
+			Err(err).
+			Str("dir", gitDir).
+			Msg("error removing directory")
+		return fmt.Errorf("error removing directory '%s': %w", gitDir, err)
+	}
+	return nil
+}
+
+func runCommands(ctx context.Context, commands []string) error {
+	for i, command := range commands {
+		log.Debug().
+			Int("step", i+1).
+			Int("totalSteps", len(commands)).
+			Str("cmd", command).
+			Msg("Running Hugo setup command")
+		var (
+			output []byte
+			err    error
+		)
+		if runtime.GOOS == "windows" {
+			output, err = exec.CommandContext(ctx, "cmd", "/C", command).Output()
+		} else {
+			// mac & Linux
+			output, err = exec.CommandContext(ctx, "bash", "-c", command).Output()
+		}
+		if err != nil {
+			log.Error().
+				Err(err).
+				Bytes("output", output).
+				Str("cmd", command).
+				Msg("error running Hugo setup command")
+			return fmt.Errorf("error running Hugo setup command '%s' -> %w", command, err)
+		}
+		log.Debug().Msgf("Hugo setup output: %s", output)
+	}
+	return nil
+}
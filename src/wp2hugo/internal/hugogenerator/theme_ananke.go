@@ -0,0 +1,61 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+)
+
+// Loaded by Ananke through its custom_css parameter, from assets/ananke/css/
+const _anankeCustomCSSFile = "wp2hugo.css"
+
+// anankeTheme is Hugo's "getting started" theme
+// Ref: https://github.com/theNewDynamic/gohugo-theme-ananke
+type anankeTheme struct{}
+
+func (anankeTheme) Name() string {
+	return "ananke"
+}
+
+func (t anankeTheme) Install(ctx context.Context, siteDir string) error {
+	return gitCloneTheme(ctx, siteDir, t.Name(), "https://github.com/theNewDynamic/gohugo-theme-ananke")
+}
+
+func (anankeTheme) UpdateConfig(config *_HugoConfig) {
+	config.Params["favicon"] = "/favicon.ico"
+	config.Params["show_reading_time"] = true
+	config.Params["mainSections"] = []string{"posts"}
+	config.Params["custom_css"] = []string{_anankeCustomCSSFile}
+}
+
+func (anankeTheme) FrontMatter() hugopage.FrontMatterMapper {
+	return hugopage.AnankeFrontMatter{}
+}
+
+func (anankeTheme) SearchURL() string {
+	return ""
+}
+
+func (anankeTheme) SetupLayouts(siteDir string, fontName string) error {
+	cssDir := path.Join(siteDir, "assets", "ananke", "css")
+	if err := utils.CreateDirIfNotExist(cssDir); err != nil {
+		return err
+	}
+	partialsDir := path.Join(siteDir, "layouts", "partials")
+	if err := utils.CreateDirIfNotExist(partialsDir); err != nil {
+		return err
+	}
+	// Ananke includes this partial, empty by default, at the end of <head>
+	return errors.Join(
+		writeFile(path.Join(partialsDir, "head-additions.html"), fmt.Appendf(nil, _extendedHeaderData, fontName)),
+		writeFile(path.Join(cssDir, _anankeCustomCSSFile), append(fmt.Appendf(nil, _customFontCSS, fontName), _customCSS...)))
+}
+
+// Ananke relies on the RSS template embedded in Hugo
+func (anankeTheme) RSSTemplate(string) ([]byte, error) {
+	return []byte(_hugoRSSTemplate), nil
+}
@@ -0,0 +1,76 @@
+package hugogenerator
+
+import (
+	"context"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/rs/zerolog/log"
+)
+
+// Same as the RSS template embedded in Hugo, for themes that do not have their own
+// Ref: https://github.com/gohugoio/hugo/blob/master/tpl/tplimpl/embedded/templates/_default/rss.xml
+const _hugoRSSTemplate = `{{- $pctx := . -}}
+{{- if .IsHome -}}{{ $pctx = .Site }}{{- end -}}
+{{- $pages := slice -}}
+{{- if or $.IsHome $.IsSection -}}
+{{- $pages = $pctx.RegularPages -}}
+{{- else -}}
+{{- $pages = $pctx.Pages -}}
+{{- end -}}
+{{- $limit := .Site.Config.Services.RSS.Limit -}}
+{{- if ge $limit 1 -}}
+{{- $pages = $pages | first $limit -}}
+{{- end -}}
+{{- printf "<?xml version=\"1.0\" encoding=\"utf-8\" standalone=\"yes\"?>" | safeHTML }}
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>{{ if eq .Title .Site.Title }}{{ .Site.Title }}{{ else }}{{ with .Title }}{{ . }} on {{ end }}{{ .Site.Title }}{{ end }}</title>
+    <link>{{ .Permalink }}</link>
+    <description>Recent content {{ if ne .Title .Site.Title }}{{ with .Title }}in {{ . }} {{ end }}{{ end }}on {{ .Site.Title }}</description>
+    <generator>Hugo</generator>
+    <language>{{ site.Language.LanguageCode }}</language>
+    {{- with .OutputFormats.Get "RSS" }}
+    {{ printf "<atom:link href=%q rel=\"self\" type=%q />" .Permalink .MediaType | safeHTML }}
+    {{- end }}
+    {{- range $pages }}
+    <item>
+      <title>{{ .Title }}</title>
+      <link>{{ .Permalink }}</link>
+      <pubDate>{{ .PublishDate.Format "Mon, 02 Jan 2006 15:04:05 -0700" | safeHTML }}</pubDate>
+      <guid>{{ .Permalink }}</guid>
+      <description>{{ .Summary | transform.XMLEscape | safeHTML }}</description>
+    </item>
+    {{- end }}
+  </channel>
+</rss>
+`
+
+// noTheme only generates the content, shortcodes and data, the user brings their own theme
+type noTheme struct{}
+
+func (noTheme) Name() string {
+	return ""
+}
+
+func (noTheme) Install(context.Context, string) error {
+	return nil
+}
+
+func (noTheme) UpdateConfig(*_HugoConfig) {}
+
+func (noTheme) FrontMatter() hugopage.FrontMatterMapper {
+	return hugopage.HugoFrontMatter{}
+}
+
+func (noTheme) SearchURL() string {
+	return ""
+}
+
+func (noTheme) SetupLayouts(string, string) error {
+	log.Info().Msg("No theme, the website has no layouts until one is added to the themes directory")
+	return nil
+}
+
+func (noTheme) RSSTemplate(string) ([]byte, error) {
+	return []byte(_hugoRSSTemplate), nil
+}
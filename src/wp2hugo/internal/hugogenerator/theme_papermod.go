@@ -0,0 +1,105 @@
+package hugogenerator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+)
+
+const _archiveContent = `
+---
+title: "All"
+layout: "archives"
+url: "/all/"
+summary: archives
+---
+`
+
+const _searchContent = `
+---
+title: "Search" # in any language you want
+layout: "search" # necessary for search
+summary: "Search"
+url: "/search/"
+placeholder: "placeholder text in search input box"
+---
+`
+
+// paperModTheme is the default theme
+// Ref: https://github.com/adityatelange/hugo-PaperMod
+type paperModTheme struct{}
+
+func (paperModTheme) Name() string {
+	return "PaperMod"
+}
+
+func (t paperModTheme) Install(ctx context.Context, siteDir string) error {
+	return gitCloneTheme(ctx, siteDir, t.Name(), "https://github.com/adityatelange/hugo-PaperMod")
+}
+
+// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-faq/
+func (paperModTheme) UpdateConfig(config *_HugoConfig) {
+	config.Params["assets"] = map[string]any{
+		"favicon":     "/favicon.ico",
+		"disableHLJS": true,
+	}
+	// To switch between dark or light according to browser theme
+	config.Params["defaultTheme"] = "auto"
+	config.Params["disableThemeToggle"] = true
+	config.Params["showShareButtons"] = true
+	config.Params["showReadingTime"] = true
+	config.Params["showToc"] = false
+	config.Params["showBreadCrumbs"] = true
+	config.Params["showCodeCopyButtons"] = true
+	config.Params["comments"] = true
+	config.Params["hideFooter"] = true
+	// https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#search-page
+	config.Outputs.Home = append(config.Outputs.Home, "JSON")
+}
+
+func (paperModTheme) FrontMatter() hugopage.FrontMatterMapper {
+	return hugopage.PaperModFrontMatter{}
+}
+
+func (paperModTheme) SearchURL() string {
+	return "/search/"
+}
+
+func (paperModTheme) SetupLayouts(siteDir string, fontName string) error {
+	return errors.Join(
+		setupArchivePage(siteDir),
+		setupSearchPage(siteDir),
+		setupFont(siteDir, fontName))
+}
+
+func (t paperModTheme) RSSTemplate(siteDir string) ([]byte, error) {
+	primaryPath := path.Join(siteDir, "themes", t.Name(), "layouts", "_default", "rss.xml")
+	fallbackPath := path.Join(siteDir, "themes", t.Name(), "layouts", "rss.xml")
+	data, err := os.ReadFile(primaryPath)
+	if err != nil {
+		data, err = os.ReadFile(fallbackPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading rss.xml from PaperMod theme (tried %q and %q): %w",
+				primaryPath, fallbackPath, err)
+		}
+	}
+	return data, nil
+}
+
+// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#archives-layout
+func setupArchivePage(siteDir string) error {
+	filePath := path.Join(siteDir, "content", "archives.md")
+	content := _archiveContent
+	return writeFile(filePath, []byte(content))
+}
+
+// Ref: https://adityatelange.github.io/hugo-PaperMod/posts/papermod/papermod-features/#search-page
+func setupSearchPage(siteDir string) error {
+	filePath := path.Join(siteDir, "content", "search.md")
+	content := _searchContent
+	return writeFile(filePath, []byte(content))
+}
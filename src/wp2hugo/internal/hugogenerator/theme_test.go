@@ -0,0 +1,71 @@
+package hugogenerator
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGetTheme(t *testing.T) {
+	t.Parallel()
+	theme, err := GetTheme(ThemeAnanke)
+	require.NoError(t, err)
+	require.Equal(t, "ananke", theme.Name())
+
+	_, err = GetTheme("unknown")
+	require.ErrorContains(t, err, "unknown theme")
+}
+
+func TestUpdateConfigWithTheme(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/testcase.WordPress_2.xml")
+	require.NoError(t, err)
+	websiteInfo, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		theme         string
+		expectedTheme string
+		expectedHome  []string
+		expectedParam string
+	}{
+		{ThemePaperMod, "PaperMod", []string{"HTML", "RSS", "JSON"}, "defaultTheme"},
+		{ThemeAnanke, "ananke", []string{"HTML", "RSS"}, "custom_css"},
+		{ThemeNone, "", []string{"HTML", "RSS"}, "description"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.theme, func(t *testing.T) {
+			t.Parallel()
+			siteDir := t.TempDir()
+			require.NoError(t, writeFile(path.Join(siteDir, "hugo.yaml"), []byte("title: Example\n")))
+			theme, err := GetTheme(testCase.theme)
+			require.NoError(t, err)
+
+			require.NoError(t, updateConfig(siteDir, *websiteInfo, theme, utils.FormatYAML, nil, ""))
+
+			data, err := os.ReadFile(path.Join(siteDir, "hugo.yaml"))
+			require.NoError(t, err)
+			var config _HugoConfig
+			require.NoError(t, yaml.Unmarshal(data, &config))
+			require.Equal(t, testCase.expectedTheme, config.Theme)
+			require.Equal(t, testCase.expectedHome, config.Outputs.Home)
+			require.Contains(t, config.Params, testCase.expectedParam)
+			require.Contains(t, config.Params, "author")
+		})
+	}
+}
+
+func TestSetupRssFeedFormatWithoutThemeTemplate(t *testing.T) {
+	t.Parallel()
+	siteDir := t.TempDir()
+	require.NoError(t, setupRssFeedFormat(siteDir, noTheme{}))
+
+	data, err := os.ReadFile(path.Join(siteDir, "layouts", "rss.xml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "{{ .Params.guid }}")
+}
@@ -2,8 +2,6 @@ package hugogenerator
 
 import (
 	"bytes"
-	"fmt"
-	"os"
 	"path"
 
 	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
@@ -11,21 +9,15 @@ import (
 
 // setupRssFeedFormat sets up custom guid for RSS feed that is being migrated from WordPress
 // to Hugo
-func setupRssFeedFormat(siteDir string) error {
+func setupRssFeedFormat(siteDir string, theme Theme) error {
 	if err := utils.CreateDirIfNotExist(path.Join(siteDir, "layouts")); err != nil {
 		return err
 	}
-	// Read from the paperMod theme and then generate a new rss.xml that will
+	// Read from the theme and then generate a new rss.xml that will
 	// take precendece over the theme's rss.xml
-	primaryPath := path.Join(siteDir, "themes", "PaperMod", "layouts", "_default", "rss.xml")
-	fallbackPath := path.Join(siteDir, "themes", "PaperMod", "layouts", "rss.xml")
-	data, err := os.ReadFile(primaryPath)
+	data, err := theme.RSSTemplate(siteDir)
 	if err != nil {
-		data, err = os.ReadFile(fallbackPath)
-		if err != nil {
-			return fmt.Errorf("error reading rss.xml from PaperMod theme (tried %q and %q): %w",
-				primaryPath, fallbackPath, err)
-		}
+		return err
 	}
 	rssFile := path.Join(siteDir, "layouts", "rss.xml")
 	return writeFile(rssFile, getModifiedRSSXML(data))
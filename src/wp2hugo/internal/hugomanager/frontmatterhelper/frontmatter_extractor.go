@@ -10,30 +10,32 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+// FrontMatter is the part of the front matter of the pages used by hugomanager,
+// in YAML, TOML or JSON
 type FrontMatter struct {
-	URL         string `yaml:"url"`
-	PublishDate string `yaml:"date"`
+	URL         string `yaml:"url" toml:"url" json:"url"`
+	PublishDate string `yaml:"date" toml:"date" json:"date"`
 
-	Description *string `yaml:"description"`
-	Summary     string  `yaml:"summary"`
-	Title       string  `yaml:"title"`
+	Description *string `yaml:"description" toml:"description" json:"description"`
+	Summary     string  `yaml:"summary" toml:"summary" json:"summary"`
+	Title       string  `yaml:"title" toml:"title" json:"title"`
 
-	Categories []string `yaml:"category"`
-	Draft      string   `yaml:"draft"`
-	Tags       []string `yaml:"tag"`
+	Categories []string `yaml:"category" toml:"category" json:"category"`
+	Draft      string   `yaml:"draft" toml:"draft" json:"draft"`
+	Tags       []string `yaml:"tag" toml:"tag" json:"tag"`
 
-	Layout      *string `yaml:"layout,omitempty"`      // Used by Hugo papermod theme
-	Placeholder *string `yaml:"placeholder,omitempty"` // Used by Hugo papermod theme for search page
-	GUID        string  `yaml:"guid"`                  // For RSS and Atom feeds
-	Author      any     `yaml:"author"`                // Either "string" or "[]string"
+	Layout      *string `yaml:"layout,omitempty" toml:"layout,omitempty" json:"layout,omitempty"`                // Used by Hugo papermod theme
+	Placeholder *string `yaml:"placeholder,omitempty" toml:"placeholder,omitempty" json:"placeholder,omitempty"` // Used by Hugo papermod theme for search page
+	GUID        string  `yaml:"guid" toml:"guid" json:"guid"`                                                    // For RSS and Atom feeds
+	Author      any     `yaml:"author" toml:"author" json:"author"`                                              // Either "string" or "[]string"
 	Cover       struct {
-		Alt     *string `yaml:"alt,omitempty"`
-		Caption *string `yaml:"caption,omitempty"`
-		Image   string  `yaml:"image,omitempty"`
-	} `yaml:"cover"`
-	Aliases []string `yaml:"aliases"`           // For redirects
-	ShowToc *bool    `yaml:"ShowToc,omitempty"` // For Hugo papermod theme
-	TocOpen *bool    `yaml:"TocOpen,omitempty"` // For Hugo papermod theme
+		Alt     *string `yaml:"alt,omitempty" toml:"alt,omitempty" json:"alt,omitempty"`
+		Caption *string `yaml:"caption,omitempty" toml:"caption,omitempty" json:"caption,omitempty"`
+		Image   string  `yaml:"image,omitempty" toml:"image,omitempty" json:"image,omitempty"`
+	} `yaml:"cover" toml:"cover" json:"cover"`
+	Aliases []string `yaml:"aliases" toml:"aliases" json:"aliases"`                               // For redirects
+	ShowToc *bool    `yaml:"ShowToc,omitempty" toml:"ShowToc,omitempty" json:"ShowToc,omitempty"` // For Hugo papermod theme
+	TocOpen *bool    `yaml:"TocOpen,omitempty" toml:"TocOpen,omitempty" json:"TocOpen,omitempty"` // For Hugo papermod theme
 }
 
 func (f *FrontMatter) IsDraft() bool {
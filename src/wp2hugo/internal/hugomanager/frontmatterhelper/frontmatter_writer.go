@@ -1,17 +1,20 @@
 package frontmatterhelper
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/adrg/frontmatter"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
 	"github.com/rs/zerolog/log"
-	"gopkg.in/yaml.v3"
 )
 
+// UpdateFrontmatter sets key, either top-level or "parent.child", to value in the front matter of
+// the file at path, keeping the format of the front matter (YAML, TOML or JSON)
 func UpdateFrontmatter(path string, key string, value string) error {
-	fullmatter, restOfTheFile, err := getFullFrontMatter(path)
+	fullmatter, restOfTheFile, format, err := getFullFrontMatter(path)
 	if err != nil {
 		return err
 	}
@@ -26,62 +29,55 @@ func UpdateFrontmatter(path string, key string, value string) error {
 				Str("key", key).
 				Msg("Key is nil")
 		}
-		map1, ok := fullmatter[key0].(map[any]any)
-		if !ok {
+		// YAML maps are decoded as map[any]any, TOML and JSON ones as map[string]any
+		switch map1 := fullmatter[key0].(type) {
+		case map[any]any:
+			map1[key1] = value
+		case map[string]any:
+			map1[key1] = value
+		default:
 			log.Fatal().
 				Str("key", key).
 				Any("map1", map1).
 				Any("fullmatter", fullmatter).
 				Msg("Key is not a map")
 		}
-		map1[key1] = value
 	} else {
 		return fmt.Errorf("key '%s' is not supported", key)
 	}
-	file, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0o644)
+	data, err := utils.Marshal(format, fullmatter)
 	if err != nil {
 		return err
 	}
 
-	_, err = file.WriteString("---\n")
-	if err != nil {
-		return err
-	}
-
-	yamlEncoder := yaml.NewEncoder(file)
-	yamlEncoder.SetIndent(2)
-	err = yamlEncoder.Encode(&fullmatter)
-	if err != nil {
-		return err
-	}
-	_, err = file.WriteString("---\n")
-	if err != nil {
-		return err
-	}
-	_, err = file.Write(restOfTheFile)
-	if err != nil {
-		return err
+	var content bytes.Buffer
+	if delimiter := utils.FrontMatterDelimiter(format); delimiter != "" {
+		content.WriteString(delimiter + "\n")
+		content.Write(data)
+		content.WriteString(delimiter + "\n")
+	} else {
+		// JSON front matter ends with an empty line
+		content.Write(data)
+		content.WriteString("\n")
 	}
-	return nil
+	content.Write(restOfTheFile)
+	return os.WriteFile(path, content.Bytes(), 0o644)
 }
 
-func getFullFrontMatter(path string) (map[string]any, []byte, error) {
-	file, err := os.Open(path)
+func getFullFrontMatter(path string) (map[string]any, []byte, string, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, "", err
 	}
-	defer func() {
-		_ = file.Close()
-	}()
 
 	var matter map[string]any
-	restOfTheFile, err := frontmatter.Parse(file, &matter)
+	restOfTheFile, err := frontmatter.Parse(bytes.NewReader(data), &matter)
 	if err != nil {
 		log.Error().
 			Err(err).
 			Str("path", path).
 			Msg("Error parsing front matter")
-		return nil, nil, err
+		return nil, nil, "", err
 	}
-	return matter, restOfTheFile, nil
+	return matter, restOfTheFile, utils.GetFormatFromFrontMatter(data), nil
 }
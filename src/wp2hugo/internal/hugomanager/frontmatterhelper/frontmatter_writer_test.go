@@ -0,0 +1,52 @@
+package frontmatterhelper
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateFrontmatter_KeepsFormat(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "yaml",
+			content:  "---\ntitle: Hello\ncover:\n  image: /a.jpg\n---\nBody\n",
+			expected: "---\ncover:\n  alt: An image\n  image: /a.jpg\ntitle: Hello\n---\nBody\n",
+		},
+		{
+			name:     "toml",
+			content:  "+++\ntitle = 'Hello'\n[cover]\nimage = '/a.jpg'\n+++\nBody\n",
+			expected: "+++\ntitle = \"Hello\"\n\n[cover]\n  alt = \"An image\"\n  image = \"/a.jpg\"\n+++\nBody\n",
+		},
+		{
+			name:     "json",
+			content:  "{\n  \"title\": \"Hello\",\n  \"cover\": {\"image\": \"/a.jpg\"}\n}\n\nBody\n",
+			expected: "{\n  \"cover\": {\n    \"alt\": \"An image\",\n    \"image\": \"/a.jpg\"\n  },\n  \"title\": \"Hello\"\n}\n\nBody\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			filePath := path.Join(t.TempDir(), "index.md")
+			require.NoError(t, os.WriteFile(filePath, []byte(testCase.content), 0o644))
+
+			require.NoError(t, UpdateFrontmatter(filePath, "cover.alt", "An image"))
+
+			data, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, string(data))
+
+			frontMatter, err := GetSelectiveFrontMatter(filePath)
+			require.NoError(t, err)
+			require.Equal(t, "Hello", frontMatter.Title)
+			require.Equal(t, "An image", *frontMatter.Cover.Alt)
+		})
+	}
+}
@@ -21,12 +21,18 @@ import (
 // The media file cannot be downloaded in this case.
 var ErrMediaNotAcceptable = errors.New("media not acceptable (HTTP 406)")
 
+// MediaCache fetches media files and keeps them in cacheDirPath, it is safe for concurrent use
 type MediaCache struct {
 	cacheDirPath string
+	rateLimiter  *hostRateLimiter
 }
 
-func New(cacheDirPath string) MediaCache {
-	return MediaCache{cacheDirPath: cacheDirPath}
+// New returns a MediaCache sending at most requestsPerSecond requests to each host
+func New(cacheDirPath string, requestsPerSecond float64) MediaCache {
+	return MediaCache{
+		cacheDirPath: cacheDirPath,
+		rateLimiter:  newHostRateLimiter(requestsPerSecond),
+	}
 }
 
 func waitOrStop(resp *http.Response) (int, bool) {
@@ -100,7 +106,7 @@ func (m MediaCache) GetReader(ctx context.Context, url string) (io.Reader, error
 		Msg("media will be fetched")
 
 	retries := 0
-	timeout := 1
+	timeout := 0
 	stop := false
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -112,9 +118,11 @@ func (m MediaCache) GetReader(ctx context.Context, url string) (io.Reader, error
 	var resp *http.Response
 
 	for retries < 5 && !stop {
-		// Send at most 1 request per second
-		// to avoid hammering servers and getting rate-limited.
 		time.Sleep(time.Duration(timeout) * time.Second)
+		// Avoid hammering servers and getting rate-limited
+		if err := m.rateLimiter.wait(ctx, req.URL.Host); err != nil {
+			return nil, fmt.Errorf("error fetching media %s: %w", url, err)
+		}
 		resp, httpErr = http.DefaultClient.Do(req)
 		timeout, stop = waitOrStop(resp)
 		retries++
@@ -134,18 +142,24 @@ func (m MediaCache) GetReader(ctx context.Context, url string) (io.Reader, error
 	defer func() {
 		_ = resp.Body.Close()
 	}()
-	file, err = os.OpenFile(path.Join(m.cacheDirPath, key), os.O_CREATE|os.O_WRONLY, 0o644)
+	// Written to a temporary file first, the same media may be fetched concurrently
+	file, err = os.CreateTemp(m.cacheDirPath, key+".*.tmp")
 	if err != nil {
 		return nil, fmt.Errorf("error creating cache file for media %s: %w", url, err)
 	}
 	_, err = io.Copy(file, resp.Body)
 	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
 		return nil, fmt.Errorf("error writing media to cache %s: %w", url, err)
 	}
 
 	if err := file.Close(); err != nil {
 		return nil, fmt.Errorf("error closing cache file for media %s: %w", url, err)
 	}
+	if err := os.Rename(file.Name(), path.Join(m.cacheDirPath, key)); err != nil {
+		return nil, fmt.Errorf("error moving cache file for media %s: %w", url, err)
+	}
 
 	file, err = os.OpenFile(path.Join(m.cacheDirPath, key), os.O_RDONLY, 0o644)
 	if err != nil {
@@ -0,0 +1,45 @@
+package mediacache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// hostRateLimiter spaces the requests sent to the same host, requests to different hosts
+// are not limited
+type hostRateLimiter struct {
+	interval time.Duration
+
+	mutex sync.Mutex
+	// Time at which the next request to the host can be sent
+	next map[string]time.Time
+}
+
+func newHostRateLimiter(requestsPerSecond float64) *hostRateLimiter {
+	return &hostRateLimiter{
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		next:     make(map[string]time.Time),
+	}
+}
+
+// wait blocks until a request can be sent to host
+func (l *hostRateLimiter) wait(ctx context.Context, host string) error {
+	l.mutex.Lock()
+	now := time.Now()
+	next := l.next[host]
+	if next.Before(now) {
+		next = now
+	}
+	l.next[host] = next.Add(l.interval)
+	l.mutex.Unlock()
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,36 @@
+package mediacache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostRateLimiter(t *testing.T) {
+	t.Parallel()
+	limiter := newHostRateLimiter(10)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, limiter.wait(ctx, "example.com"))
+	}
+	// The first request is not delayed, the next ones are 100ms apart
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	// Other hosts are not limited
+	start = time.Now()
+	require.NoError(t, limiter.wait(ctx, "example.org"))
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestHostRateLimiter_Canceled(t *testing.T) {
+	t.Parallel()
+	limiter := newHostRateLimiter(0.1)
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, limiter.wait(ctx, "example.com"))
+	cancel()
+	require.ErrorIs(t, limiter.wait(ctx, "example.com"), context.Canceled)
+}
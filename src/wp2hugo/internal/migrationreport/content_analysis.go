@@ -0,0 +1,49 @@
+package migrationreport
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// E.g. `\[contact-form-7 id="12"\]` as escaped by the HTML to Markdown conversion, or `[/caption]`
+var _wordPressShortcode = regexp.MustCompile(`\\?\[(/?)([a-zA-Z][\w-]*)((?:\s+[^\]\n]*?)?)\\?\](?:[^(\[]|$)`)
+
+// Shortcodes which are common enough to be reported even without attributes nor closing tag
+var _knownShortcodes = []string{
+	"audio", "caption", "embed", "gallery", "playlist", "video", "wp_caption",
+	"contact-form-7", "gravityform", "ninja_form", "wpforms", "vc_row", "vc_column", "et_pb_section",
+}
+
+// FindShortcodes returns the names of the WordPress shortcodes left in markdown, in order of appearance.
+// Text between brackets is only considered a shortcode if it has attributes, is a closing tag
+// or has a well known name, so that e.g. "[sic]" is not reported
+func FindShortcodes(markdown string) []string {
+	var names []string
+	for _, match := range _wordPressShortcode.FindAllStringSubmatch(markdown, -1) {
+		name := match[2]
+		isShortcode := match[1] == "/" || strings.Contains(match[3], "=") || slices.Contains(_knownShortcodes, name)
+		if isShortcode && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// FindExternalMedia returns the links which are not on the website at siteHost, such media are not downloaded
+func FindExternalMedia(links []string, siteHost string) []string {
+	siteHost = strings.TrimPrefix(siteHost, "www.")
+	var external []string
+	for _, link := range links {
+		u, err := url.Parse(strings.TrimSpace(link))
+		if err != nil || u.Host == "" {
+			// Relative links are on the website
+			continue
+		}
+		if strings.TrimPrefix(u.Host, "www.") != siteHost && !slices.Contains(external, link) {
+			external = append(external, link)
+		}
+	}
+	return external
+}
@@ -0,0 +1,28 @@
+package migrationreport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindShortcodes(t *testing.T) {
+	t.Parallel()
+	markdown := `Contact us: \[contact-form-7 id="12" title="Contact"\]
+
+\[vc_row\]Some text\[/vc_row\] and [a link](https://example.com) [sic], see [caption]
+- [x] done`
+	require.Equal(t, []string{"contact-form-7", "vc_row", "caption"}, FindShortcodes(markdown))
+	require.Empty(t, FindShortcodes("No [shortcode] here, only [links](/a) and [references][1]"))
+}
+
+func TestFindExternalMedia(t *testing.T) {
+	t.Parallel()
+	links := []string{
+		"/wp-content/uploads/a.jpg",
+		"https://www.example.com/wp-content/uploads/b.jpg",
+		"https://cdn.other.com/c.jpg",
+		"https://cdn.other.com/c.jpg",
+	}
+	require.Equal(t, []string{"https://cdn.other.com/c.jpg"}, FindExternalMedia(links, "example.com"))
+}
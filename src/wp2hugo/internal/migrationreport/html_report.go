@@ -0,0 +1,64 @@
+package migrationreport
+
+import (
+	"html/template"
+	"strings"
+)
+
+var _htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>wp2hugo migration report: {{ .Website }}</title>
+<style>
+  body { font-family: sans-serif; margin: 2rem; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { border: 1px solid #ccc; padding: 0.3rem 0.5rem; text-align: left; vertical-align: top; }
+  tr.issues { background: #fff4e5; }
+  code { font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Migration report: {{ .Website }}</h1>
+<p>Generated on {{ .GeneratedAt.Format "2006-01-02 15:04:05" }}</p>
+<h2>Summary</h2>
+<ul>
+  <li>Items: {{ .Summary.NumItems }}, with issues: {{ .Summary.NumItemsWithIssues }}</li>
+  <li>Unconverted shortcodes: {{ .Summary.NumUnconvertedShortcodes }}</li>
+  <li>Unconverted Gutenberg blocks: {{ .Summary.NumUnconvertedBlocks }}</li>
+  <li>External media, not downloaded: {{ .Summary.NumExternalMedia }}</li>
+  <li>Empty content: {{ .Summary.NumEmptyContent }}</li>
+  <li>Unknown categories: {{ .Summary.NumUnknownCategories }}</li>
+  <li>Links to items not exported: {{ .Summary.NumMissingLinks }}</li>
+  <li>File name collisions: {{ .Summary.NumCollisions }}</li>
+</ul>
+<h2>Items</h2>
+<table>
+<thead>
+<tr><th>ID</th><th>Type</th><th>Title</th><th>Path</th><th>Issues</th></tr>
+</thead>
+<tbody>
+{{- range .Items }}
+<tr{{ if .HasIssues }} class="issues"{{ end }}>
+  <td>{{ .PostID }}</td>
+  <td>{{ .PostType }}</td>
+  <td><a href="{{ .Link }}">{{ .Title }}</a></td>
+  <td><code>{{ .Path }}</code></td>
+  <td>
+    {{- if .UnconvertedShortcodes }}<div>Shortcodes: <code>{{ join .UnconvertedShortcodes ", " }}</code></div>{{ end }}
+    {{- if .UnconvertedBlocks }}<div>Gutenberg blocks: <code>{{ join .UnconvertedBlocks ", " }}</code></div>{{ end }}
+    {{- if .ExternalMedia }}<div>External media:<ul>{{ range .ExternalMedia }}<li><a href="{{ . }}">{{ . }}</a></li>{{ end }}</ul></div>{{ end }}
+    {{- if .EmptyContent }}<div>Empty content</div>{{ end }}
+    {{- if .UnknownCategories }}<div>Unknown categories: {{ join .UnknownCategories ", " }}</div>{{ end }}
+    {{- if .MissingLinks }}<div>Links to items not exported:<ul>{{ range .MissingLinks }}<li><code>{{ . }}</code></li>{{ end }}</ul></div>{{ end }}
+    {{- if .CollidesWith }}<div>Same file name as <code>{{ .CollidesWith }}</code></div>{{ end }}
+  </td>
+</tr>
+{{- end }}
+</tbody>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,167 @@
+package migrationreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+// Formats of the report
+const (
+	FormatJSON = "json"
+	FormatHTML = "html"
+)
+
+// Report lists, for each WordPress item, the file it is written to and what should be fixed
+// in WordPress before the import, see the `-dry-run` flag
+type Report struct {
+	Website     string    `json:"website"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Summary     Summary   `json:"summary"`
+	Items       []Item    `json:"items"`
+
+	knownCategories map[string]bool
+	// Absolute path -> index of the item written to it
+	itemsByPath map[string]int
+	// Index of an item -> index of the item it collides with
+	collisions map[int]int
+}
+
+type Summary struct {
+	NumItems                 int `json:"num_items"`
+	NumItemsWithIssues       int `json:"num_items_with_issues"`
+	NumUnconvertedShortcodes int `json:"num_unconverted_shortcodes"`
+	NumUnconvertedBlocks     int `json:"num_unconverted_blocks"`
+	NumExternalMedia         int `json:"num_external_media"`
+	NumEmptyContent          int `json:"num_empty_content"`
+	NumUnknownCategories     int `json:"num_unknown_categories"`
+	NumMissingLinks          int `json:"num_missing_links"`
+	NumCollisions            int `json:"num_collisions"`
+}
+
+type Item struct {
+	PostID   string `json:"post_id"`
+	PostType string `json:"post_type"`
+	Title    string `json:"title"`
+	Link     string `json:"link"`
+	// Relative to the site directory
+	Path string `json:"path"`
+
+	UnconvertedShortcodes []string `json:"unconverted_shortcodes,omitempty"`
+	UnconvertedBlocks     []string `json:"unconverted_blocks,omitempty"`
+	// Media on other websites, which are not downloaded
+	ExternalMedia     []string `json:"external_media,omitempty"`
+	EmptyContent      bool     `json:"empty_content,omitempty"`
+	UnknownCategories []string `json:"unknown_categories,omitempty"`
+	// Links to items of the website which are not exported
+	MissingLinks []string `json:"missing_links,omitempty"`
+	// Path of the item which has the same file name, this item got a numeric suffix instead
+	CollidesWith string `json:"collides_with,omitempty"`
+}
+
+// HasIssues returns true if anything should be fixed before the import
+func (i Item) HasIssues() bool {
+	return len(i.UnconvertedShortcodes) > 0 || len(i.UnconvertedBlocks) > 0 || len(i.ExternalMedia) > 0 ||
+		i.EmptyContent || len(i.UnknownCategories) > 0 || len(i.MissingLinks) > 0 || i.CollidesWith != ""
+}
+
+// New returns an empty report for the website of info
+func New(info wpparser.WebsiteInfo) *Report {
+	// Categories of custom post types, e.g. product_cat, are terms of taxonomies
+	known := make(map[string]bool)
+	for _, category := range info.Categories() {
+		known[category.Name] = true
+	}
+	for _, term := range info.Taxonomies() {
+		known[wpparser.NormalizeCategoryName(term.Name)] = true
+	}
+	website := ""
+	if info.Link() != nil {
+		website = info.Link().String()
+	}
+	return &Report{
+		Website:         website,
+		GeneratedAt:     time.Now(),
+		Items:           []Item{},
+		knownCategories: known,
+		itemsByPath:     make(map[string]int),
+		collisions:      make(map[int]int),
+	}
+}
+
+// UnknownCategories returns the categories which are not declared in the export
+func (r *Report) UnknownCategories(categories []string) []string {
+	var unknown []string
+	for _, category := range categories {
+		if !r.knownCategories[category] && !slices.Contains(unknown, category) {
+			unknown = append(unknown, category)
+		}
+	}
+	return unknown
+}
+
+// Add records item, whose Path is the absolute path of the file it has been written to, and
+// CollidesWith the absolute path which was already taken, if a numeric suffix had to be added.
+// Items have to be added in the order they are written
+func (r *Report) Add(item Item) {
+	index := len(r.Items)
+	if item.CollidesWith != "" {
+		if other, ok := r.itemsByPath[item.CollidesWith]; ok {
+			r.collisions[index] = other
+		}
+		// Set by Finish, relative to the site directory
+		item.CollidesWith = ""
+	}
+	r.itemsByPath[item.Path] = index
+	r.Items = append(r.Items, item)
+}
+
+// Finish sets the final paths, returned by getPath, and computes the summary
+func (r *Report) Finish(getPath func(postID string) string) {
+	for i := range r.Items {
+		r.Items[i].Path = getPath(r.Items[i].PostID)
+	}
+	r.Summary = Summary{NumItems: len(r.Items)}
+	for i := range r.Items {
+		item := &r.Items[i]
+		if other, ok := r.collisions[i]; ok {
+			item.CollidesWith = r.Items[other].Path
+			r.Summary.NumCollisions++
+		}
+		if item.HasIssues() {
+			r.Summary.NumItemsWithIssues++
+		}
+		r.Summary.NumUnconvertedShortcodes += len(item.UnconvertedShortcodes)
+		r.Summary.NumUnconvertedBlocks += len(item.UnconvertedBlocks)
+		r.Summary.NumExternalMedia += len(item.ExternalMedia)
+		r.Summary.NumUnknownCategories += len(item.UnknownCategories)
+		r.Summary.NumMissingLinks += len(item.MissingLinks)
+		if item.EmptyContent {
+			r.Summary.NumEmptyContent++
+		}
+	}
+}
+
+// Write writes the report in format, FormatJSON or FormatHTML
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(r); err != nil {
+			return fmt.Errorf("error writing JSON report: %w", err)
+		}
+		return nil
+	case FormatHTML:
+		if err := _htmlReport.Execute(w, r); err != nil {
+			return fmt.Errorf("error writing HTML report: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
@@ -0,0 +1,68 @@
+package migrationreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_Collisions(t *testing.T) {
+	t.Parallel()
+	report := New(wpparser.WebsiteInfo{})
+	report.Add(Item{PostID: "1", Path: "/tmp/site/content/posts/hello.md"})
+	report.Add(Item{
+		PostID: "2", Path: "/tmp/site/content/posts/hello-1.md", CollidesWith: "/tmp/site/content/posts/hello.md",
+	})
+	// Its slug ends with a number, along with the one of a post without it
+	report.Add(Item{PostID: "3", Path: "/tmp/site/content/posts/hello-2.md"})
+	report.Add(Item{PostID: "4", Path: "/tmp/site/content/pages/about/_index.fr.md"})
+	report.Add(Item{
+		PostID:       "5",
+		Path:         "/tmp/site/content/pages/about/_index-1.fr.md",
+		CollidesWith: "/tmp/site/content/pages/about/_index.fr.md",
+	})
+	report.Finish(func(postID string) string {
+		return map[string]string{
+			"1": "content/posts/hello.md",
+			"2": "content/posts/hello-1.md",
+			"3": "content/posts/hello-2.md",
+			// Renamed since, e.g. to a leaf bundle
+			"4": "content/pages/about/index.fr.md",
+			"5": "content/pages/about/_index-1.fr.md",
+		}[postID]
+	})
+
+	require.Empty(t, report.Items[0].CollidesWith)
+	require.Equal(t, "content/posts/hello.md", report.Items[1].CollidesWith)
+	require.Empty(t, report.Items[2].CollidesWith)
+	require.Equal(t, "content/pages/about/index.fr.md", report.Items[4].CollidesWith)
+	require.Equal(t, 2, report.Summary.NumCollisions)
+	require.Equal(t, 2, report.Summary.NumItemsWithIssues)
+}
+
+func TestReport_Write(t *testing.T) {
+	t.Parallel()
+	report := New(wpparser.WebsiteInfo{})
+	report.Add(Item{PostID: "1", Title: "<Hello>", Path: "/tmp/site/content/posts/hello.md", EmptyContent: true})
+	report.Finish(func(string) string {
+		return "content/posts/hello.md"
+	})
+
+	var output bytes.Buffer
+	require.NoError(t, report.Write(&output, FormatJSON))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	require.Equal(t, report.Items, decoded.Items)
+	require.Equal(t, 1, decoded.Summary.NumEmptyContent)
+
+	output.Reset()
+	require.NoError(t, report.Write(&output, FormatHTML))
+	require.Contains(t, output.String(), "&lt;Hello&gt;")
+	require.Contains(t, output.String(), "<code>content/posts/hello.md</code>")
+	require.Contains(t, output.String(), "Empty content")
+
+	require.Error(t, report.Write(&output, "xml"))
+}
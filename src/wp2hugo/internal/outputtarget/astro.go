@@ -0,0 +1,420 @@
+package outputtarget
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Relative to the content files, see astroTarget.PagePath
+const _astroComponentsImport = "../../components/wp2hugo"
+
+var (
+	_htmlComment = regexp.MustCompile(`(?s)<!--(.*?)-->`)
+	// A "<" which does not start a tag, e.g. "a < b"
+	_loneLessThan = regexp.MustCompile(`<([^a-zA-Z/!{]|$)`)
+	// MDX requires void elements to be closed, e.g. <br />
+	_voidElement = regexp.MustCompile(`<(area|base|br|col|embed|hr|img|input|link|meta|source|track|wbr)\b([^>]*?)\s*/?>`)
+	_inlineCode  = regexp.MustCompile("`[^`\n]*`")
+)
+
+var _astroComponents = map[string]string{
+	"Figure": `---
+const { src, alt = '', caption, align, width, title } = Astro.props;
+---
+<figure class={align}>
+  <img src={src} alt={alt} width={width} title={title} loading="lazy" />
+  {caption && <figcaption set:html={caption} />}
+</figure>
+`,
+	"YouTube": `---
+const { id } = Astro.props;
+---
+<iframe width="560" height="315" src={` + "`https://www.youtube.com/embed/${id}`" + `}
+  title="YouTube video player" frameborder="0" allowfullscreen loading="lazy"></iframe>
+`,
+	"GoogleMaps": `---
+const { src, width, height } = Astro.props;
+---
+<iframe loading="lazy" src={` + "`https://www.google.com/maps/d/embed?mid=${src}`" + `} width={width} height={height}></iframe>
+`,
+	"Gist": `---
+const { user, id } = Astro.props;
+---
+<script is:inline src={` + "`https://gist.github.com/${user}/${id}.js`" + `}></script>
+`,
+	"Audio": `---
+const { src } = Astro.props;
+---
+<audio controls preload="metadata">
+  <source src={src} />
+  Your browser does not support the audio element.
+</audio>
+`,
+	"Video": `---
+const { src } = Astro.props;
+---
+<video controls preload="metadata">
+  <source src={src} />
+  Your browser does not support the video element.
+</video>
+`,
+	"Gallery": `---
+const { cols = '1' } = Astro.props;
+---
+<div class={` + "`gallery gallery-cols-${cols}`" + `}>
+  <slot />
+</div>
+`,
+	"ParallaxBlur": `---
+const { src } = Astro.props;
+---
+<div class="parallaxblur" style={` + "`background-image: url('${src}')`" + `}>
+  <slot />
+</div>
+`,
+	"Columns": `---
+---
+<div class="wp-columns" style="display: flex; flex-wrap: wrap; gap: 2em;">
+  <slot />
+</div>
+`,
+	"Column": `---
+const { width } = Astro.props;
+---
+<div class="wp-column" style={` + "`flex: ${width ? `0 1 ${width}` : '1 1 0'}; min-width: 12em;`" + `}>
+  <slot />
+</div>
+`,
+	"Buttons": `---
+---
+<div class="wp-buttons" style="display: flex; flex-wrap: wrap; gap: 1em;">
+  <slot />
+</div>
+`,
+	"Button": `---
+const { href, text, target } = Astro.props;
+---
+<a class="wp-button" href={href} target={target} rel={target ? 'noopener' : undefined}>{text}</a>
+`,
+	"Cover": `---
+const { src, dim = '50' } = Astro.props;
+---
+<div class="wp-cover" style="position: relative; overflow: hidden; padding: 4em 2em;">
+  {src && <img src={src} alt="" style="position: absolute; inset: 0; width: 100%; height: 100%; object-fit: cover;" />}
+  <div style={` + "`position: absolute; inset: 0; background: black; opacity: ${Number(dim) / 100};`" + `}></div>
+  <div style="position: relative; color: white;">
+    <slot />
+  </div>
+</div>
+`,
+	"Details": `---
+const { summary, open } = Astro.props;
+---
+<details open={open === 'true'}>
+  <summary>{summary}</summary>
+  <slot />
+</details>
+`,
+	"File": `---
+const { src, name } = Astro.props;
+---
+<a href={src} download>{name || src.split('/').pop()}</a>
+`,
+	"Embed": `---
+const { title, src, width, height } = Astro.props;
+---
+<iframe loading="lazy" title={title} src={src} width={width} height={height} style="border: 0;" allowfullscreen></iframe>
+`,
+	"EmbedPlaceholder": `---
+const { provider, src, url } = Astro.props;
+---
+<div class="embed-placeholder" style="padding: 2em; border: 1px solid #ccc; text-align: center;">
+  <p>This content is hosted by {provider}, loading it sends your data to {provider}.</p>
+  <button type="button" data-src={src}
+          onclick="var f = document.createElement('iframe'); f.src = this.dataset.src; f.width = '100%'; f.height = '400'; f.allowFullscreen = true; f.style.border = '0'; this.parentNode.replaceWith(f);">
+    Load content
+  </button>
+  <p><a href={url}>View on {provider}</a></p>
+</div>
+`,
+	"CatList": `---
+import { getCollection } from 'astro:content';
+
+const { category, catlink = 'yes', count = '5' } = Astro.props;
+const posts = (await getCollection('posts'))
+  .filter((post) => (post.data.categories ?? []).includes(category))
+  .slice(0, Number(count));
+---
+<h3>{catlink === 'yes' ? <a href={` + "`/category/${category}/`" + `}>{category}</a> : category}</h3>
+<ul>
+  {posts.map((post) => <li><a href={post.data.permalink ?? ` + "`/${post.id}/`" + `}>{post.data.title}</a></li>)}
+</ul>
+`,
+}
+
+// astroTarget writes content collections as MDX, with the embeds as components
+// Ref: https://docs.astro.build/en/guides/content-collections/
+type astroTarget struct{}
+
+func (astroTarget) Name() string {
+	return "Astro"
+}
+
+func (astroTarget) PagePath(page wpparser.CommonFields) (string, string) {
+	return path.Join("src", "content", getPostTypeDir(getPostType(page))), page.GetFileInfo().FileNameWithLanguage()
+}
+
+func (astroTarget) FileExtension() string {
+	return ".mdx"
+}
+
+func (astroTarget) MediaDir() string {
+	return "public"
+}
+
+func (astroTarget) DataDir() string {
+	return path.Join("src", "data")
+}
+
+func (astroTarget) FrontMatter() hugopage.FrontMatterMapper {
+	return astroFrontMatter{}
+}
+
+func (astroTarget) Render(metadata map[string]any, markdown string) ([]byte, error) {
+	frontMatter := withoutHugoKeys(metadata)
+	if url, ok := metadata["url"]; ok {
+		frontMatter["permalink"] = url
+	}
+	if isDraft(metadata) {
+		frontMatter["draft"] = true
+	}
+	renameKey(frontMatter, "date", "pubDate")
+	renameKey(frontMatter, "summary", "description")
+
+	var components []string
+	body := ReplaceShortcodes(markdown, func(shortcode Shortcode) string {
+		component, ok := renderAstroShortcode(shortcode)
+		if !ok {
+			log.Warn().
+				Str("shortcode", shortcode.Name).
+				Msg("No Astro component for Hugo shortcode, keeping it as text")
+			return shortcode.Original()
+		}
+		if !slices.Contains(components, component.name) {
+			components = append(components, component.name)
+		}
+		return component.String()
+	})
+	body = sanitizeMDX(body)
+	if len(components) > 0 {
+		slices.Sort(components)
+		body = fmt.Sprintf("import { %s } from '%s';\n\n%s", strings.Join(components, ", "), _astroComponentsImport, body)
+	}
+	return renderYAMLFrontMatter(frontMatter, body)
+}
+
+type mdxComponent struct {
+	name  string
+	attrs [][2]string
+	inner *string
+}
+
+func (c mdxComponent) String() string {
+	var output strings.Builder
+	output.WriteString("<" + c.name)
+	for _, attr := range c.attrs {
+		if attr[1] == "" {
+			continue
+		}
+		fmt.Fprintf(&output, ` %s="%s"`, attr[0], escapeJSXAttribute(attr[1]))
+	}
+	if c.inner == nil {
+		output.WriteString(" />")
+		return output.String()
+	}
+	// Blank lines, so that the inner content is parsed as Markdown
+	fmt.Fprintf(&output, ">\n\n%s\n\n</%s>", strings.TrimSpace(*c.inner), c.name)
+	return output.String()
+}
+
+// JSX string attributes decode HTML entities, but MDX would interpret the braces
+func escapeJSXAttribute(value string) string {
+	value = html.EscapeString(value)
+	value = strings.ReplaceAll(value, "{", "&#123;")
+	return strings.ReplaceAll(value, "}", "&#125;")
+}
+
+func renderAstroShortcode(shortcode Shortcode) (mdxComponent, bool) {
+	args := func(names ...string) [][2]string {
+		attrs := make([][2]string, 0, len(names))
+		for _, name := range names {
+			attrs = append(attrs, [2]string{name, shortcode.Args[name]})
+		}
+		return attrs
+	}
+	switch shortcode.Name {
+	case "figure":
+		return mdxComponent{name: "Figure", attrs: args("src", "alt", "caption", "align", "width", "title")}, true
+	case "youtube":
+		return mdxComponent{name: "YouTube", attrs: [][2]string{{"id", shortcode.Get("id", 0)}}}, true
+	case "googlemaps":
+		return mdxComponent{name: "GoogleMaps", attrs: args("src", "width", "height")}, true
+	case "gist":
+		return mdxComponent{name: "Gist", attrs: [][2]string{
+			{"user", shortcode.Get("user", 0)},
+			{"id", shortcode.Get("id", 1)},
+		}}, true
+	case "audio":
+		return mdxComponent{name: "Audio", attrs: args("src")}, true
+	case "video":
+		return mdxComponent{name: "Video", attrs: args("src")}, true
+	case "gallery":
+		return mdxComponent{name: "Gallery", attrs: args("cols"), inner: shortcode.Inner}, true
+	case "parallaxblur":
+		return mdxComponent{name: "ParallaxBlur", attrs: args("src"), inner: shortcode.Inner}, true
+	case "catlist":
+		return mdxComponent{name: "CatList", attrs: args("category", "catlink", "count")}, true
+	case "columns":
+		return mdxComponent{name: "Columns", inner: shortcode.Inner}, true
+	case "column":
+		return mdxComponent{name: "Column", attrs: args("width"), inner: shortcode.Inner}, true
+	case "buttons":
+		return mdxComponent{name: "Buttons", inner: shortcode.Inner}, true
+	case "button":
+		return mdxComponent{name: "Button", attrs: args("href", "text", "target")}, true
+	case "cover":
+		return mdxComponent{name: "Cover", attrs: args("src", "dim"), inner: shortcode.Inner}, true
+	case "details":
+		return mdxComponent{name: "Details", attrs: args("summary", "open"), inner: shortcode.Inner}, true
+	case "file":
+		return mdxComponent{name: "File", attrs: args("src", "name")}, true
+	case "embed-placeholder":
+		return mdxComponent{name: "EmbedPlaceholder", attrs: args("provider", "src", "url")}, true
+	default:
+		if player, ok := getEmbedPlayer(shortcode); ok {
+			return mdxComponent{name: "Embed", attrs: [][2]string{
+				{"title", player.title},
+				{"src", player.src},
+				{"width", player.width},
+				{"height", player.height},
+			}}, true
+		}
+		return mdxComponent{}, false
+	}
+}
+
+// sanitizeMDX escapes, outside of code, what is valid in Markdown but not in MDX, on a best-effort basis
+// Ref: https://mdxjs.com/docs/troubleshooting-mdx/#could-not-parse-expression-with-acorn
+func sanitizeMDX(markdown string) string {
+	markdown = mapOutsideOfCode(markdown, func(text string) string {
+		text = strings.ReplaceAll(text, "{", `\{`)
+		text = strings.ReplaceAll(text, "}", `\}`)
+		text = _loneLessThan.ReplaceAllString(text, "&lt;$1")
+		return _voidElement.ReplaceAllString(text, "<$1$2 />")
+	})
+	// Comments may span several lines
+	return _htmlComment.ReplaceAllStringFunc(markdown, func(comment string) string {
+		content := _htmlComment.FindStringSubmatch(comment)[1]
+		return "{/*" + strings.ReplaceAll(content, "*/", "* /") + "*/}"
+	})
+}
+
+// mapOutsideOfCode applies fn to the parts of markdown which are not code blocks or code spans
+func mapOutsideOfCode(markdown string, fn func(text string) string) string {
+	lines := strings.SplitAfter(markdown, "\n")
+	inCodeBlock := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		var output strings.Builder
+		last := 0
+		for _, loc := range _inlineCode.FindAllStringIndex(line, -1) {
+			output.WriteString(fn(line[last:loc[0]]))
+			output.WriteString(line[loc[0]:loc[1]])
+			last = loc[1]
+		}
+		output.WriteString(fn(line[last:]))
+		lines[i] = output.String()
+	}
+	return strings.Join(lines, "")
+}
+
+func (astroTarget) Finish(siteDir string, _ wpparser.WebsiteInfo, pages []Page) error {
+	var collections []string
+	for _, page := range pages {
+		collection := path.Base(path.Dir(page.Path))
+		if !slices.Contains(collections, collection) {
+			collections = append(collections, collection)
+		}
+	}
+	slices.Sort(collections)
+
+	var config strings.Builder
+	config.WriteString(`// Generated by wp2hugo
+import { defineCollection, z } from 'astro:content';
+import { glob } from 'astro/loaders';
+
+const schema = z.object({
+  title: z.string(),
+  pubDate: z.coerce.date().optional(),
+  description: z.string().optional(),
+  draft: z.boolean().optional(),
+  heroImage: z.string().optional(),
+  categories: z.array(z.string()).optional(),
+  tags: z.array(z.string()).optional(),
+  permalink: z.string().optional(),
+}).passthrough();
+
+export const collections = {
+`)
+	for _, collection := range collections {
+		fmt.Fprintf(&config, "  '%s': defineCollection({ loader: glob({ pattern: '**/*.mdx', base: './src/content/%s' }), schema }),\n",
+			collection, collection)
+	}
+	config.WriteString("};\n")
+
+	files := map[string]string{
+		path.Join("src", "content.config.ts"): config.String(),
+	}
+	var index strings.Builder
+	componentNames := make([]string, 0, len(_astroComponents))
+	for name := range _astroComponents {
+		componentNames = append(componentNames, name)
+	}
+	slices.Sort(componentNames)
+	for _, name := range componentNames {
+		files[path.Join("src", "components", "wp2hugo", name+".astro")] = _astroComponents[name]
+		fmt.Fprintf(&index, "export { default as %s } from './%s.astro';\n", name, name)
+	}
+	files[path.Join("src", "components", "wp2hugo", "index.ts")] = index.String()
+	return writeSupportFiles(siteDir, files)
+}
+
+// astroFrontMatter uses the keys of the blog template of Astro
+// Ref: https://github.com/withastro/astro/tree/main/examples/blog
+type astroFrontMatter struct{}
+
+func (astroFrontMatter) SetCoverImage(metadata map[string]any, imageURL string, _ string) {
+	metadata["heroImage"] = imageURL
+}
+
+func (astroFrontMatter) SetTableOfContents(metadata map[string]any) {
+	metadata["toc"] = true
+}
+
+// The robots key of the front matter is left to the layouts
+func (astroFrontMatter) SetNoIndex(map[string]any) {}
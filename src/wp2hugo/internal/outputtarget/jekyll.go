@@ -0,0 +1,236 @@
+package outputtarget
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+// Ref: https://jekyllrb.com/docs/includes/
+var _jekyllIncludes = map[string]string{
+	"_includes/figure.html": `<figure{% if include.align %} class="{{ include.align }}"{% endif %}>
+  <img src="{{ include.src | relative_url }}" alt="{{ include.alt }}"{% if include.width %} width="{{ include.width }}"{% endif %}{% if include.title %} title="{{ include.title }}"{% endif %}>
+  {% if include.caption and include.caption != "" %}<figcaption>{{ include.caption }}</figcaption>{% endif %}
+</figure>
+`,
+	"_includes/youtube.html": `<iframe width="560" height="315" src="https://www.youtube.com/embed/{{ include.id }}"
+        title="YouTube video player" frameborder="0" allowfullscreen loading="lazy"></iframe>
+`,
+	"_includes/googlemaps.html": `<iframe loading="lazy"
+        src="https://www.google.com/maps/d/embed?mid={{ include.src }}"
+        width="{{ include.width }}"
+        height="{{ include.height }}">
+</iframe>
+`,
+	"_includes/audio.html": `<audio controls preload="metadata">
+  <source src="{{ include.src | relative_url }}">
+  Your browser does not support the audio element.
+</audio>
+`,
+	"_includes/video.html": `<video controls preload="metadata">
+  <source src="{{ include.src | relative_url }}">
+  Your browser does not support the video element.
+</video>
+`,
+	"_includes/catlist.html": `{% assign count = include.count | default: 5 | plus: 0 %}
+<h3>
+  {% if include.catlink == "yes" %}
+    <a href="{{ '/category/' | append: include.category | relative_url }}">{{ include.category }}</a>
+  {% else %}
+    {{ include.category }}
+  {% endif %}
+</h3>
+<ul>
+  {% for post in site.categories[include.category] limit: count %}
+    <li><a href="{{ post.url | relative_url }}">{{ post.title }}</a></li>
+  {% endfor %}
+</ul>
+`,
+}
+
+// jekyllTarget writes posts into _posts/YYYY-MM-DD-slug.md and embeds as Liquid includes
+// Ref: https://jekyllrb.com/docs/posts/
+type jekyllTarget struct{}
+
+func (jekyllTarget) Name() string {
+	return "Jekyll"
+}
+
+func (jekyllTarget) PagePath(page wpparser.CommonFields) (string, string) {
+	fileName := page.GetFileInfo().FileNameWithLanguage()
+	switch postType := getPostType(page); postType {
+	case "post":
+		if page.PublishDate == nil {
+			return "_drafts", fileName
+		}
+		return "_posts", page.PublishDate.Format("2006-01-02") + "-" + fileName
+	case "page":
+		return "pages", fileName
+	default:
+		// Custom post types are collections
+		// Ref: https://jekyllrb.com/docs/collections/
+		return "_" + getPostTypeDir(postType), fileName
+	}
+}
+
+func (jekyllTarget) FileExtension() string {
+	return ".md"
+}
+
+func (jekyllTarget) MediaDir() string {
+	// Jekyll copies all the files which are not part of the site structure as is
+	return ""
+}
+
+func (jekyllTarget) DataDir() string {
+	return "_data"
+}
+
+func (jekyllTarget) FrontMatter() hugopage.FrontMatterMapper {
+	return jekyllFrontMatter{}
+}
+
+// Ref: https://jekyllrb.com/docs/front-matter/
+func (jekyllTarget) Render(metadata map[string]any, markdown string) ([]byte, error) {
+	frontMatter := withoutHugoKeys(metadata)
+	if url, ok := metadata["url"]; ok {
+		frontMatter["permalink"] = url
+	}
+	if isDraft(metadata) {
+		frontMatter["published"] = false
+	}
+	renameKey(frontMatter, "summary", "excerpt")
+	// Ref: https://jekyllrb.com/docs/posts/#post-excerpts
+	if strings.Contains(markdown, _moreTag) {
+		frontMatter["excerpt_separator"] = _moreTag
+	}
+	return renderYAMLFrontMatter(frontMatter, ReplaceShortcodes(markdown, renderJekyllShortcode))
+}
+
+func renderJekyllShortcode(shortcode Shortcode) string {
+	switch shortcode.Name {
+	case "figure":
+		return liquidInclude("figure", shortcode, "src", "alt", "caption", "align", "width", "title")
+	case "youtube":
+		return fmt.Sprintf(`{%% include youtube.html id="%s" %%}`, escapeLiquid(shortcode.Get("id", 0)))
+	case "googlemaps":
+		return liquidInclude("googlemaps", shortcode, "src", "width", "height")
+	case "audio", "video":
+		return liquidInclude(shortcode.Name, shortcode, "src")
+	case "catlist":
+		return liquidInclude("catlist", shortcode, "category", "catlink", "count")
+	case "gist":
+		// Ref: https://github.com/jekyll/jekyll-gist
+		return fmt.Sprintf("{%% gist %s/%s %%}", shortcode.Get("user", 0), shortcode.Get("id", 1))
+	case "gallery":
+		return fmt.Sprintf(`<div class="gallery gallery-cols-%s" markdown="1">%s</div>`,
+			shortcode.Get("cols", 0), innerOf(shortcode))
+	case "parallaxblur":
+		return fmt.Sprintf(`<div class="parallaxblur" style="background-image: url('%s')" markdown="1">%s</div>`,
+			shortcode.Get("src", 0), innerOf(shortcode))
+	default:
+		if layout, ok := renderLayoutShortcode(shortcode); ok {
+			return layout
+		}
+		if embed, ok := renderEmbedShortcode(shortcode); ok {
+			return embed
+		}
+		log.Warn().
+			Str("shortcode", shortcode.Name).
+			Msg("No Jekyll equivalent for Hugo shortcode, keeping it as is")
+		return shortcode.Original()
+	}
+}
+
+// liquidInclude returns the include of _includes/<name>.html with the non-empty arguments of shortcode
+func liquidInclude(name string, shortcode Shortcode, argNames ...string) string {
+	var output strings.Builder
+	fmt.Fprintf(&output, "{%% include %s.html", name)
+	for _, argName := range argNames {
+		if value := shortcode.Args[argName]; value != "" {
+			fmt.Fprintf(&output, ` %s="%s"`, argName, escapeLiquid(value))
+		}
+	}
+	output.WriteString(" %}")
+	return output.String()
+}
+
+func escapeLiquid(value string) string {
+	return strings.ReplaceAll(value, `"`, "&quot;")
+}
+
+func innerOf(shortcode Shortcode) string {
+	if shortcode.Inner == nil {
+		return ""
+	}
+	return *shortcode.Inner
+}
+
+func (jekyllTarget) Finish(siteDir string, info wpparser.WebsiteInfo, pages []Page) error {
+	// Ref: https://jekyllrb.com/docs/configuration/front-matter-defaults/
+	defaults := []map[string]any{
+		{"scope": map[string]string{"path": "", "type": "posts"}, "values": map[string]string{"layout": "post"}},
+		{"scope": map[string]string{"path": "pages"}, "values": map[string]string{"layout": "page"}},
+	}
+	collections := make(map[string]any)
+	for _, page := range pages {
+		dir := strings.Split(page.Path, "/")[0]
+		if dir == "_posts" || dir == "_drafts" || !strings.HasPrefix(dir, "_") {
+			continue
+		}
+		collection := strings.TrimPrefix(dir, "_")
+		collections[collection] = map[string]bool{"output": true}
+	}
+	collectionNames := make([]string, 0, len(collections))
+	for name := range collections {
+		collectionNames = append(collectionNames, name)
+	}
+	slices.Sort(collectionNames)
+	for _, name := range collectionNames {
+		defaults = append(defaults, map[string]any{
+			"scope":  map[string]string{"path": "", "type": name},
+			"values": map[string]string{"layout": "page"},
+		})
+	}
+
+	config := map[string]any{
+		"title":    info.Title(),
+		"url":      info.Link().Scheme + "://" + info.Link().Host,
+		"lang":     info.Language(),
+		"markdown": "kramdown",
+		"plugins":  []string{"jekyll-gist"},
+		"defaults": defaults,
+	}
+	if len(collections) > 0 {
+		config["collections"] = collections
+	}
+	if err := writeYAMLFile(path.Join(siteDir, "_config.yml"), config); err != nil {
+		return err
+	}
+	return writeSupportFiles(siteDir, _jekyllIncludes)
+}
+
+// jekyllFrontMatter uses the keys of jekyll-seo-tag, supported by most themes
+// Ref: https://github.com/jekyll/jekyll-seo-tag/blob/master/docs/advanced-usage.md
+type jekyllFrontMatter struct{}
+
+func (jekyllFrontMatter) SetCoverImage(metadata map[string]any, imageURL string, alt string) {
+	metadata["image"] = map[string]string{
+		"path": imageURL,
+		"alt":  alt,
+	}
+}
+
+func (jekyllFrontMatter) SetTableOfContents(metadata map[string]any) {
+	metadata["toc"] = true
+}
+
+// Ref: https://github.com/jekyll/jekyll-sitemap#exclusions
+func (jekyllFrontMatter) SetNoIndex(metadata map[string]any) {
+	metadata["sitemap"] = false
+}
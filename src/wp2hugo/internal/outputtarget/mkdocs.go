@@ -0,0 +1,221 @@
+package outputtarget
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/rs/zerolog/log"
+)
+
+const _mkDocsCSSPath = "stylesheets/wp2hugo.css"
+
+const _mkDocsCSS = `.gallery {
+  display: grid;
+  grid-template-columns: repeat(var(--gallery-cols, 1), 1fr);
+  gap: 0.5rem;
+}
+
+.parallaxblur {
+  background-attachment: fixed;
+  background-size: cover;
+  background-position: center;
+  padding: 2rem;
+  text-align: center;
+}
+`
+
+// mkDocsTarget writes the pages for MkDocs with the Material theme, embeds as HTML and admonitions
+// Ref: https://squidfunk.github.io/mkdocs-material/reference/
+type mkDocsTarget struct{}
+
+func (mkDocsTarget) Name() string {
+	return "MkDocs"
+}
+
+func (mkDocsTarget) PagePath(page wpparser.CommonFields) (string, string) {
+	return path.Join("docs", getPostTypeDir(getPostType(page))), page.GetFileInfo().FileNameWithLanguage()
+}
+
+func (mkDocsTarget) FileExtension() string {
+	return ".md"
+}
+
+func (mkDocsTarget) MediaDir() string {
+	return "docs"
+}
+
+func (mkDocsTarget) DataDir() string {
+	// Not part of the website, MkDocs has no data files
+	return "data"
+}
+
+func (mkDocsTarget) FrontMatter() hugopage.FrontMatterMapper {
+	return mkDocsFrontMatter{}
+}
+
+// Ref: https://squidfunk.github.io/mkdocs-material/plugins/blog/#metadata
+func (mkDocsTarget) Render(metadata map[string]any, markdown string) ([]byte, error) {
+	frontMatter := withoutHugoKeys(metadata)
+	if isDraft(metadata) {
+		frontMatter["draft"] = true
+	}
+	// The names of the authors replace their slugs in the Hugo taxonomy
+	if author, ok := frontMatter["author"]; ok {
+		delete(frontMatter, "author")
+		if names, ok := author.([]string); ok {
+			frontMatter["authors"] = names
+		} else {
+			frontMatter["authors"] = []any{author}
+		}
+	}
+	renameKey(frontMatter, "summary", "description")
+	// Ref: https://squidfunk.github.io/mkdocs-material/plugins/blog/#config.post_excerpt_separator
+	markdown = strings.Replace(markdown, _moreTag, "<!-- more -->", 1)
+	return renderYAMLFrontMatter(frontMatter, ReplaceShortcodes(markdown, renderMkDocsShortcode))
+}
+
+func renderMkDocsShortcode(shortcode Shortcode) string {
+	attr := func(name string) string {
+		return html.EscapeString(shortcode.Get(name, 0))
+	}
+	switch shortcode.Name {
+	case "figure":
+		// Ref: https://squidfunk.github.io/mkdocs-material/reference/images/#image-captions
+		var output strings.Builder
+		output.WriteString("<figure markdown=\"span\">\n")
+		fmt.Fprintf(&output, "  ![%s](%s)", shortcode.Args["alt"], shortcode.Args["src"])
+		if width := shortcode.Args["width"]; width != "" {
+			fmt.Fprintf(&output, `{ width="%s" }`, html.EscapeString(width))
+		}
+		output.WriteString("\n")
+		if caption := shortcode.Args["caption"]; caption != "" {
+			fmt.Fprintf(&output, "  <figcaption>%s</figcaption>\n", caption)
+		}
+		output.WriteString("</figure>")
+		return output.String()
+	case "youtube":
+		return fmt.Sprintf(`<iframe width="560" height="315" src="https://www.youtube.com/embed/%s" `+
+			`title="YouTube video player" frameborder="0" allowfullscreen loading="lazy"></iframe>`, html.EscapeString(shortcode.Get("id", 0)))
+	case "googlemaps":
+		return fmt.Sprintf(`<iframe loading="lazy" src="https://www.google.com/maps/d/embed?mid=%s" width="%s" height="%s"></iframe>`,
+			attr("src"), attr("width"), attr("height"))
+	case "gist":
+		return fmt.Sprintf(`<script src="https://gist.github.com/%s/%s.js"></script>`,
+			html.EscapeString(shortcode.Get("user", 0)), html.EscapeString(shortcode.Get("id", 1)))
+	case "audio", "video":
+		return fmt.Sprintf(`<%s controls preload="metadata" src="%s"></%s>`, shortcode.Name, attr("src"), shortcode.Name)
+	case "gallery":
+		return fmt.Sprintf("<div class=\"gallery\" style=\"--gallery-cols: %s\" markdown>\n%s\n</div>",
+			attr("cols"), strings.TrimSpace(innerOf(shortcode)))
+	case "parallaxblur":
+		return fmt.Sprintf("<div class=\"parallaxblur\" style=\"background-image: url('%s')\" markdown>\n%s\n</div>",
+			attr("src"), strings.TrimSpace(innerOf(shortcode)))
+	case "catlist":
+		// MkDocs cannot list the pages of a category, link to it instead
+		// Ref: https://squidfunk.github.io/mkdocs-material/reference/admonitions/
+		category := shortcode.Args["category"]
+		return fmt.Sprintf("!!! note \"%s\"\n\n    [Posts in %s](/category/%s/)", category, category, category)
+	default:
+		if layout, ok := renderLayoutShortcode(shortcode); ok {
+			return layout
+		}
+		if embed, ok := renderEmbedShortcode(shortcode); ok {
+			return embed
+		}
+		log.Warn().
+			Str("shortcode", shortcode.Name).
+			Msg("No MkDocs equivalent for Hugo shortcode, keeping it as is")
+		return shortcode.Original()
+	}
+}
+
+func (mkDocsTarget) Finish(siteDir string, info wpparser.WebsiteInfo, pages []Page) error {
+	// Posts are listed from the newest
+	pages = slices.Clone(pages)
+	slices.SortStableFunc(pages, func(a, b Page) int {
+		switch {
+		case a.PublishDate == nil && b.PublishDate == nil:
+			return strings.Compare(a.Path, b.Path)
+		case a.PublishDate == nil:
+			return 1
+		case b.PublishDate == nil:
+			return -1
+		default:
+			return b.PublishDate.Compare(*a.PublishDate)
+		}
+	})
+
+	var sections []string
+	navBySection := make(map[string][]any)
+	for _, page := range pages {
+		section := getPostTypeDir(page.PostType)
+		if !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+		navBySection[section] = append(navBySection[section], map[string]string{
+			page.Title: strings.TrimPrefix(page.Path, "docs/"),
+		})
+	}
+	// Pages first, then posts and the custom post types
+	slices.SortFunc(sections, func(a, b string) int {
+		switch {
+		case a == "pages":
+			return -1
+		case b == "pages":
+			return 1
+		case a == "posts":
+			return -1
+		case b == "posts":
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
+	nav := make([]any, 0, len(sections))
+	for _, section := range sections {
+		nav = append(nav, map[string][]any{
+			strings.ToUpper(section[:1]) + section[1:]: navBySection[section],
+		})
+	}
+
+	config := map[string]any{
+		"site_name": info.Title(),
+		"site_url":  info.Link().Scheme + "://" + info.Link().Host + "/",
+		"theme": map[string]string{
+			"name":     "material",
+			"language": info.Language(),
+		},
+		"nav": nav,
+		"markdown_extensions": []any{
+			"admonition", "attr_list", "md_in_html", "footnotes",
+			map[string]any{"toc": map[string]bool{"permalink": true}},
+		},
+		"extra_css": []string{_mkDocsCSSPath},
+	}
+	if err := writeYAMLFile(path.Join(siteDir, "mkdocs.yml"), config); err != nil {
+		return err
+	}
+	return writeSupportFiles(siteDir, map[string]string{
+		path.Join("docs", _mkDocsCSSPath): _mkDocsCSS,
+	})
+}
+
+// mkDocsFrontMatter uses the keys of the Material theme
+type mkDocsFrontMatter struct{}
+
+// Used by the social cards of Material
+// Ref: https://squidfunk.github.io/mkdocs-material/plugins/social/
+func (mkDocsFrontMatter) SetCoverImage(metadata map[string]any, imageURL string, _ string) {
+	metadata["image"] = imageURL
+}
+
+// Material shows the table of contents by default
+func (mkDocsFrontMatter) SetTableOfContents(map[string]any) {}
+
+// The robots key of the front matter is left to the theme overrides
+func (mkDocsFrontMatter) SetNoIndex(map[string]any) {}
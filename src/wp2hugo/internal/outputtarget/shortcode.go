@@ -0,0 +1,208 @@
+package outputtarget
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// {{< name arg1 key="value" key2=value >}}, names may have dashes, e.g. embed-placeholder
+var (
+	_shortcodeOpening = regexp.MustCompile(`{{<\s*([a-zA-Z_][a-zA-Z0-9_-]*)((?:\s+(?:[a-zA-Z_]+=)?(?:"[^"]*"|[^\s"}>]+))*)\s*>}}`)
+	_shortcodeArg     = regexp.MustCompile(`([a-zA-Z_]+)=(?:"([^"]*)"|(\S+))|"([^"]*)"|(\S+)`)
+)
+
+// Shortcode is a Hugo shortcode of the converted Markdown
+type Shortcode struct {
+	Name       string
+	Args       map[string]string
+	Positional []string
+	// Content between the opening and closing shortcodes, already converted, nil if not paired
+	Inner *string
+	// The shortcode as written in the Markdown, without its inner content
+	Raw string
+}
+
+// Get returns the named argument, or the positional one if there is no argument with that name
+func (s Shortcode) Get(name string, position int) string {
+	if value, ok := s.Args[name]; ok {
+		return value
+	}
+	if position < len(s.Positional) {
+		return s.Positional[position]
+	}
+	return ""
+}
+
+// Original returns the shortcode as written in the Markdown, around its converted inner content
+func (s Shortcode) Original() string {
+	if s.Inner == nil {
+		return s.Raw
+	}
+	return s.Raw + *s.Inner + "{{< /" + s.Name + " >}}"
+}
+
+// ReplaceShortcodes replaces all the Hugo shortcodes of markdown with the output of render,
+// inner content of paired shortcodes is replaced first
+func ReplaceShortcodes(markdown string, render func(shortcode Shortcode) string) string {
+	var output strings.Builder
+	for {
+		loc := _shortcodeOpening.FindStringSubmatchIndex(markdown)
+		if loc == nil {
+			output.WriteString(markdown)
+			return output.String()
+		}
+		output.WriteString(markdown[:loc[0]])
+
+		shortcode := parseShortcode(markdown[loc[0]:loc[1]], markdown[loc[2]:loc[3]], markdown[loc[4]:loc[5]])
+		rest := markdown[loc[1]:]
+		closing := regexp.MustCompile(`{{<\s*/` + regexp.QuoteMeta(shortcode.Name) + `\s*>}}`)
+		if closingLoc := closing.FindStringIndex(rest); closingLoc != nil {
+			inner := ReplaceShortcodes(rest[:closingLoc[0]], render)
+			shortcode.Inner = &inner
+			rest = rest[closingLoc[1]:]
+		}
+		output.WriteString(render(shortcode))
+		markdown = rest
+	}
+}
+
+func parseShortcode(raw string, name string, args string) Shortcode {
+	shortcode := Shortcode{
+		Name: name,
+		Args: make(map[string]string),
+		Raw:  raw,
+	}
+	for _, match := range _shortcodeArg.FindAllStringSubmatch(args, -1) {
+		switch {
+		case match[1] != "":
+			shortcode.Args[match[1]] = match[2] + match[3]
+		default:
+			shortcode.Positional = append(shortcode.Positional, match[4]+match[5])
+		}
+	}
+	return shortcode
+}
+
+// renderLayoutShortcode returns the HTML of the layout shortcodes of the Gutenberg blocks, like the
+// templates hugogenerator writes for Hugo. markdown="1" has kramdown (Jekyll) and md_in_html (MkDocs)
+// parse the inner content as Markdown
+func renderLayoutShortcode(shortcode Shortcode) (string, bool) {
+	attr := func(name string) string {
+		return html.EscapeString(shortcode.Args[name])
+	}
+	inner := strings.TrimSpace(innerOf(shortcode))
+	switch shortcode.Name {
+	case "columns":
+		return fmt.Sprintf("<div class=\"wp-columns\" style=\"display: flex; flex-wrap: wrap; gap: 2em;\" markdown=\"1\">\n%s\n</div>",
+			inner), true
+	case "column":
+		flex := "1 1 0"
+		if width := attr("width"); width != "" {
+			flex = "0 1 " + width
+		}
+		return fmt.Sprintf("<div class=\"wp-column\" style=\"flex: %s; min-width: 12em;\" markdown=\"1\">\n\n%s\n\n</div>",
+			flex, inner), true
+	case "buttons":
+		return fmt.Sprintf("<div class=\"wp-buttons\" style=\"display: flex; flex-wrap: wrap; gap: 1em;\" markdown=\"1\">\n%s\n</div>",
+			inner), true
+	case "button":
+		target := ""
+		if value := attr("target"); value != "" {
+			target = fmt.Sprintf(` target="%s" rel="noopener"`, value)
+		}
+		return fmt.Sprintf(`<a class="wp-button" href="%s"%s>%s</a>`, attr("href"), target, attr("text")), true
+	case "cover":
+		var output strings.Builder
+		output.WriteString(`<div class="wp-cover" style="position: relative; overflow: hidden; padding: 4em 2em;">` + "\n")
+		if src := attr("src"); src != "" {
+			fmt.Fprintf(&output, `<img src="%s" alt="" style="position: absolute; inset: 0; width: 100%%; height: 100%%; object-fit: cover;">`+"\n", src)
+		}
+		dim, err := strconv.Atoi(shortcode.Args["dim"])
+		if err != nil {
+			dim = 50
+		}
+		fmt.Fprintf(&output, `<div style="position: absolute; inset: 0; background: black; opacity: %.2f;"></div>`+"\n", float64(dim)/100)
+		fmt.Fprintf(&output, "<div style=\"position: relative; color: white;\" markdown=\"1\">\n\n%s\n\n</div>\n</div>", inner)
+		return output.String(), true
+	case "details":
+		open := ""
+		if shortcode.Args["open"] == "true" {
+			open = " open"
+		}
+		return fmt.Sprintf("<details%s markdown=\"1\">\n<summary>%s</summary>\n\n%s\n\n</details>", open, attr("summary"), inner), true
+	case "file":
+		name := attr("name")
+		if name == "" {
+			name = html.EscapeString(path.Base(shortcode.Args["src"]))
+		}
+		return fmt.Sprintf(`<a href="%s" download>%s</a>`, attr("src"), name), true
+	default:
+		return "", false
+	}
+}
+
+// embedPlayer is the iframe of an embed shortcode Hugo has, or hugogenerator writes, see hugopage
+type embedPlayer struct {
+	title  string
+	src    string
+	width  string
+	height string
+}
+
+// getEmbedPlayer returns the player of the embed shortcodes other than youtube, which all targets
+// have an equivalent of
+func getEmbedPlayer(shortcode Shortcode) (embedPlayer, bool) {
+	switch shortcode.Name {
+	case "vimeo":
+		return embedPlayer{"Vimeo video player", "https://player.vimeo.com/video/" + url.PathEscape(shortcode.Get("id", 0)),
+			"640", "360"}, true
+	case "x":
+		return embedPlayer{"X post", "https://platform.twitter.com/embed/Tweet.html?id=" + url.QueryEscape(shortcode.Get("id", 1)),
+			"550", "500"}, true
+	case "instagram":
+		return embedPlayer{"Instagram post", "https://www.instagram.com/p/" + url.PathEscape(shortcode.Get("id", 0)) + "/embed",
+			"400", "540"}, true
+	case "soundcloud":
+		return embedPlayer{"SoundCloud player", "https://w.soundcloud.com/player/?url=" + url.QueryEscape(shortcode.Get("url", 0)),
+			"100%", "166"}, true
+	case "spotify":
+		height := "352"
+		if kind := shortcode.Get("type", 0); kind == "track" || kind == "episode" {
+			height = "152"
+		}
+		return embedPlayer{"Spotify player", "https://open.spotify.com/embed/" + url.PathEscape(shortcode.Get("type", 0)) + "/" +
+			url.PathEscape(shortcode.Get("id", 1)), "100%", height}, true
+	case "tiktok":
+		return embedPlayer{"TikTok video", "https://www.tiktok.com/embed/v2/" + url.PathEscape(shortcode.Get("id", 0)),
+			"325", "740"}, true
+	case "codepen":
+		return embedPlayer{"CodePen", "https://codepen.io/" + url.PathEscape(shortcode.Get("user", 0)) + "/embed/" +
+			url.PathEscape(shortcode.Get("id", 1)) + "?default-tab=result", "100%", "400"}, true
+	default:
+		return embedPlayer{}, false
+	}
+}
+
+// renderEmbedShortcode returns the HTML of the embed shortcodes, and of the placeholders of the privacy mode,
+// like the templates hugogenerator writes for Hugo
+func renderEmbedShortcode(shortcode Shortcode) (string, bool) {
+	if shortcode.Name == "embed-placeholder" {
+		provider := html.EscapeString(shortcode.Args["provider"])
+		return fmt.Sprintf(`<div class="embed-placeholder" style="padding: 2em; border: 1px solid #ccc; text-align: center;">
+<p>This content is hosted by %[1]s, loading it sends your data to %[1]s.</p>
+<button type="button" data-src="%[2]s" onclick="var f = document.createElement('iframe'); f.src = this.dataset.src; f.width = '100%%'; f.height = '400'; f.allowFullscreen = true; f.style.border = '0'; this.parentNode.replaceWith(f);">Load content</button>
+<p><a href="%[3]s">View on %[1]s</a></p>
+</div>`, provider, html.EscapeString(shortcode.Args["src"]), html.EscapeString(shortcode.Args["url"])), true
+	}
+	player, ok := getEmbedPlayer(shortcode)
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf(`<iframe loading="lazy" title="%s" src="%s" width="%s" height="%s" style="border: 0;" allowfullscreen></iframe>`,
+		player.title, html.EscapeString(player.src), player.width, player.height), true
+}
@@ -0,0 +1,47 @@
+package outputtarget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceShortcodes(t *testing.T) {
+	t.Parallel()
+	const markdown = `Intro {{< youtube id="abc" >}} and {{< gist user 123 >}}
+
+{{< gallery cols="2" >}}{{< figure src="/a.jpg" alt="A" width=905 >}}{{< /gallery >}}`
+
+	var shortcodes []Shortcode
+	output := ReplaceShortcodes(markdown, func(shortcode Shortcode) string {
+		shortcodes = append(shortcodes, shortcode)
+		return "[" + shortcode.Name + "]"
+	})
+
+	require.Equal(t, "Intro [youtube] and [gist]\n\n[gallery]", output)
+	require.Len(t, shortcodes, 4)
+	require.Equal(t, "abc", shortcodes[0].Get("id", 0))
+	require.Equal(t, []string{"user", "123"}, shortcodes[1].Positional)
+	require.Equal(t, "905", shortcodes[2].Args["width"])
+	require.Equal(t, "gallery", shortcodes[3].Name)
+	require.Equal(t, "[figure]", *shortcodes[3].Inner)
+}
+
+func TestShortcodeOriginal(t *testing.T) {
+	t.Parallel()
+	const markdown = `{{< details summary="more" >}}Hidden{{< /details >}}`
+	output := ReplaceShortcodes(markdown, Shortcode.Original)
+	require.Equal(t, `{{< details summary="more" >}}Hidden{{< /details >}}`, output)
+}
+
+func TestReplaceShortcodes_DashedName(t *testing.T) {
+	t.Parallel()
+	const markdown = `{{< embed-placeholder provider="Vimeo" src="https://player.vimeo.com/video/1?dnt=1" url="https://vimeo.com/1" >}}`
+	var shortcodes []Shortcode
+	output := ReplaceShortcodes(markdown, func(shortcode Shortcode) string {
+		shortcodes = append(shortcodes, shortcode)
+		return "[" + shortcode.Name + "]"
+	})
+	require.Equal(t, "[embed-placeholder]", output)
+	require.Equal(t, "Vimeo", shortcodes[0].Args["provider"])
+}
@@ -0,0 +1,166 @@
+// Package outputtarget renders the content converted for Hugo for other static site generators
+package outputtarget
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/hugogenerator/hugopage"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/utils"
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+)
+
+const (
+	// TargetHugo is handled by hugogenerator itself, GetTarget returns no Target for it
+	TargetHugo   = "hugo"
+	TargetJekyll = "jekyll"
+	TargetAstro  = "astro"
+	TargetMkDocs = "mkdocs"
+)
+
+// Summary divider of the Markdown written by hugopage
+const _moreTag = "<!--more-->"
+
+// Target is a static site generator, other than Hugo, the converted content is written for
+type Target interface {
+	Name() string
+	// PagePath returns the directory, relative to the site directory, and the file name,
+	// without extension, of page
+	PagePath(page wpparser.CommonFields) (dir string, fileName string)
+	// FileExtension is the extension of the content files, including the dot
+	FileExtension() string
+	// MediaDir is the directory, relative to the site directory, served at the root of the website
+	MediaDir() string
+	// DataDir is the directory, relative to the site directory, of the data files like comments.yaml
+	DataDir() string
+	// FrontMatter maps the target-specific front matter keys of the pages, like the cover image
+	FrontMatter() hugopage.FrontMatterMapper
+	// Render converts the front matter and the Markdown generated for Hugo into a content file
+	Render(metadata map[string]any, markdown string) ([]byte, error)
+	// Finish writes the configuration of the site and the files the rendered pages depend on
+	Finish(siteDir string, info wpparser.WebsiteInfo, pages []Page) error
+}
+
+// Page is a content file written for a Target
+type Page struct {
+	// Relative to the site directory
+	Path        string
+	Title       string
+	PostType    string
+	PublishDate *time.Time
+}
+
+var _targets = map[string]Target{
+	TargetJekyll: jekyllTarget{},
+	TargetAstro:  astroTarget{},
+	TargetMkDocs: mkDocsTarget{},
+}
+
+// GetTarget returns the target named name, nil for TargetHugo, see Names
+func GetTarget(name string) (Target, error) {
+	if name == TargetHugo {
+		return nil, nil
+	}
+	target, ok := _targets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output target %q (allowed: %v)", name, Names())
+	}
+	return target, nil
+}
+
+func Names() []string {
+	names := []string{TargetHugo}
+	for name := range _targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getPostType(page wpparser.CommonFields) string {
+	if page.PostType == nil {
+		return "post"
+	}
+	return *page.PostType
+}
+
+// Plural form of the post type, as used for the content directories
+func getPostTypeDir(postType string) string {
+	if strings.HasSuffix(postType, "s") {
+		return postType
+	}
+	return postType + "s"
+}
+
+func isDraft(metadata map[string]any) bool {
+	draft, ok := metadata["draft"]
+	return ok && fmt.Sprint(draft) == "true"
+}
+
+// renameKey moves the value of oldKey to newKey, if any, unless newKey is already set
+func renameKey(metadata map[string]any, oldKey string, newKey string) {
+	if value, ok := metadata[oldKey]; ok {
+		delete(metadata, oldKey)
+		// E.g. the description written along with the summary
+		if _, exists := metadata[newKey]; !exists {
+			metadata[newKey] = value
+		}
+	}
+}
+
+// withoutHugoKeys returns a copy of metadata without the keys only Hugo makes sense of
+func withoutHugoKeys(metadata map[string]any) map[string]any {
+	result := make(map[string]any, len(metadata))
+	for key, value := range metadata {
+		switch key {
+		case "url", "draft":
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func renderYAMLFrontMatter(metadata map[string]any, markdown string) ([]byte, error) {
+	frontMatter, err := utils.GetYAML(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling metadata: %w", err)
+	}
+	content := fmt.Sprintf("---\n%s---\n%s", frontMatter, markdown)
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return []byte(content), nil
+}
+
+// writeSupportFiles writes files, by path relative to siteDir, unless they already exist
+func writeSupportFiles(siteDir string, files map[string]string) error {
+	for filePath, content := range files {
+		fullPath := path.Join(siteDir, filePath)
+		if utils.FileExists(fullPath) {
+			continue
+		}
+		if err := utils.CreateDirIfNotExist(path.Dir(fullPath)); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("error writing %s: %w", fullPath, err)
+		}
+	}
+	return nil
+}
+
+func writeYAMLFile(filePath string, data any) error {
+	content, err := utils.GetYAML(data)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, content, 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", filePath, err)
+	}
+	return nil
+}
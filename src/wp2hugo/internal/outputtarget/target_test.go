@@ -0,0 +1,240 @@
+package outputtarget
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/ashishb/wp2hugo/src/wp2hugo/internal/wpparser"
+	"github.com/stretchr/testify/require"
+)
+
+const _testMarkdown = `Intro
+
+<!--more-->
+
+Some {text} with a < b
+
+{{< figure src="/wp-content/uploads/a.jpg" alt="An image" caption="A {caption}" >}}
+
+{{< youtube gJ7AAJXHeeg >}}
+
+` + "```go\nfunc main() {}\n```\n"
+
+func getTestMetadata() map[string]any {
+	return map[string]any{
+		"url":     "/2024/01/hello/",
+		"title":   "Hello",
+		"author":  "alice",
+		"date":    "2024-01-02T03:04:05+00:00",
+		"draft":   "true",
+		"summary": "The summary",
+	}
+}
+
+func TestGetTarget(t *testing.T) {
+	t.Parallel()
+	target, err := GetTarget(TargetHugo)
+	require.NoError(t, err)
+	require.Nil(t, target)
+
+	target, err = GetTarget(TargetAstro)
+	require.NoError(t, err)
+	require.Equal(t, "Astro", target.Name())
+
+	_, err = GetTarget("gatsby")
+	require.ErrorContains(t, err, "unknown output target")
+}
+
+func TestPagePath(t *testing.T) {
+	t.Parallel()
+	publishDate := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	post, page, faq := "post", "page", "avada_faq"
+	testCases := []struct {
+		target       Target
+		postType     *string
+		publishDate  *time.Time
+		expectedDir  string
+		expectedFile string
+	}{
+		{jekyllTarget{}, &post, &publishDate, "_posts", "2024-01-02-hello"},
+		{jekyllTarget{}, &post, nil, "_drafts", "hello"},
+		{jekyllTarget{}, &page, nil, "pages", "hello"},
+		{jekyllTarget{}, &faq, nil, "_avada_faqs", "hello"},
+		{astroTarget{}, &post, &publishDate, "src/content/posts", "hello"},
+		{mkDocsTarget{}, &page, nil, "docs/pages", "hello"},
+	}
+	for _, testCase := range testCases {
+		dir, fileName := testCase.target.PagePath(wpparser.CommonFields{
+			Link:        "https://example.com/2024/01/hello/",
+			PostType:    testCase.postType,
+			PublishDate: testCase.publishDate,
+		})
+		require.Equal(t, testCase.expectedDir, dir)
+		require.Equal(t, testCase.expectedFile, fileName)
+	}
+}
+
+func TestJekyllRender(t *testing.T) {
+	t.Parallel()
+	content, err := jekyllTarget{}.Render(getTestMetadata(), _testMarkdown)
+	require.NoError(t, err)
+	output := string(content)
+	require.Contains(t, output, "permalink: /2024/01/hello/\n")
+	require.Contains(t, output, "published: false\n")
+	require.Contains(t, output, "excerpt: The summary\n")
+	require.Contains(t, output, "excerpt_separator: <!--more-->\n")
+	require.NotContains(t, output, "draft:")
+	require.Contains(t, output, `{% include figure.html src="/wp-content/uploads/a.jpg" alt="An image" caption="A {caption}" %}`)
+	require.Contains(t, output, `{% include youtube.html id="gJ7AAJXHeeg" %}`)
+	require.NotContains(t, output, "{{<")
+}
+
+func TestAstroRender(t *testing.T) {
+	t.Parallel()
+	content, err := astroTarget{}.Render(getTestMetadata(), _testMarkdown)
+	require.NoError(t, err)
+	output := string(content)
+	require.Contains(t, output, "pubDate: \"2024-01-02T03:04:05+00:00\"\n")
+	require.Contains(t, output, "description: The summary\n")
+	require.Contains(t, output, "draft: true\n")
+	require.Contains(t, output, "import { Figure, YouTube } from '../../components/wp2hugo';")
+	require.Contains(t, output, `<Figure src="/wp-content/uploads/a.jpg" alt="An image" caption="A &#123;caption&#125;" />`)
+	require.Contains(t, output, `<YouTube id="gJ7AAJXHeeg" />`)
+	require.Contains(t, output, `Some \{text\} with a &lt; b`)
+	// Code is left as is
+	require.Contains(t, output, "func main() {}")
+}
+
+func TestAstroRender_Description(t *testing.T) {
+	t.Parallel()
+	metadata := getTestMetadata()
+	metadata["description"] = "The description"
+	content, err := astroTarget{}.Render(metadata, _testMarkdown)
+	require.NoError(t, err)
+	output := string(content)
+	require.Contains(t, output, "description: The description\n")
+	require.NotContains(t, output, "The summary")
+}
+
+func TestSanitizeMDX(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "{/* note */}\n\nLine<br />\n\n`{code}`", sanitizeMDX("<!-- note -->\n\nLine<br>\n\n`{code}`"))
+}
+
+func TestMkDocsRender(t *testing.T) {
+	t.Parallel()
+	content, err := mkDocsTarget{}.Render(getTestMetadata(), _testMarkdown)
+	require.NoError(t, err)
+	output := string(content)
+	require.Contains(t, output, "authors:\n  - alice\n")
+	require.Contains(t, output, "description: The summary\n")
+	require.Contains(t, output, "Intro\n\n<!-- more -->\n")
+	require.Contains(t, output, "<figure markdown=\"span\">\n  ![An image](/wp-content/uploads/a.jpg)\n  <figcaption>A {caption}</figcaption>\n</figure>")
+	require.Contains(t, output, `src="https://www.youtube.com/embed/gJ7AAJXHeeg"`)
+}
+
+// Markdown of a columns block, with buttons, as converted by hugopage
+const _testLayoutMarkdown = `{{< columns >}}
+
+{{< column width="40%" >}}
+
+One
+
+{{< /column >}}
+
+{{< column >}}
+
+{{< buttons >}}
+
+{{< button href="https://example.com/signup" text="Sign up" target="_blank" >}}
+
+{{< /buttons >}}
+
+{{< /column >}}
+
+{{< /columns >}}
+
+{{< details summary="Question" open=true >}}
+
+Answer
+
+{{< /details >}}
+`
+
+func TestRenderLayoutShortcodes(t *testing.T) {
+	t.Parallel()
+	for _, target := range []Target{jekyllTarget{}, mkDocsTarget{}} {
+		content, err := target.Render(map[string]any{"title": "Hello"}, _testLayoutMarkdown)
+		require.NoError(t, err)
+		output := string(content)
+		require.NotContains(t, output, "{{<", target.Name())
+		require.Contains(t, output, `<div class="wp-columns" style="display: flex; flex-wrap: wrap; gap: 2em;" markdown="1">`)
+		require.Contains(t, output, "<div class=\"wp-column\" style=\"flex: 0 1 40%; min-width: 12em;\" markdown=\"1\">\n\nOne\n\n</div>")
+		require.Contains(t, output, `<div class="wp-column" style="flex: 1 1 0; min-width: 12em;" markdown="1">`)
+		require.Contains(t, output, `<a class="wp-button" href="https://example.com/signup" target="_blank" rel="noopener">Sign up</a>`)
+		require.Contains(t, output, "<details open markdown=\"1\">\n<summary>Question</summary>\n\nAnswer\n\n</details>")
+	}
+
+	content, err := astroTarget{}.Render(map[string]any{"title": "Hello"}, _testLayoutMarkdown)
+	require.NoError(t, err)
+	output := string(content)
+	require.NotContains(t, output, "{{<")
+	require.Contains(t, output, "import { Button, Buttons, Column, Columns, Details } from '../../components/wp2hugo';")
+	require.Contains(t, output, "<Column width=\"40%\">\n\nOne\n\n</Column>")
+	require.Contains(t, output, `<Button href="https://example.com/signup" text="Sign up" target="_blank" />`)
+	require.Contains(t, output, "<Details summary=\"Question\" open=\"true\">\n\nAnswer\n\n</Details>")
+}
+
+// Markdown of embeds, as converted by hugopage, the last one in privacy mode
+const _testEmbedMarkdown = `{{< vimeo 76979871 >}}
+
+{{< x user="wordpress" id="123" >}}
+
+{{< spotify type="track" id="abc" >}}
+
+{{< embed-placeholder provider="CodePen" src="https://codepen.io/jane/embed/xyz?default-tab=result" url="https://codepen.io/jane/pen/xyz" >}}
+`
+
+func TestRenderEmbedShortcodes(t *testing.T) {
+	t.Parallel()
+	for _, target := range []Target{jekyllTarget{}, mkDocsTarget{}} {
+		content, err := target.Render(map[string]any{"title": "Hello"}, _testEmbedMarkdown)
+		require.NoError(t, err)
+		output := string(content)
+		require.NotContains(t, output, "{{<", target.Name())
+		require.Contains(t, output, `<iframe loading="lazy" title="Vimeo video player" src="https://player.vimeo.com/video/76979871" width="640" height="360"`)
+		require.Contains(t, output, `src="https://platform.twitter.com/embed/Tweet.html?id=123"`)
+		require.Contains(t, output, `src="https://open.spotify.com/embed/track/abc" width="100%" height="152"`)
+		require.Contains(t, output, `<button type="button" data-src="https://codepen.io/jane/embed/xyz?default-tab=result"`)
+		require.Contains(t, output, `<p><a href="https://codepen.io/jane/pen/xyz">View on CodePen</a></p>`)
+	}
+
+	content, err := astroTarget{}.Render(map[string]any{"title": "Hello"}, _testEmbedMarkdown)
+	require.NoError(t, err)
+	output := string(content)
+	require.NotContains(t, output, "{{<")
+	require.Contains(t, output, "import { Embed, EmbedPlaceholder } from '../../components/wp2hugo';")
+	require.Contains(t, output, `<Embed title="Vimeo video player" src="https://player.vimeo.com/video/76979871" width="640" height="360" />`)
+	require.Contains(t, output, `<EmbedPlaceholder provider="CodePen" src="https://codepen.io/jane/embed/xyz?default-tab=result" url="https://codepen.io/jane/pen/xyz" />`)
+}
+
+func TestMkDocsFinish(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("../hugogenerator/testdata/testcase.WordPress_2.xml")
+	require.NoError(t, err)
+	info, err := wpparser.NewParser().Parse(file, nil, nil)
+	require.NoError(t, err)
+	publishDate := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	siteDir := t.TempDir()
+	require.NoError(t, mkDocsTarget{}.Finish(siteDir, *info, []Page{
+		{Path: "docs/posts/hello.md", Title: "Hello", PostType: "post", PublishDate: &publishDate},
+		{Path: "docs/pages/about.md", Title: "About", PostType: "page"},
+	}))
+	config, err := os.ReadFile(path.Join(siteDir, "mkdocs.yml"))
+	require.NoError(t, err)
+	require.Contains(t, string(config), "nav:\n  - Pages:\n      - About: pages/about.md\n  - Posts:\n      - Hello: posts/hello.md\n")
+	require.FileExists(t, path.Join(siteDir, "docs", _mkDocsCSSPath))
+}
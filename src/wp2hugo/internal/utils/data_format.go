@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the front matter, the config and the data files supported by Hugo
+// Ref: https://gohugo.io/content-management/front-matter/
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+	FormatJSON = "json"
+)
+
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatYAML, FormatTOML, FormatJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// FileExtension returns the extension, including the dot, of the files in format
+func FileExtension(format string) string {
+	return "." + format
+}
+
+// FrontMatterDelimiter returns the line around the front matter in format, empty for JSON
+// which is delimited by its braces
+func FrontMatterDelimiter(format string) string {
+	switch format {
+	case FormatTOML:
+		return "+++"
+	case FormatJSON:
+		return ""
+	default:
+		return "---"
+	}
+}
+
+// GetFormatFromFrontMatter returns the format of the front matter starting content, YAML if unknown
+func GetFormatFromFrontMatter(content []byte) string {
+	content = bytes.TrimLeft(content, "\ufeff \t\r\n")
+	switch {
+	case bytes.HasPrefix(content, []byte("+++")):
+		return FormatTOML
+	case bytes.HasPrefix(content, []byte("{")):
+		return FormatJSON
+	default:
+		return FormatYAML
+	}
+}
+
+// Marshal encodes input in format. The keys are the same in all formats, the ones of the yaml tags
+func Marshal(format string, input any) ([]byte, error) {
+	if format == FormatYAML {
+		return GetYAML(input)
+	}
+
+	// TOML and JSON cannot encode the map[any]any decoded from YAML or PHP arrays, and
+	// the structs of the repo are only tagged for YAML
+	generic, err := toGeneric(input)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatTOML:
+		var output bytes.Buffer
+		if err := toml.NewEncoder(&output).Encode(generic); err != nil {
+			return nil, fmt.Errorf("error marshalling to TOML: %w", err)
+		}
+		return output.Bytes(), nil
+	case FormatJSON:
+		output, err := json.MarshalIndent(generic, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling to JSON: %w", err)
+		}
+		return append(output, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// Unmarshal decodes data in format into output, using its yaml tags
+func Unmarshal(format string, data []byte, output any) error {
+	var generic any
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, output); err != nil {
+			return fmt.Errorf("error unmarshalling YAML: %w", err)
+		}
+		return nil
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &generic); err != nil {
+			return fmt.Errorf("error unmarshalling TOML: %w", err)
+		}
+	case FormatJSON:
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return fmt.Errorf("error unmarshalling JSON: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	data, err := GetYAML(generic)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, output); err != nil {
+		return fmt.Errorf("error unmarshalling %s: %w", format, err)
+	}
+	return nil
+}
+
+// toGeneric converts input into maps with string keys, slices and scalars, through YAML
+func toGeneric(input any) (any, error) {
+	data, err := GetYAML(input)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("error unmarshalling YAML: %w", err)
+	}
+	return generic, nil
+}
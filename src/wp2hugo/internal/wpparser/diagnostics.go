@@ -0,0 +1,56 @@
+package wpparser
+
+import (
+	"fmt"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// Diagnostic is a problem found in a WordPress item of the export
+type Diagnostic struct {
+	PostID   string `json:"post_id,omitempty"`
+	PostType string `json:"post_type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	// E.g. "wp:post_parent"
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+	// Whether the whole item has been skipped, otherwise only the field has been ignored or defaulted
+	Skipped bool `json:"skipped"`
+}
+
+func (d Diagnostic) String() string {
+	action := "degraded"
+	if d.Skipped {
+		action = "skipped"
+	}
+	return fmt.Sprintf("%s %s %q (post ID %s) %s: %s", action, d.PostType, d.Title, d.PostID, d.Field, d.Reason)
+}
+
+// fieldError is returned when a field required to import an item is missing or invalid
+type fieldError struct {
+	field  string
+	reason string
+}
+
+func (e *fieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.field, e.reason)
+}
+
+// getWPValue returns the value of the first <wp:name> element of item, if any
+func getWPValue(item *rss.Item, name string) (string, bool) {
+	values := item.Extensions["wp"][name]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0].Value, true
+}
+
+// getChildValue returns the value of the first name child of element, if any
+func getChildValue(element ext.Extension, name string) (string, bool) {
+	values := element.Children[name]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0].Value, true
+}
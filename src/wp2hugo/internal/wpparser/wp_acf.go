@@ -0,0 +1,133 @@
+package wpparser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/leeqvip/gophp"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+)
+
+// ACFFieldGroup is a field group of the Advanced Custom Fields plugin, an acf-field-group item of the export
+// Ref: https://www.advancedcustomfields.com/resources/
+type ACFFieldGroup struct {
+	ID string
+	// E.g. group_64a1b2c3d4e5f
+	Key   string
+	Title string
+	// In order
+	Fields []ACFField
+}
+
+// ACFField is a field of the Advanced Custom Fields plugin, an acf-field item of the export.
+// Posts store the value of a field in the meta Name, and its Key in the meta _Name
+type ACFField struct {
+	ID string
+	// E.g. field_64a1b2c3d4e5f
+	Key string
+	// Name of the meta, e.g. price
+	Name  string
+	Label string
+	// E.g. text, image, repeater
+	Type string
+	// Post ID of the field group or of the parent field
+	ParentID string
+	Order    int
+	// Settings of the field, e.g. return_format, decoded from the PHP serialized content of the item
+	Settings map[string]any
+	// Fields of the repeaters, groups and flexible contents, in order
+	SubFields []ACFField
+}
+
+// Setting returns the setting name of the field as a string, empty if it is not set
+func (f ACFField) Setting(name string) string {
+	value, ok := f.Settings[name]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// getACFField reads an acf-field item, whose content is the PHP serialized settings of the field
+func getACFField(item *rss.Item, taxonomies []TaxonomyInfo) (*ACFField, error) {
+	fields, err := getCommonFields(item, taxonomies)
+	if err != nil {
+		return nil, fmt.Errorf("error getting common fields: %w", err)
+	}
+
+	key, _ := getWPValue(item, "post_name")
+	if key == "" {
+		return nil, &fieldError{field: "wp:post_name", reason: "missing ACF field key"}
+	}
+	field := &ACFField{
+		ID:       fields.PostID,
+		Key:      key,
+		Name:     fields.Excerpt,
+		Label:    fields.Title,
+		Settings: make(map[string]any),
+	}
+	if fields.PostParentID != nil {
+		field.ParentID = *fields.PostParentID
+	}
+	if order, ok := getWPValue(item, "menu_order"); ok {
+		field.Order, _ = strconv.Atoi(order)
+	}
+
+	settings, err := gophp.Unserialize([]byte(fields.Content))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("key", key).
+			Msg("Failed to decode the settings of ACF field")
+	} else if settings, ok := settings.(map[string]any); ok {
+		field.Settings = settings
+	}
+	field.Type = field.Setting("type")
+	return field, nil
+}
+
+func getACFFieldGroup(item *rss.Item, taxonomies []TaxonomyInfo) (*ACFFieldGroup, error) {
+	fields, err := getCommonFields(item, taxonomies)
+	if err != nil {
+		return nil, fmt.Errorf("error getting common fields: %w", err)
+	}
+
+	key, _ := getWPValue(item, "post_name")
+	return &ACFFieldGroup{
+		ID:    fields.PostID,
+		Key:   key,
+		Title: fields.Title,
+	}, nil
+}
+
+// nestACFFields returns groups with their fields, and the fields with their sub fields, in order
+func nestACFFields(groups []ACFFieldGroup, fields []ACFField) []ACFFieldGroup {
+	children := make(map[string][]ACFField)
+	for _, field := range fields {
+		children[field.ParentID] = append(children[field.ParentID], field)
+	}
+	var nest func(parentID string, depth int) []ACFField
+	nest = func(parentID string, depth int) []ACFField {
+		// Guards against a field being its own ancestor
+		if depth > 10 {
+			return nil
+		}
+		result := slices.Clone(children[parentID])
+		slices.SortStableFunc(result, func(a, b ACFField) int {
+			return a.Order - b.Order
+		})
+		for i := range result {
+			result[i].SubFields = nest(result[i].ID, depth+1)
+		}
+		return result
+	}
+
+	result := make([]ACFFieldGroup, 0, len(groups))
+	for _, group := range groups {
+		group.Fields = nest(group.ID, 0)
+		result = append(result, group)
+	}
+	return result
+}
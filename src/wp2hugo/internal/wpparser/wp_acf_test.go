@@ -0,0 +1,55 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseACFFieldGroups(t *testing.T) {
+	t.Parallel()
+	expected := []ACFFieldGroup{{
+		ID:    "300",
+		Key:   "group_64a1b2c3d4e5f",
+		Title: "Product",
+		Fields: []ACFField{
+			{
+				ID:       "301",
+				Key:      "field_64a1b2c3d4e60",
+				Name:     "price",
+				Label:    "Price",
+				Type:     "number",
+				ParentID: "300",
+				Settings: map[string]any{"type": "number"},
+			},
+			{
+				ID:       "302",
+				Key:      "field_64a1b2c3d4e61",
+				Name:     "features",
+				Label:    "Features",
+				Type:     "repeater",
+				ParentID: "300",
+				Order:    1,
+				Settings: map[string]any{"type": "repeater", "min": 0},
+				SubFields: []ACFField{{
+					ID:       "303",
+					Key:      "field_64a1b2c3d4e62",
+					Name:     "label",
+					Label:    "Label",
+					Type:     "text",
+					ParentID: "302",
+					Settings: map[string]any{"type": "text"},
+				}},
+			},
+		},
+	}}
+
+	info, err := NewParser().Parse(strings.NewReader(readTestExport(t, "acf.WordPress.xml")), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, info.ACFFieldGroups())
+
+	info, err = NewParser().ParseStream(strings.NewReader(readTestExport(t, "acf.WordPress.xml")), nil, nil, StreamHandler{})
+	require.NoError(t, err)
+	require.Equal(t, expected, info.ACFFieldGroups())
+}
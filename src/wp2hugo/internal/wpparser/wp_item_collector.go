@@ -0,0 +1,276 @@
+package wpparser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+)
+
+// StreamHandler receives items as soon as they have been parsed.
+// Items whose callback is nil are retained in the returned WebsiteInfo instead,
+// so the zero value behaves exactly like Parser.Parse.
+type StreamHandler struct {
+	OnAttachment func(attachment AttachmentInfo) error
+	OnPage       func(page PageInfo) error
+	OnPost       func(post PostInfo) error
+	OnCustomPost func(customPost CustomPostInfo) error
+}
+
+// itemCollector dispatches WordPress items by post type, either to a StreamHandler
+// or into the slices that end up in WebsiteInfo
+type itemCollector struct {
+	authors         []string
+	customPostTypes []string
+	taxonomies      []TaxonomyInfo
+	handler         StreamHandler
+	// Skip the malformed items instead of failing
+	tolerant bool
+
+	diagnostics     []Diagnostic
+	attachments     []AttachmentInfo
+	pages           []PageInfo
+	posts           []PostInfo
+	customPosts     []CustomPostInfo
+	navigationLinks []NavigationLink
+	navMenuItems    []NavMenuItem
+	streamedPosts   []PostLink
+	reusableBlocks  []ReusableBlock
+	acfFieldGroups  []ACFFieldGroup
+	acfFields       []ACFField
+}
+
+func newItemCollector(authors []string, customPostTypes []string, taxonomies []TaxonomyInfo,
+	handler StreamHandler, tolerant bool,
+) *itemCollector {
+	return &itemCollector{
+		authors:         authors,
+		customPostTypes: customPostTypes,
+		taxonomies:      taxonomies,
+		handler:         handler,
+		tolerant:        tolerant,
+
+		attachments: make([]AttachmentInfo, 0),
+		pages:       make([]PageInfo, 0),
+		posts:       make([]PostInfo, 0),
+		customPosts: make([]CustomPostInfo, 0),
+	}
+}
+
+func (c *itemCollector) addItem(item *rss.Item) error {
+	wpPostType, ok := getWPValue(item, "post_type")
+	if !ok {
+		return c.skipItem(item, "", &fieldError{field: "wp:post_type", reason: "missing"})
+	}
+	switch wpPostType {
+	case "attachment":
+		if attachment, err := getAttachmentInfo(item, c.taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+			return c.skipItem(item, wpPostType, err)
+		} else if attachment != nil && hasValidAuthor(c.authors, attachment.CommonFields) {
+			log.Debug().
+				Str("postID", attachment.PostID).
+				Str("postType", wpPostType).
+				Msg("processing attachment")
+			c.addDiagnostics(item, wpPostType, attachment.diagnostics)
+			if c.handler.OnAttachment != nil {
+				return c.handler.OnAttachment(*attachment)
+			}
+			c.attachments = append(c.attachments, *attachment)
+		}
+	case "page":
+		if page, err := getPageInfo(item, c.taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+			return c.skipItem(item, wpPostType, err)
+		} else if page != nil {
+			if page.Content == "" && hasValidAuthor(c.authors, page.CommonFields) {
+				log.Warn().
+					Str("title", page.Title).
+					Msg("Empty content")
+			}
+			log.Debug().
+				Str("postID", page.PostID).
+				Str("postType", wpPostType).
+				Msg("processing page")
+			c.addDiagnostics(item, wpPostType, page.diagnostics)
+			if c.handler.OnPage != nil {
+				return c.handler.OnPage(*page)
+			}
+			c.pages = append(c.pages, *page)
+		}
+	case "post":
+		if post, err := getPostInfo(item, c.taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+			return c.skipItem(item, wpPostType, err)
+		} else if post != nil && hasValidAuthor(c.authors, post.CommonFields) {
+			if post.Content == "" {
+				log.Warn().
+					Str("title", post.Title).
+					Msg("Empty content")
+			}
+			log.Debug().
+				Str("postID", post.PostID).
+				Str("postType", wpPostType).
+				Msg("processing Post")
+			c.addDiagnostics(item, wpPostType, post.diagnostics)
+			if c.handler.OnPost != nil {
+				c.streamedPosts = append(c.streamedPosts, newPostLink(post.CommonFields))
+				return c.handler.OnPost(*post)
+			}
+			c.posts = append(c.posts, *post)
+		}
+	case "wp_navigation":
+		navigationLinks, err := getNavigationLinks(item.Content)
+		if err != nil {
+			return c.skipItem(item, wpPostType, fmt.Errorf("error getting navigation links: %w", err))
+		}
+		c.navigationLinks = navigationLinks
+	case "nav_menu_item":
+		if menuItem, err := getNavMenuItem(item, c.taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+			// Menus are not content, a broken entry never fails the import
+			c.dropItem(item, wpPostType, err)
+		} else if menuItem != nil {
+			log.Debug().
+				Str("postID", menuItem.ID).
+				Str("menu", menuItem.MenuSlug).
+				Msg("processing menu item")
+			c.navMenuItems = append(c.navMenuItems, *menuItem)
+		}
+	case "wp_block":
+		if block, err := getReusableBlock(item, c.taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+			return c.skipItem(item, wpPostType, err)
+		} else if block != nil {
+			log.Debug().
+				Str("postID", block.ID).
+				Str("slug", block.Slug).
+				Msg("processing reusable block")
+			c.reusableBlocks = append(c.reusableBlocks, *block)
+		}
+	case "acf-field-group":
+		if group, err := getACFFieldGroup(item, c.taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+			return c.skipItem(item, wpPostType, err)
+		} else if group != nil {
+			log.Debug().
+				Str("postID", group.ID).
+				Str("key", group.Key).
+				Msg("processing ACF field group")
+			c.acfFieldGroups = append(c.acfFieldGroups, *group)
+		}
+	case "acf-field":
+		if field, err := getACFField(item, c.taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+			return c.skipItem(item, wpPostType, err)
+		} else if field != nil {
+			log.Debug().
+				Str("postID", field.ID).
+				Str("key", field.Key).
+				Str("name", field.Name).
+				Msg("processing ACF field")
+			c.acfFields = append(c.acfFields, *field)
+		}
+	case "amp_validated_url", "custom_css", "wp_global_styles":
+		// Ignoring these for now
+		return nil
+	default:
+		if !slices.Contains(c.customPostTypes, wpPostType) {
+			log.Info().
+				Str("title", item.Title).
+				Str("type", wpPostType).
+				Msg("Ignoring item due to unknown type")
+			return nil
+		}
+		if customPost, err := getCustomPostInfo(item, c.taxonomies); err != nil && !errors.Is(err, errTrashItem) {
+			return c.skipItem(item, wpPostType, err)
+		} else if customPost != nil {
+			if customPost.Content == "" {
+				log.Warn().
+					Str("title", customPost.Title).
+					Msg("Empty content")
+			}
+			log.Debug().
+				Str("postID", customPost.PostID).
+				Str("postType", wpPostType).
+				Msg("processing post")
+			c.addDiagnostics(item, wpPostType, customPost.diagnostics)
+			if c.handler.OnCustomPost != nil {
+				c.streamedPosts = append(c.streamedPosts, newPostLink(customPost.CommonFields))
+				return c.handler.OnCustomPost(*customPost)
+			}
+			c.customPosts = append(c.customPosts, *customPost)
+		}
+	}
+	return nil
+}
+
+// skipItem fails with err, or in tolerant mode, reports item as skipped and carries on
+func (c *itemCollector) skipItem(item *rss.Item, postType string, err error) error {
+	postID, _ := getWPValue(item, "post_id")
+	if !c.tolerant {
+		return fmt.Errorf("error reading %s %q (post ID %s): %w", postType, item.Title, postID, err)
+	}
+	c.dropItem(item, postType, err)
+	return nil
+}
+
+// dropItem reports item as skipped because of err
+func (c *itemCollector) dropItem(item *rss.Item, postType string, err error) {
+	postID, _ := getWPValue(item, "post_id")
+	diagnostic := Diagnostic{
+		PostID:   postID,
+		PostType: postType,
+		Title:    item.Title,
+		Reason:   err.Error(),
+		Skipped:  true,
+	}
+	var fieldErr *fieldError
+	if errors.As(err, &fieldErr) {
+		diagnostic.Field = fieldErr.field
+		diagnostic.Reason = fieldErr.reason
+	}
+	log.Warn().
+		Str("postID", postID).
+		Str("postType", postType).
+		Str("title", item.Title).
+		Err(err).
+		Msg("Skipping malformed item")
+	c.diagnostics = append(c.diagnostics, diagnostic)
+}
+
+// addDiagnostics reports the fields of item which have been ignored or defaulted
+func (c *itemCollector) addDiagnostics(item *rss.Item, postType string, diagnostics []Diagnostic) {
+	postID, _ := getWPValue(item, "post_id")
+	for _, diagnostic := range diagnostics {
+		diagnostic.PostID = postID
+		diagnostic.PostType = postType
+		diagnostic.Title = item.Title
+		c.diagnostics = append(c.diagnostics, diagnostic)
+	}
+}
+
+// websiteInfo completes the site-level fields of info with the retained items
+func (c *itemCollector) websiteInfo(info WebsiteInfo) *WebsiteInfo {
+	info.attachments = c.attachments
+	info.pages = c.pages
+	info.posts = c.posts
+	info.customPosts = c.customPosts
+	info.navigationLinks = c.navigationLinks
+	info.navMenuItems = c.navMenuItems
+	info.streamedPosts = c.streamedPosts
+	info.reusableBlocks = c.reusableBlocks
+	info.acfFieldGroups = c.acfFieldGroups
+	info.acfFields = c.acfFields
+	info.customPostTypes = c.customPostTypes
+	info.postIDToAttachmentCache = getPostIDToAttachmentsMap(c.attachments)
+
+	log.Info().
+		Int("numAttachments", len(info.attachments)).
+		Int("numPages", len(info.pages)).
+		Int("numPosts", len(info.posts)).
+		Int("numCustomPosts", len(info.customPosts)).
+		Int("numNavigationLinks", len(info.navigationLinks)).
+		Int("numNavMenuItems", len(info.navMenuItems)).
+		Int("numReusableBlocks", len(info.reusableBlocks)).
+		Int("numACFFields", len(info.acfFields)).
+		Int("numCategories", len(info.categories)).
+		Int("numTags", len(info.tags)).
+		Msgf("WebsiteInfo: %s", info.title)
+	return &info
+}
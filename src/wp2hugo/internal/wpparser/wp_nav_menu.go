@@ -0,0 +1,101 @@
+package wpparser
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// Types of the classic menu items, see _menu_item_type
+const (
+	NavMenuItemTypePost     = "post_type"
+	NavMenuItemTypeArchive  = "post_type_archive"
+	NavMenuItemTypeTaxonomy = "taxonomy"
+	NavMenuItemTypeCustom   = "custom"
+)
+
+// NavMenuItem is an entry of a classic WordPress menu, a nav_menu_item item of the export
+type NavMenuItem struct {
+	ID string
+	// Slug and name of the nav_menu term of the menu
+	MenuSlug string
+	MenuName string
+	// Empty if the title of the linked object is used
+	Title    string
+	Order    int
+	ParentID string // ID of the parent menu item, if any
+	// One of NavMenuItemType*
+	Type string
+	// Post type or taxonomy of the linked object, e.g. "page" or "category"
+	Object   string
+	ObjectID string
+	// Only set for custom links
+	URL string
+}
+
+// PostLink is what menu items need of a post, kept for the posts which are streamed
+type PostLink struct {
+	PostID string
+	Title  string
+	Link   string
+}
+
+func newPostLink(fields CommonFields) PostLink {
+	return PostLink{PostID: fields.PostID, Title: fields.Title, Link: fields.Link}
+}
+
+func getNavMenuItem(item *rss.Item, taxonomies []TaxonomyInfo) (*NavMenuItem, error) {
+	fields, err := getCommonFields(item, taxonomies)
+	if err != nil {
+		return nil, fmt.Errorf("error getting common fields: %w", err)
+	}
+
+	if fields.PublishStatus == PublishStatusDraft {
+		// Added to a menu but never saved
+		return nil, nil
+	}
+
+	menuItem := NavMenuItem{
+		ID:    fields.PostID,
+		Title: fields.Title,
+	}
+	for _, category := range item.Categories {
+		if category.Domain == "nav_menu" {
+			menuItem.MenuName = category.Value
+			menuItem.MenuSlug = Slugify(category.Value)
+		}
+	}
+	// The nicename attribute is not parsed, the slug is the one of the term
+	for _, term := range fields.Taxonomies {
+		if term.Taxonomy == "nav_menu" && term.Slug != "" {
+			menuItem.MenuSlug = term.Slug
+		}
+	}
+	if menuItem.MenuSlug == "" {
+		return nil, &fieldError{field: "category", reason: "not in any menu"}
+	}
+
+	if order, ok := getWPValue(item, "menu_order"); ok {
+		if menuItem.Order, err = strconv.Atoi(order); err != nil {
+			return nil, &fieldError{field: "wp:menu_order", reason: fmt.Sprintf("invalid order %q", order)}
+		}
+	}
+	for _, metadatum := range fields.CustomMetaData {
+		switch metadatum.Key {
+		case "_menu_item_type":
+			menuItem.Type = metadatum.Value
+		case "_menu_item_menu_item_parent":
+			if metadatum.Value != "0" {
+				menuItem.ParentID = metadatum.Value
+			}
+		case "_menu_item_object":
+			menuItem.Object = metadatum.Value
+		case "_menu_item_object_id":
+			menuItem.ObjectID = metadatum.Value
+		case "_menu_item_url":
+			menuItem.URL = metadatum.Value
+		}
+	}
+	return &menuItem, nil
+}
@@ -0,0 +1,55 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNavMenuItems(t *testing.T) {
+	t.Parallel()
+	primary := func(item NavMenuItem) NavMenuItem {
+		// The slug of the nav_menu term wins over the one of its name
+		item.MenuSlug = "primary"
+		item.MenuName = "Main Menu"
+		return item
+	}
+	expected := []NavMenuItem{
+		primary(NavMenuItem{ID: "100", Order: 2, ParentID: "101", Type: NavMenuItemTypePost, Object: "page", ObjectID: "1"}),
+		primary(NavMenuItem{ID: "101", Order: 1, Type: NavMenuItemTypeTaxonomy, Object: "category", ObjectID: "3"}),
+		primary(NavMenuItem{
+			ID: "102", Title: "Home", Order: 3, Type: NavMenuItemTypeCustom, Object: "custom", ObjectID: "102",
+			URL: "https://example.net/",
+		}),
+		primary(NavMenuItem{ID: "103", Title: "Archives", Order: 4, Type: NavMenuItemTypeArchive, Object: "post"}),
+		primary(NavMenuItem{ID: "104", Order: 5, Type: NavMenuItemTypePost, Object: "post", ObjectID: "2"}),
+		{
+			ID:       "105",
+			MenuSlug: "social",
+			MenuName: "Social",
+			Title:    "Mastodon",
+			Order:    1,
+			ParentID: "100",
+			Type:     NavMenuItemTypeCustom,
+			Object:   "custom",
+			ObjectID: "105",
+			URL:      "https://mastodon.social/@example",
+		},
+	}
+	export := readTestExport(t, "nav_menus.WordPress.xml")
+
+	// Broken menu items are skipped, even if not tolerant
+	parser := NewParser()
+	info, err := parser.Parse(strings.NewReader(export), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, info.NavMenuItems())
+	require.Equal(t, []Diagnostic{
+		{PostID: "107", PostType: "nav_menu_item", Title: "Orphan", Field: "category", Reason: "not in any menu", Skipped: true},
+		{PostID: "108", PostType: "nav_menu_item", Title: "Misordered", Field: "wp:menu_order", Reason: `invalid order "first"`, Skipped: true},
+	}, parser.Diagnostics())
+
+	info, err = NewParser().ParseStream(strings.NewReader(export), nil, nil, StreamHandler{})
+	require.NoError(t, err)
+	require.Equal(t, expected, info.NavMenuItems())
+}
@@ -30,12 +30,27 @@ var (
 	nonAlphanumericRegex = regexp.MustCompile(`[^\p{L}]+`)
 )
 
-type Parser struct{}
+type Parser struct {
+	// Skip or degrade malformed items instead of failing
+	tolerant    bool
+	diagnostics []Diagnostic
+}
 
 func NewParser() *Parser {
 	return &Parser{}
 }
 
+// SetTolerant makes the parser skip the items it cannot import instead of failing,
+// they are reported by Diagnostics along with the fields which have been ignored
+func (p *Parser) SetTolerant(tolerant bool) {
+	p.tolerant = tolerant
+}
+
+// Diagnostics returns the problems found in the items parsed so far
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
 type PublishStatus string
 
 // See some discussion here https://github.com/ashishb/wp2hugo/issues/26
@@ -54,7 +69,9 @@ const (
 type CommonFields struct {
 	PostID string
 
-	Author           string
+	Author string
+	// Logins of the authors, Author first and then the co-authors, if any
+	Authors          []string
 	Title            string
 	Link             string     // Note that this is the absolute link for example https://example.com/about
 	PublishDate      *time.Time // This can be nil since an item might have never been published
@@ -82,6 +99,9 @@ type CommonFields struct {
 	attachmentURL *string
 
 	Comments []CommentInfo
+
+	// Fields of the item which have been ignored or defaulted
+	diagnostics []Diagnostic
 }
 
 func titleToFilename(title string) string {
@@ -169,7 +189,7 @@ func (i CommonFields) GetFileInfo() FileInfo {
 
 	// WooCommerce products have ugly links like https://website.com/?post_type=product&p=666
 	// Nothing meaningful there, but their GUID uses pretty links. Retry then.
-	if len(file) == 0 {
+	if len(file) == 0 && i.GUID != nil {
 		parts = strings.Split(strings.TrimRight(i.GUID.Value, "/"), "/")
 		file, params = findSlugAndParams(parts)
 	}
@@ -252,6 +272,10 @@ func (p *Parser) Parse(xmlData io.Reader, authors []string, customPostTypes []st
 			Msgf("error parsing XML")
 		return nil, fmt.Errorf("error parsing XML: %w", err)
 	}
+	return p.getWebsiteInfo(feed, getNonEmptyAuthors(authors), customPostTypes)
+}
+
+func getNonEmptyAuthors(authors []string) []string {
 	nonEmptyAuthors := make([]string, 0, len(authors))
 	for _, a := range authors {
 		a = strings.TrimSpace(a)
@@ -259,7 +283,7 @@ func (p *Parser) Parse(xmlData io.Reader, authors []string, customPostTypes []st
 			nonEmptyAuthors = append(nonEmptyAuthors, a)
 		}
 	}
-	return p.getWebsiteInfo(feed, nonEmptyAuthors, customPostTypes)
+	return nonEmptyAuthors
 }
 
 func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostTypes []string) (*WebsiteInfo, error) {
@@ -272,129 +296,37 @@ func (p *Parser) getWebsiteInfo(feed *rss.Feed, authors []string, customPostType
 		Any("Term", feed.Extensions["wp"]["term"]).
 		Msg("feed.Custom")
 
+	wpAuthors := getAuthors(feed.Extensions["wp"]["author"])
 	categories := getCategories(feed.Extensions["wp"]["category"])
 	tags := getTags(feed.Extensions["wp"]["tag"])
 	taxonomies := getTaxonomies(feed.Extensions["wp"]["term"])
 
-	attachments := make([]AttachmentInfo, 0)
-	pages := make([]PageInfo, 0)
-	posts := make([]PostInfo, 0)
-	customPosts := make([]CustomPostInfo, 0)
-	var navigationLinks []NavigationLink
-
+	collector := newItemCollector(authors, customPostTypes, taxonomies, StreamHandler{}, p.tolerant)
 	for _, item := range feed.Items {
-		wpPostType := item.Extensions["wp"]["post_type"][0].Value
-		switch wpPostType {
-		case "attachment":
-			if attachment, err := getAttachmentInfo(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
-				return nil, err
-			} else if attachment != nil && hasValidAuthor(authors, attachment.CommonFields) {
-				attachments = append(attachments, *attachment)
-				log.Debug().
-					Str("postID", attachment.PostID).
-					Str("postType", wpPostType).
-					Msg("processing attachment")
-			}
-		case "page":
-			if page, err := getPageInfo(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
-				return nil, err
-			} else if page != nil {
-				if page.Content == "" && hasValidAuthor(authors, page.CommonFields) {
-					log.Warn().
-						Str("title", page.Title).
-						Msg("Empty content")
-				}
-				pages = append(pages, *page)
-				log.Debug().
-					Str("postID", page.PostID).
-					Str("postType", wpPostType).
-					Msg("processing page")
-			}
-		case "post":
-			if post, err := getPostInfo(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
-				return nil, err
-			} else if post != nil && hasValidAuthor(authors, post.CommonFields) {
-				if post.Content == "" {
-					log.Warn().
-						Str("title", post.Title).
-						Msg("Empty content")
-				}
-				log.Debug().
-					Str("postID", post.PostID).
-					Str("postType", wpPostType).
-					Msg("processing Post")
-				posts = append(posts, *post)
-			}
-		case "wp_navigation":
-			var err error
-			navigationLinks, err = getNavigationLinks(item.Content)
-			if err != nil {
-				return nil, fmt.Errorf("error getting navigation links: %w", err)
-			}
-		case "amp_validated_url", "nav_menu_item", "custom_css", "wp_global_styles":
-			// Ignoring these for now
-			continue
-		default:
-			if slices.Contains(customPostTypes, wpPostType) {
-				if customPost, err := getCustomPostInfo(item, taxonomies); err != nil && !errors.Is(err, errTrashItem) {
-					return nil, err
-				} else if customPost != nil {
-					if customPost.Content == "" {
-						log.Warn().
-							Str("title", customPost.Title).
-							Msg("Empty content")
-					}
-					customPosts = append(customPosts, *customPost)
-					log.Debug().
-						Str("postID", customPost.PostID).
-						Str("postType", wpPostType).
-						Msg("processing post")
-				}
-			} else {
-				log.Info().
-					Str("title", item.Title).
-					Str("type", wpPostType).
-					Msg("Ignoring item due to unknown type")
-			}
+		if err := collector.addItem(item); err != nil {
+			return nil, err
 		}
 	}
+	p.diagnostics = append(p.diagnostics, collector.diagnostics...)
 
 	linkURL, err := url.Parse(feed.Link)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing feed link: %w", err)
 	}
 
-	websiteInfo := WebsiteInfo{
+	websiteInfo := collector.websiteInfo(WebsiteInfo{
 		title:       feed.Title,
 		link:        linkURL,
 		Description: feed.Description,
 		pubDate:     feed.PubDateParsed,
 		language:    feed.Language,
 
+		authors:    wpAuthors,
 		categories: categories,
 		tags:       tags,
 		taxonomies: taxonomies,
-
-		attachments:     attachments,
-		pages:           pages,
-		posts:           posts,
-		customPosts:     customPosts,
-		navigationLinks: navigationLinks,
-
-		customPostTypes: customPostTypes,
-
-		postIDToAttachmentCache: getPostIDToAttachmentsMap(attachments),
-	}
-	log.Info().
-		Int("numAttachments", len(websiteInfo.attachments)).
-		Int("numPages", len(websiteInfo.pages)).
-		Int("numPosts", len(websiteInfo.posts)).
-		Int("numCustomPosts", len(websiteInfo.customPosts)).
-		Int("numNavigationLinks", len(websiteInfo.navigationLinks)).
-		Int("numCategories", len(categories)).
-		Int("numTags", len(tags)).
-		Msgf("WebsiteInfo: %s", websiteInfo.title)
-	return &websiteInfo, nil
+	})
+	return websiteInfo, nil
 }
 
 func getAttachmentInfo(item *rss.Item, taxonomies []TaxonomyInfo) (*AttachmentInfo, error) {
@@ -410,21 +342,35 @@ func getAttachmentInfo(item *rss.Item, taxonomies []TaxonomyInfo) (*AttachmentIn
 }
 
 func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields, error) {
+	var diagnostics []Diagnostic
+	degrade := func(field string, reason string) {
+		diagnostics = append(diagnostics, Diagnostic{Field: field, Reason: reason})
+	}
+
+	postID, ok := getWPValue(item, "post_id")
+	if !ok || postID == "" {
+		return nil, &fieldError{field: "wp:post_id", reason: "missing"}
+	}
+
 	var lastModifiedDate *time.Time
-	values := item.Extensions["wp"]["post_modified_gmt"]
-	if len(values) > 0 {
+	if value, ok := getWPValue(item, "post_modified_gmt"); ok {
 		var err error
-		lastModifiedDate, err = parseTime(values[0].Value)
+		lastModifiedDate, err = parseTime(value)
 		if err != nil {
 			log.Warn().
 				Str("link", item.Link).
-				Str("date", item.Extensions["wp"]["post_modified_gmt"][0].Value).
+				Str("date", value).
 				Err(err).
 				Msg("Error parsing last modified date")
+			degrade("wp:post_modified_gmt", fmt.Sprintf("invalid date %q, ignored", value))
 		}
 	}
 
-	publishStatus := PublishStatus(item.Extensions["wp"]["status"][0].Value)
+	status, ok := getWPValue(item, "status")
+	if !ok {
+		degrade("wp:status", "missing, mapped to draft")
+	}
+	publishStatus := PublishStatus(status)
 	switch publishStatus {
 	case PublishStatusAttachment, PublishStatusDraft, PublishStatusFuture, PublishStatusInherit, PublishStatusPending,
 		PublishStatusPrivate, PublishStatusPublish, PublishStatusStatic:
@@ -432,13 +378,21 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 	case PublishStatusTrash:
 		return nil, fmt.Errorf("%w, ignored: %s", errTrashItem, item.Title)
 	default:
-		log.Warn().Msgf("Unknown publish status: '%s' for '%s'. Mapping to draft.", publishStatus, item.Title)
+		if ok {
+			log.Warn().Msgf("Unknown publish status: '%s' for '%s'. Mapping to draft.", publishStatus, item.Title)
+			degrade("wp:status", fmt.Sprintf("unknown status %q, mapped to draft", publishStatus))
+		}
 		publishStatus = PublishStatusDraft
 	}
 	pageCategories := make([]string, 0, len(item.Categories))
 	pageTags := make([]string, 0, len(item.Categories))
 	pageTaxonomies := make([]TaxonomyInfo, 0, len(item.Categories))
 	var postFormat *string
+	author := getAuthor(item)
+	pageAuthors := make([]string, 0, 1)
+	if author != "" {
+		pageAuthors = append(pageAuthors, author)
+	}
 
 	for _, category := range item.Categories {
 		if isCategory(category) {
@@ -448,6 +402,10 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 		} else if isPostFormat(category) {
 			tmp := NormalizeCategoryName(category.Value)
 			postFormat = &tmp
+		} else if isCoAuthor(category) {
+			if !slices.Contains(pageAuthors, category.Value) {
+				pageAuthors = append(pageAuthors, category.Value)
+			}
 		} else {
 			taxo := isTaxonomy(category, taxonomies)
 			if taxo != nil {
@@ -457,6 +415,7 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 					Str("link", item.Link).
 					Any("categories", item.Categories).
 					Msgf("Unknown category: %s", category)
+				degrade("category", fmt.Sprintf("unknown taxonomy %q of %q, ignored", category.Domain, category.Value))
 			}
 		}
 	}
@@ -505,13 +464,17 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 	}
 
 	pubDate := item.PubDateParsed
-	if pubDate == nil && len(item.Extensions["wp"]["post_date"]) > 0 {
-		tmp, err := time.Parse("2006-01-02 15:04:05", item.Extensions["wp"]["post_date"][0].Value)
+	if postDate, ok := getWPValue(item, "post_date"); pubDate == nil && ok {
+		tmp, err := time.Parse("2006-01-02 15:04:05", postDate)
 		if err != nil {
 			log.Warn().
 				Str("link", item.Link).
-				Str("date", item.Extensions["wp"]["post_date"][0].Value).
+				Str("date", postDate).
 				Msg("Error parsing date")
+			// Never published items have a zero date
+			if postDate != "0000-00-00 00:00:00" {
+				degrade("wp:post_date", fmt.Sprintf("invalid date %q, ignored", postDate))
+			}
 		} else {
 			pubDate = &tmp
 		}
@@ -525,7 +488,10 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 	}
 
 	var postParent *string
-	tmp := item.Extensions["wp"]["post_parent"][0].Value
+	tmp, ok := getWPValue(item, "post_parent")
+	if !ok {
+		degrade("wp:post_parent", "missing, the item has no parent")
+	}
 	if tmp != "0" && tmp != "" {
 		log.Debug().
 			Str("link", item.Link).
@@ -537,38 +503,60 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 	}
 
 	comments := make([]CommentInfo, 0, len(item.Extensions["wp"]["comment"]))
-	if len(item.Extensions["wp"]["comment"]) > 0 {
-		for _, comment := range item.Extensions["wp"]["comment"] {
-			// Don't append spams and unapproved comments
-			if comment.Children["comment_approved"][0].Value == "1" {
-				var commentPubDate *time.Time
-				tmp, err := time.Parse("2006-01-02 15:04:05", comment.Children["comment_date"][0].Value)
-				if err != nil {
-					log.Warn().
-						Str("date", item.Extensions["wp"]["post_date"][0].Value).
-						Msg("Error parsing date")
-				} else {
-					commentPubDate = &tmp
-				}
-
-				comments = append(comments, CommentInfo{
-					ID:          comment.Children["comment_id"][0].Value,
-					ParentID:    comment.Children["comment_parent"][0].Value,
-					AuthorName:  comment.Children["comment_author"][0].Value,
-					AuthorEmail: comment.Children["comment_author_email"][0].Value,
-					AuthorURL:   comment.Children["comment_author_url"][0].Value,
-					PublishDate: commentPubDate,
-					Content:     comment.Children["comment_content"][0].Value,
-					PostLink:    item.Link,
-					PostID:      item.Extensions["wp"]["post_id"][0].Value,
-				})
-			}
+	for i, comment := range item.Extensions["wp"]["comment"] {
+		commentID, ok := getChildValue(comment, "comment_id")
+		if !ok || commentID == "" {
+			degrade(fmt.Sprintf("wp:comment[%d].comment_id", i), "missing, comment ignored")
+			continue
 		}
+		// Don't append spams and unapproved comments
+		approved, ok := getChildValue(comment, "comment_approved")
+		if !ok {
+			degrade(fmt.Sprintf("wp:comment[%s].comment_approved", commentID), "missing, comment ignored")
+		}
+		if approved != "1" {
+			continue
+		}
+
+		var commentPubDate *time.Time
+		commentDate, _ := getChildValue(comment, "comment_date")
+		tmp, err := time.Parse("2006-01-02 15:04:05", commentDate)
+		if err != nil {
+			log.Warn().
+				Str("date", commentDate).
+				Msg("Error parsing date")
+			degrade(fmt.Sprintf("wp:comment[%s].comment_date", commentID), fmt.Sprintf("invalid date %q, ignored", commentDate))
+		} else {
+			commentPubDate = &tmp
+		}
+
+		parentID, _ := getChildValue(comment, "comment_parent")
+		authorName, _ := getChildValue(comment, "comment_author")
+		authorEmail, _ := getChildValue(comment, "comment_author_email")
+		authorURL, _ := getChildValue(comment, "comment_author_url")
+		content, _ := getChildValue(comment, "comment_content")
+		comments = append(comments, CommentInfo{
+			ID:          commentID,
+			ParentID:    parentID,
+			AuthorName:  authorName,
+			AuthorEmail: authorEmail,
+			AuthorURL:   authorURL,
+			PublishDate: commentPubDate,
+			Content:     content,
+			PostLink:    item.Link,
+			PostID:      postID,
+		})
+	}
+
+	var excerpt string
+	if excerpts := item.Extensions["excerpt"]["encoded"]; len(excerpts) > 0 {
+		excerpt = excerpts[0].Value
 	}
 
 	return &CommonFields{
-		Author:           getAuthor(item),
-		PostID:           item.Extensions["wp"]["post_id"][0].Value,
+		Author:           author,
+		Authors:          pageAuthors,
+		PostID:           postID,
 		Title:            item.Title,
 		Link:             item.Link,
 		PublishDate:      pubDate,
@@ -578,7 +566,7 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 		PostFormat:       postFormat,
 		PostType:         postType,
 		PostParentID:     postParent,
-		Excerpt:          item.Extensions["excerpt"]["encoded"][0].Value,
+		Excerpt:          excerpt,
 
 		Description:     item.Description,
 		Content:         item.Content,
@@ -592,6 +580,8 @@ func getCommonFields(item *rss.Item, taxonomies []TaxonomyInfo) (*CommonFields,
 		attachmentURL: attachmentURL,
 
 		Comments: comments,
+
+		diagnostics: diagnostics,
 	}, nil
 }
 
@@ -616,8 +606,8 @@ func getAuthor(item *rss.Item) string {
 	if len(author) > 0 {
 		return author
 	}
-	if item.Extensions["dc"] != nil && item.Extensions["dc"]["creator"] != nil {
-		return item.Extensions["dc"]["creator"][0].Value
+	if creators := item.Extensions["dc"]["creator"]; len(creators) > 0 {
+		return creators[0].Value
 	}
 	return ""
 }
@@ -701,13 +691,47 @@ func isTag(tag *rss.Category) bool {
 	return tag.Domain == "post_tag" || tag.Domain == "portfolio_tags" || tag.Domain == "product_tag"
 }
 
-func isTaxonomy(taxonomy *rss.Category, taxonomies []TaxonomyInfo) *TaxonomyInfo {
+// isCoAuthor returns whether category is an additional author of the Co-Authors Plus plugin,
+// its value is the login of the author
+// Ref: https://wordpress.org/plugins/co-authors-plus/
+func isCoAuthor(category *rss.Category) bool {
+	return category.Domain == "author" && category.Value != ""
+}
+
+// isTaxonomy returns the term of a custom taxonomy category is, or nil if its taxonomy is unknown
+func isTaxonomy(category *rss.Category, taxonomies []TaxonomyInfo) *TaxonomyInfo {
+	knownTaxonomy := false
 	for _, tax := range taxonomies {
-		if tax.Taxonomy == taxonomy.Domain {
+		if tax.Taxonomy != category.Domain {
+			continue
+		}
+		knownTaxonomy = true
+		// Items refer to terms by name, the nicename attribute is not parsed
+		if tax.Name == category.Value {
 			return &tax
 		}
 	}
-	return nil
+	if !knownTaxonomy {
+		return nil
+	}
+	log.Debug().
+		Str("taxonomy", category.Domain).
+		Str("term", category.Value).
+		Msg("Term missing from the export")
+	return &TaxonomyInfo{
+		Taxonomy: category.Domain,
+		Name:     category.Value,
+		Slug:     Slugify(category.Value),
+	}
+}
+
+// Matches the characters WordPress replaces with a dash in slugs
+var _nonSlugCharacters = regexp.MustCompile(`[^\p{L}\p{N}_-]+`)
+
+// Slugify returns the slug WordPress would derive from name, e.g. "Web Design" -> "web-design"
+func Slugify(name string) string {
+	slug := strings.ReplaceAll(strings.ToLower(name), "@", "")
+	return strings.Trim(_nonSlugCharacters.ReplaceAllString(slug, "-"), "-")
 }
 
 // NormalizeCategoryName removes space from the category name and converts it to lowercase
@@ -756,23 +780,49 @@ func getCustomPostInfo(item *rss.Item, taxonomies []TaxonomyInfo) (*CustomPostIn
 	return &post, nil
 }
 
+func getAuthors(inputs []ext.Extension) []AuthorInfo {
+	authors := make([]AuthorInfo, 0, len(inputs))
+	for _, input := range inputs {
+		id, _ := getChildValue(input, "author_id")
+		login, _ := getChildValue(input, "author_login")
+		email, _ := getChildValue(input, "author_email")
+		displayName, _ := getChildValue(input, "author_display_name")
+		firstName, _ := getChildValue(input, "author_first_name")
+		lastName, _ := getChildValue(input, "author_last_name")
+		if login == "" {
+			log.Warn().
+				Str("id", id).
+				Msg("Ignoring author without login")
+			continue
+		}
+		authors = append(authors, AuthorInfo{
+			ID:          id,
+			Login:       login,
+			Email:       email,
+			DisplayName: displayName,
+			FirstName:   firstName,
+			LastName:    lastName,
+		})
+	}
+	return authors
+}
+
 func getCategories(inputs []ext.Extension) []CategoryInfo {
 	categories := make([]CategoryInfo, 0, len(inputs))
 	for _, input := range inputs {
-		categoryName := ""
-		if len(input.Children["cat_name"]) > 0 {
-			categoryName = NormalizeCategoryName(input.Children["cat_name"][0].Value)
-		}
-		categoryNiceName := ""
-		if len(input.Children["category_nicename"]) > 0 {
-			categoryNiceName = input.Children["category_nicename"][0].Value
-		}
+		categoryTitle, _ := getChildValue(input, "cat_name")
+		categoryNiceName, _ := getChildValue(input, "category_nicename")
+		parent, _ := getChildValue(input, "category_parent")
+		description, _ := getChildValue(input, "category_description")
+		// ID is usually int but for safety let's assume string
+		id, _ := getChildValue(input, "term_id")
 		category := CategoryInfo{
-			// ID is usually int but for safety let's assume string
-			ID:       input.Children["term_id"][0].Value,
-			Name:     categoryName,
-			NiceName: categoryNiceName,
-			// We are ignoring "category_parent" for now as I have never used it
+			ID:          id,
+			Name:        NormalizeCategoryName(categoryTitle),
+			Title:       categoryTitle,
+			NiceName:    categoryNiceName,
+			Parent:      parent,
+			Description: description,
 		}
 		log.Trace().Msgf("category: %+v", category)
 		categories = append(categories, category)
@@ -783,21 +833,24 @@ func getCategories(inputs []ext.Extension) []CategoryInfo {
 func getTags(inputs []ext.Extension) []TagInfo {
 	categories := make([]TagInfo, 0, len(inputs))
 	for _, input := range inputs {
-		var tagName string
-		if len(input.Children["tag_name"]) == 0 {
+		slug, _ := getChildValue(input, "tag_slug")
+		tagName, ok := getChildValue(input, "tag_name")
+		if !ok {
 			// Fallback
-			tagName = input.Children["tag_slug"][0].Value
+			tagName = slug
 			log.Warn().
 				Any("input", input).
 				Msg("tag_name is missing")
-		} else {
-			tagName = input.Children["tag_name"][0].Value
 		}
+		description, _ := getChildValue(input, "tag_description")
+		// ID is usually int but for safety let's assume string
+		id, _ := getChildValue(input, "term_id")
 		tag := TagInfo{
-			// ID is usually int but for safety let's assume string
-			ID:   input.Children["term_id"][0].Value,
-			Name: NormalizeCategoryName(tagName),
-			Slug: input.Children["tag_slug"][0].Value,
+			ID:          id,
+			Name:        NormalizeCategoryName(tagName),
+			Title:       tagName,
+			Slug:        slug,
+			Description: description,
 		}
 		log.Trace().Msgf("tag: %+v", tag)
 		categories = append(categories, tag)
@@ -832,12 +885,14 @@ func buildTaxonomy(term ext.Extension) TaxonomyInfo {
 			id = 0
 		}
 	}
+	description, _ := getChildValue(term, "term_description")
 	return TaxonomyInfo{
-		ID:       id,
-		Taxonomy: taxonomy,
-		Parent:   parent,
-		Name:     name,
-		Slug:     slug,
+		ID:          id,
+		Taxonomy:    taxonomy,
+		Parent:      parent,
+		Name:        name,
+		Slug:        slug,
+		Description: description,
 	}
 }
 
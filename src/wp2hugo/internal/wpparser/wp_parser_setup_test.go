@@ -1,6 +1,7 @@
 package wpparser
 
 import (
+	"strings"
 	"testing"
 
 	ext "github.com/mmcdole/gofeed/extensions"
@@ -26,6 +27,75 @@ func TestGetCommonFields_TrashReturnsIgnoredError(t *testing.T) {
 	require.ErrorIs(t, err, errTrashItem)
 }
 
+func TestGetCommonFields_MalformedItemIsDegraded(t *testing.T) {
+	t.Parallel()
+
+	item := &rss.Item{
+		Title: "test-title",
+		Link:  "https://example.com/test-title",
+		Extensions: map[string]map[string][]ext.Extension{
+			"wp": {
+				"post_id": {{Value: "1"}},
+				"comment": {
+					{Children: map[string][]ext.Extension{
+						"comment_id":      {{Value: "10"}},
+						"comment_content": {{Value: "Nice"}},
+					}},
+					{Children: map[string][]ext.Extension{
+						"comment_id":       {{Value: "11"}},
+						"comment_approved": {{Value: "1"}},
+						"comment_content":  {{Value: "Great"}},
+					}},
+				},
+			},
+		},
+	}
+	fields, err := getCommonFields(item, nil)
+	require.NoError(t, err)
+	require.Equal(t, PublishStatusDraft, fields.PublishStatus)
+	require.Nil(t, fields.PostParentID)
+	require.Empty(t, fields.Excerpt)
+	require.Len(t, fields.Comments, 1)
+	require.Equal(t, "Great", fields.Comments[0].Content)
+
+	fieldNames := make([]string, 0, len(fields.diagnostics))
+	for _, diagnostic := range fields.diagnostics {
+		fieldNames = append(fieldNames, diagnostic.Field)
+	}
+	require.Equal(t, []string{"wp:status", "wp:post_parent", "wp:comment[10].comment_approved", "wp:comment[11].comment_date"}, fieldNames)
+}
+
+func TestParseTolerantSkipsMalformedItems(t *testing.T) {
+	t.Parallel()
+
+	// The first post has no post ID
+	export := strings.Replace(readTestExport(t, "sample.WordPress.xml"), "<wp:post_id>1</wp:post_id>", "", 1)
+
+	_, err := NewParser().Parse(strings.NewReader(export), nil, nil)
+	require.ErrorContains(t, err, `post "First post"`)
+
+	for _, stream := range []bool{false, true} {
+		parser := NewParser()
+		parser.SetTolerant(true)
+		var info *WebsiteInfo
+		if stream {
+			info, err = parser.ParseStream(strings.NewReader(export), nil, nil, StreamHandler{})
+		} else {
+			info, err = parser.Parse(strings.NewReader(export), nil, nil)
+		}
+		require.NoError(t, err)
+		require.Empty(t, info.Posts())
+		require.Len(t, info.Attachments(), 1)
+		require.Equal(t, []Diagnostic{{
+			PostType: "post",
+			Title:    "First post",
+			Field:    "wp:post_id",
+			Reason:   "missing",
+			Skipped:  true,
+		}}, parser.Diagnostics())
+	}
+}
+
 func newRSSItemWithStatus(status string) *rss.Item {
 	return &rss.Item{
 		Title:       "test-title",
@@ -44,3 +114,16 @@ func newRSSItemWithStatus(status string) *rss.Item {
 		},
 	}
 }
+
+func TestIsTaxonomy(t *testing.T) {
+	t.Parallel()
+
+	taxonomies := []TaxonomyInfo{
+		{ID: 7, Taxonomy: "portfolio_skills", Slug: "design", Name: "Web Design"},
+		{ID: 8, Taxonomy: "portfolio_skills", Slug: "logo", Name: "Logo"},
+	}
+	require.Equal(t, &taxonomies[1], isTaxonomy(&rss.Category{Domain: "portfolio_skills", Value: "Logo"}, taxonomies))
+	require.Equal(t, &TaxonomyInfo{Taxonomy: "portfolio_skills", Slug: "print-media", Name: "Print Media"},
+		isTaxonomy(&rss.Category{Domain: "portfolio_skills", Value: "Print Media"}, taxonomies))
+	require.Nil(t, isTaxonomy(&rss.Category{Domain: "product_tag", Value: "Logo"}, taxonomies))
+}
@@ -0,0 +1,42 @@
+package wpparser
+
+import (
+	"fmt"
+
+	"github.com/mmcdole/gofeed/rss"
+)
+
+// ReusableBlock is a Gutenberg reusable block, aka synced pattern, a wp_block item of the export.
+// The content of pages refers to it as <!-- wp:block {"ref":ID} /-->
+type ReusableBlock struct {
+	ID      string
+	Slug    string
+	Title   string
+	Content string
+}
+
+func getReusableBlock(item *rss.Item, taxonomies []TaxonomyInfo) (*ReusableBlock, error) {
+	fields, err := getCommonFields(item, taxonomies)
+	if err != nil {
+		return nil, fmt.Errorf("error getting common fields: %w", err)
+	}
+
+	if fields.PublishStatus != PublishStatusPublish {
+		// WordPress does not render unpublished blocks
+		return nil, nil
+	}
+
+	slug, _ := getWPValue(item, "post_name")
+	if slug == "" {
+		slug = Slugify(fields.Title)
+	}
+	if slug == "" {
+		slug = fields.PostID
+	}
+	return &ReusableBlock{
+		ID:      fields.PostID,
+		Slug:    slug,
+		Title:   fields.Title,
+		Content: fields.Content,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReusableBlocks(t *testing.T) {
+	t.Parallel()
+	expected := []ReusableBlock{
+		{
+			ID:    "200",
+			Slug:  "newsletter",
+			Title: "Newsletter signup",
+			Content: `<p>Subscribe to <a href="https://example.net/newsletter/">the newsletter</a></p>
+<!-- wp:block {"ref":201} /-->`,
+		},
+		{
+			ID:      "201",
+			Slug:    "disclaimer",
+			Title:   "Disclaimer",
+			Content: `<p><em>No spam</em></p><!-- wp:block {"ref":201} /-->`,
+		},
+	}
+	export := readTestExport(t, "reusable_blocks.WordPress.xml")
+
+	info, err := NewParser().Parse(strings.NewReader(export), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, info.ReusableBlocks())
+	require.Equal(t, &expected[0], info.GetReusableBlock("200"))
+	// Drafts are ignored
+	require.Nil(t, info.GetReusableBlock("202"))
+
+	info, err = NewParser().ParseStream(strings.NewReader(export), nil, nil, StreamHandler{})
+	require.NoError(t, err)
+	require.Equal(t, expected, info.ReusableBlocks())
+}
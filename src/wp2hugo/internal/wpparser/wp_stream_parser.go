@@ -0,0 +1,326 @@
+package wpparser
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/html/charset"
+)
+
+// Prefixes gofeed uses for these namespaces, irrespective of what the export declares
+var _canonicalNamespacePrefixes = map[string]string{
+	"http://purl.org/rss/1.0/modules/content/": "content",
+	"http://purl.org/dc/elements/1.1/":         "dc",
+}
+
+// ParseStream parses the XML data token by token with encoding/xml instead of building
+// the whole feed in memory first. Every item is handed to the matching callback of
+// handler as soon as it has been parsed, so memory stays flat regardless of the export size.
+// Items without a callback are retained in the returned WebsiteInfo.
+//
+// Note: WordPress writes categories, tags and terms before the first item,
+// items only get the taxonomies that have been seen before them.
+func (p *Parser) ParseStream(xmlData io.Reader, authors []string, customPostTypes []string,
+	handler StreamHandler,
+) (*WebsiteInfo, error) {
+	decoder := xml.NewDecoder(InvalidatorCharacterRemover{reader: xmlData})
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	s := &streamParser{
+		decoder:  decoder,
+		prefixes: make(map[string]string),
+		tolerant: p.tolerant,
+	}
+	info, err := s.parse(getNonEmptyAuthors(authors), customPostTypes, handler)
+	p.diagnostics = append(p.diagnostics, s.diagnostics...)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msgf("error parsing XML")
+		return nil, fmt.Errorf("error parsing XML: %w", err)
+	}
+	return info, nil
+}
+
+type streamParser struct {
+	decoder *xml.Decoder
+	// Namespace URI -> prefix as declared on the <rss> element
+	prefixes map[string]string
+
+	tolerant    bool
+	diagnostics []Diagnostic
+}
+
+func (s *streamParser) parse(authors []string, customPostTypes []string, handler StreamHandler) (*WebsiteInfo, error) {
+	var (
+		info       WebsiteInfo
+		feedLink   string
+		wpElements = make(map[string][]ext.Extension)
+		collector  *itemCollector
+	)
+
+	for {
+		token, err := s.decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case start.Name.Local == "rss":
+			s.registerPrefixes(start)
+		case start.Name.Local == "channel":
+			// Descend into the channel
+		case start.Name.Local == "item":
+			if collector == nil {
+				info.authors = getAuthors(wpElements["author"])
+				info.categories = getCategories(wpElements["category"])
+				info.tags = getTags(wpElements["tag"])
+				info.taxonomies = getTaxonomies(wpElements["term"])
+				collector = newItemCollector(authors, customPostTypes, info.taxonomies, handler, s.tolerant)
+			}
+			item, err := s.readItem(start)
+			if err != nil {
+				return nil, fmt.Errorf("error reading item: %w", err)
+			}
+			err = collector.addItem(item)
+			s.diagnostics = collector.diagnostics
+			if err != nil {
+				return nil, err
+			}
+		case s.prefix(start.Name) == "wp":
+			element, err := s.readElement(start)
+			if err != nil {
+				return nil, err
+			}
+			wpElements[element.Name] = append(wpElements[element.Name], element)
+		case start.Name.Space != "":
+			if err := s.decoder.Skip(); err != nil {
+				return nil, err
+			}
+		default:
+			if err := s.readChannelField(start, &info, &feedLink); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if collector == nil {
+		// An export without a single item
+		info.authors = getAuthors(wpElements["author"])
+		info.categories = getCategories(wpElements["category"])
+		info.tags = getTags(wpElements["tag"])
+		info.taxonomies = getTaxonomies(wpElements["term"])
+		collector = newItemCollector(authors, customPostTypes, info.taxonomies, handler, s.tolerant)
+	}
+
+	if info.pubDate == nil {
+		log.Warn().Msgf("error parsing published date: %s", info.pubDate)
+	}
+	linkURL, err := url.Parse(feedLink)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing feed link: %w", err)
+	}
+	info.link = linkURL
+	return collector.websiteInfo(info), nil
+}
+
+func (s *streamParser) registerPrefixes(start xml.StartElement) {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" {
+			s.prefixes[strings.TrimSpace(attr.Value)] = attr.Name.Local
+		}
+	}
+}
+
+// prefix maps the namespace URI of name back to the prefix gofeed would have used
+func (s *streamParser) prefix(name xml.Name) string {
+	space := strings.TrimSpace(name.Space)
+	if prefix, ok := _canonicalNamespacePrefixes[space]; ok {
+		return prefix
+	}
+	if prefix, ok := s.prefixes[space]; ok {
+		return prefix
+	}
+	return space
+}
+
+func (s *streamParser) readChannelField(start xml.StartElement, info *WebsiteInfo, feedLink *string) error {
+	switch strings.ToLower(start.Name.Local) {
+	case "title":
+		return s.readText(start, &info.title)
+	case "description":
+		return s.readText(start, &info.Description)
+	case "language":
+		return s.readText(start, &info.language)
+	case "link":
+		return s.readText(start, feedLink)
+	case "pubdate":
+		var pubDate string
+		if err := s.readText(start, &pubDate); err != nil {
+			return err
+		}
+		info.pubDate = parseRSSDate(pubDate)
+		return nil
+	default:
+		return s.decoder.Skip()
+	}
+}
+
+// readItem converts an <item> into the same rss.Item gofeed would have produced,
+// so that the rest of the parser is shared between Parse and ParseStream
+func (s *streamParser) readItem(start xml.StartElement) (*rss.Item, error) {
+	item := &rss.Item{
+		Extensions: make(ext.Extensions),
+	}
+	for {
+		token, err := s.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.EndElement:
+			if t.Name.Local == start.Name.Local {
+				return item, nil
+			}
+		case xml.StartElement:
+			if err := s.readItemField(t, item); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (s *streamParser) readItemField(start xml.StartElement, item *rss.Item) error {
+	prefix := s.prefix(start.Name)
+	name := strings.ToLower(start.Name.Local)
+	switch {
+	case prefix == "content" && name == "encoded":
+		return s.readText(start, &item.Content)
+	case prefix != "":
+		element, err := s.readElement(start)
+		if err != nil {
+			return err
+		}
+		if item.Extensions[prefix] == nil {
+			item.Extensions[prefix] = make(map[string][]ext.Extension)
+		}
+		item.Extensions[prefix][element.Name] = append(item.Extensions[prefix][element.Name], element)
+		return nil
+	}
+
+	switch name {
+	case "title":
+		return s.readText(start, &item.Title)
+	case "description":
+		return s.readText(start, &item.Description)
+	case "author":
+		return s.readText(start, &item.Author)
+	case "link":
+		if err := s.readText(start, &item.Link); err != nil {
+			return err
+		}
+		item.Links = append(item.Links, item.Link)
+		return nil
+	case "pubdate":
+		if err := s.readText(start, &item.PubDate); err != nil {
+			return err
+		}
+		item.PubDateParsed = parseRSSDate(item.PubDate)
+		return nil
+	case "guid":
+		guid := rss.GUID{IsPermalink: "true"}
+		for _, attr := range start.Attr {
+			if strings.EqualFold(attr.Name.Local, "isPermaLink") {
+				guid.IsPermalink = attr.Value
+			}
+		}
+		if err := s.readText(start, &guid.Value); err != nil {
+			return err
+		}
+		item.GUID = &guid
+		return nil
+	case "category":
+		category := rss.Category{}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "domain" {
+				category.Domain = attr.Value
+			}
+		}
+		if err := s.readText(start, &category.Value); err != nil {
+			return err
+		}
+		item.Categories = append(item.Categories, &category)
+		return nil
+	default:
+		return s.decoder.Skip()
+	}
+}
+
+// readElement reads the element started by start, and all its children, into an ext.Extension
+func (s *streamParser) readElement(start xml.StartElement) (ext.Extension, error) {
+	element := ext.Extension{
+		Name:     start.Name.Local,
+		Attrs:    make(map[string]string, len(start.Attr)),
+		Children: make(map[string][]ext.Extension),
+	}
+	for _, attr := range start.Attr {
+		element.Attrs[attr.Name.Local] = attr.Value
+	}
+
+	var value strings.Builder
+	for {
+		token, err := s.decoder.Token()
+		if err != nil {
+			return element, err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			value.Write(t)
+		case xml.StartElement:
+			child, err := s.readElement(t)
+			if err != nil {
+				return element, err
+			}
+			element.Children[child.Name] = append(element.Children[child.Name], child)
+		case xml.EndElement:
+			element.Value = strings.TrimSpace(value.String())
+			return element, nil
+		}
+	}
+}
+
+func (s *streamParser) readText(start xml.StartElement, target *string) error {
+	var text string
+	if err := s.decoder.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	*target = strings.TrimSpace(text)
+	return nil
+}
+
+func parseRSSDate(value string) *time.Time {
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123} {
+		if t, err := time.Parse(layout, value); err == nil {
+			utc := t.UTC()
+			return &utc
+		}
+	}
+	return nil
+}
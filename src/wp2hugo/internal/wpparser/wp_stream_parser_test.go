@@ -0,0 +1,117 @@
+package wpparser
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readTestExport returns the WordPress export fileName, shared with the tests of the generator
+func readTestExport(t *testing.T, fileName string) string {
+	t.Helper()
+	export, err := os.ReadFile(path.Join("../hugogenerator/testdata", fileName))
+	require.NoError(t, err)
+	return string(export)
+}
+
+func TestParseStreamMatchesParse(t *testing.T) {
+	t.Parallel()
+	expected, err := NewParser().Parse(strings.NewReader(readTestExport(t, "sample.WordPress.xml")), nil, nil)
+	require.NoError(t, err)
+
+	actual, err := NewParser().ParseStream(strings.NewReader(readTestExport(t, "sample.WordPress.xml")), nil, nil, StreamHandler{})
+	require.NoError(t, err)
+
+	require.Equal(t, expected.Title(), actual.Title())
+	require.Equal(t, expected.Link(), actual.Link())
+	require.Equal(t, expected.Description, actual.Description)
+	require.Equal(t, expected.Language(), actual.Language())
+	require.Equal(t, expected.authors, actual.authors)
+	require.Equal(t, expected.categories, actual.categories)
+	require.Equal(t, expected.tags, actual.tags)
+	require.Equal(t, expected.Posts(), actual.Posts())
+	require.Equal(t, expected.Attachments(), actual.Attachments())
+	require.Len(t, actual.GetAttachmentsForPost("1"), 1)
+}
+
+func TestParseStreamHandsOffItems(t *testing.T) {
+	t.Parallel()
+	var posts []PostInfo
+	var attachments []AttachmentInfo
+	info, err := NewParser().ParseStream(strings.NewReader(readTestExport(t, "sample.WordPress.xml")), nil, nil, StreamHandler{
+		OnPost: func(post PostInfo) error {
+			posts = append(posts, post)
+			return nil
+		},
+		OnAttachment: func(attachment AttachmentInfo) error {
+			attachments = append(attachments, attachment)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	// Handed-off items are not retained
+	require.Empty(t, info.Posts())
+	require.Empty(t, info.Attachments())
+
+	require.Len(t, posts, 1)
+	require.Equal(t, "First post", posts[0].Title)
+	require.Equal(t, "<p>Hello &amp; welcome</p>", posts[0].Content)
+	require.Equal(t, []string{"travel"}, posts[0].Categories)
+	require.Equal(t, []string{"italy"}, posts[0].Tags)
+	require.Equal(t, "jdoe", posts[0].Author)
+	require.Equal(t, []string{"jdoe", "jane.roe@example.net"}, posts[0].Authors)
+	require.NotNil(t, posts[0].FeaturedImageID)
+	require.Equal(t, "2", *posts[0].FeaturedImageID)
+
+	require.Len(t, attachments, 1)
+	require.Equal(t, "https://example.net/wp-content/uploads/2024/07/photo.jpg", *attachments[0].GetAttachmentURL())
+}
+
+func TestParseAuthors(t *testing.T) {
+	t.Parallel()
+	info, err := NewParser().Parse(strings.NewReader(readTestExport(t, "sample.WordPress.xml")), nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, info.Authors(), 2)
+	author := info.GetAuthor("jdoe")
+	require.NotNil(t, author)
+	require.Equal(t, AuthorInfo{
+		ID:          "1",
+		Login:       "jdoe",
+		Email:       "jdoe@example.net",
+		DisplayName: "John Doe",
+		FirstName:   "John",
+		LastName:    "Doe",
+	}, *author)
+	require.Equal(t, "jdoe", author.Slug())
+
+	coAuthor := info.GetAuthor("jane.roe@example.net")
+	require.NotNil(t, coAuthor)
+	require.Equal(t, "Jane Roe", coAuthor.Name())
+	require.Equal(t, "jane-roeexample-net", coAuthor.Slug())
+	require.Nil(t, info.GetAuthor("unknown"))
+}
+
+func TestParseCategoryHierarchy(t *testing.T) {
+	t.Parallel()
+	info, err := NewParser().ParseStream(strings.NewReader(readTestExport(t, "terms.WordPress.xml")), nil, nil, StreamHandler{})
+	require.NoError(t, err)
+
+	require.Len(t, info.Categories(), 3)
+	require.Equal(t, CategoryInfo{
+		ID:          "3",
+		Name:        "travel",
+		Title:       "Travel",
+		NiceName:    "travel",
+		Description: "Trips & holidays",
+	}, info.Categories()[0])
+	require.Equal(t, "travel", info.Categories()[1].Parent)
+
+	require.Equal(t, []string{"europe", "travel"}, info.GetCategoryAncestors("south-italy"))
+	require.Empty(t, info.GetCategoryAncestors("travel"))
+	require.Empty(t, info.GetCategoryAncestors("unknown"))
+}
@@ -2,6 +2,7 @@ package wpparser
 
 import (
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -13,6 +14,7 @@ type WebsiteInfo struct {
 	pubDate  *time.Time
 	language string
 
+	authors    []AuthorInfo
 	categories []CategoryInfo
 	tags       []TagInfo
 
@@ -22,8 +24,15 @@ type WebsiteInfo struct {
 	pages           []PageInfo
 	posts           []PostInfo
 	navigationLinks []NavigationLink
-	customPosts     []CustomPostInfo
-	taxonomies      []TaxonomyInfo
+	navMenuItems    []NavMenuItem
+	// Posts handed over to a StreamHandler, which are not retained
+	streamedPosts  []PostLink
+	reusableBlocks []ReusableBlock
+	// Flat, see ACFFieldGroups
+	acfFieldGroups []ACFFieldGroup
+	acfFields      []ACFField
+	customPosts    []CustomPostInfo
+	taxonomies     []TaxonomyInfo
 
 	// WordPress non-native post types slugs to import.
 	// By default, we handle avada_portfolio, avada_faq (Advada theme),
@@ -41,24 +50,58 @@ type NavigationLink struct {
 	Type  string
 }
 
+// AuthorInfo is a WordPress user, as exported in <wp:author>
+type AuthorInfo struct {
+	ID          string
+	Login       string
+	Email       string
+	DisplayName string
+	FirstName   string
+	LastName    string
+}
+
+// Name returns the name WordPress displays for the author
+func (a AuthorInfo) Name() string {
+	if a.DisplayName != "" {
+		return a.DisplayName
+	}
+	if name := strings.TrimSpace(a.FirstName + " " + a.LastName); name != "" {
+		return name
+	}
+	return a.Login
+}
+
+// Slug returns the slug of the author, used by WordPress in the URL of the author archive, e.g. /author/jdoe/
+func (a AuthorInfo) Slug() string {
+	return Slugify(a.Login)
+}
+
 type CategoryInfo struct {
-	ID       string
-	Name     string
-	NiceName string
+	ID string
+	// Normalized with NormalizeCategoryName, as in the front matter of the pages
+	Name        string
+	Title       string
+	NiceName    string
+	Parent      string // NiceName of the parent category, if any
+	Description string
 }
 
 type TagInfo struct {
-	ID   string
-	Name string
-	Slug string
+	ID string
+	// Normalized with NormalizeCategoryName, as in the front matter of the pages
+	Name        string
+	Title       string
+	Slug        string
+	Description string
 }
 
 type TaxonomyInfo struct {
-	ID       int
-	Taxonomy string
-	Slug     string
-	Parent   string
-	Name     string
+	ID          int
+	Taxonomy    string
+	Slug        string
+	Parent      string // Slug of the parent term, if any
+	Name        string
+	Description string
 }
 
 func (w *WebsiteInfo) Title() string {
@@ -81,6 +124,36 @@ func (w *WebsiteInfo) NavigationLinks() []NavigationLink {
 	return w.navigationLinks
 }
 
+// NavMenuItems returns the entries of all the classic menus
+func (w *WebsiteInfo) NavMenuItems() []NavMenuItem {
+	return w.navMenuItems
+}
+
+// StreamedPosts returns the title and link of the posts and custom posts handed over to a StreamHandler
+func (w *WebsiteInfo) StreamedPosts() []PostLink {
+	return w.streamedPosts
+}
+
+// ReusableBlocks returns the Gutenberg reusable blocks, aka synced patterns
+func (w *WebsiteInfo) ReusableBlocks() []ReusableBlock {
+	return w.reusableBlocks
+}
+
+// ACFFieldGroups returns the field groups of Advanced Custom Fields, with their fields
+func (w *WebsiteInfo) ACFFieldGroups() []ACFFieldGroup {
+	return nestACFFields(w.acfFieldGroups, w.acfFields)
+}
+
+// GetReusableBlock returns the reusable block of post ID id, nil if there is none
+func (w *WebsiteInfo) GetReusableBlock(id string) *ReusableBlock {
+	for i := range w.reusableBlocks {
+		if w.reusableBlocks[i].ID == id {
+			return &w.reusableBlocks[i]
+		}
+	}
+	return nil
+}
+
 func (w *WebsiteInfo) GetAttachmentsForPost(postID string) []AttachmentInfo {
 	return w.postIDToAttachmentCache[postID]
 }
@@ -101,6 +174,56 @@ func (w *WebsiteInfo) CustomPostTypes() []string {
 	return w.customPostTypes
 }
 
+func (w *WebsiteInfo) Authors() []AuthorInfo {
+	return w.authors
+}
+
+// GetAuthor returns the author whose login is login, if the export declares it
+func (w *WebsiteInfo) GetAuthor(login string) *AuthorInfo {
+	for i := range w.authors {
+		if w.authors[i].Login == login {
+			return &w.authors[i]
+		}
+	}
+	return nil
+}
+
+func (w *WebsiteInfo) Categories() []CategoryInfo {
+	return w.categories
+}
+
+func (w *WebsiteInfo) Tags() []TagInfo {
+	return w.tags
+}
+
+// GetCategoryAncestors returns the names of the ancestors of the category named name, its parent first
+func (w *WebsiteInfo) GetCategoryAncestors(name string) []string {
+	byNiceName := make(map[string]CategoryInfo, len(w.categories))
+	var category *CategoryInfo
+	for i, c := range w.categories {
+		byNiceName[c.NiceName] = c
+		if c.Name == name {
+			category = &w.categories[i]
+		}
+	}
+	if category == nil {
+		return nil
+	}
+
+	var ancestors []string
+	seen := map[string]bool{category.NiceName: true}
+	for parent, ok := byNiceName[category.Parent]; ok && !seen[parent.NiceName]; parent, ok = byNiceName[parent.Parent] {
+		seen[parent.NiceName] = true
+		ancestors = append(ancestors, parent.Name)
+	}
+	return ancestors
+}
+
+// Taxonomies returns the terms of the taxonomies other than categories and tags
+func (w *WebsiteInfo) Taxonomies() []TaxonomyInfo {
+	return w.taxonomies
+}
+
 func getPostIDToAttachmentsMap(attachments []AttachmentInfo) map[string][]AttachmentInfo {
 	result := make(map[string][]AttachmentInfo)
 	for _, attachment := range attachments {
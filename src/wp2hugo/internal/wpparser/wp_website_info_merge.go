@@ -0,0 +1,144 @@
+package wpparser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrConflictingDuplicate is returned when two export files contain the same post ID with different data
+var ErrConflictingDuplicate = errors.New("conflicting duplicate item")
+
+// MergeWebsiteInfos merges the WebsiteInfo of several export files of the same website into one.
+// WP-CLI splits large exports into several files (site.000.xml, site.001.xml, ...),
+// site-level information is taken from the first file while categories, tags, taxonomies and
+// items are de-duplicated by ID across all of them.
+// Identical duplicates are dropped, duplicates that differ return ErrConflictingDuplicate.
+func MergeWebsiteInfos(infos []*WebsiteInfo) (*WebsiteInfo, error) {
+	if len(infos) == 0 {
+		return nil, errors.New("no website info to merge")
+	}
+	if len(infos) == 1 {
+		return infos[0], nil
+	}
+
+	merged := *infos[0]
+	merged.authors = nil
+	merged.categories = nil
+	merged.tags = nil
+	merged.taxonomies = nil
+	merged.attachments = nil
+	merged.pages = nil
+	merged.posts = nil
+	merged.customPosts = nil
+	merged.customPostTypes = nil
+	merged.navMenuItems = nil
+	merged.streamedPosts = nil
+	merged.reusableBlocks = nil
+	merged.acfFieldGroups = nil
+	merged.acfFields = nil
+
+	var err error
+	for i, info := range infos {
+		if info.link.String() != merged.link.String() {
+			log.Warn().
+				Int("file", i).
+				Str("link", info.link.String()).
+				Str("expectedLink", merged.link.String()).
+				Msg("Merging exports of different websites")
+		}
+		if len(merged.navigationLinks) == 0 {
+			merged.navigationLinks = info.navigationLinks
+		}
+		for _, postType := range info.customPostTypes {
+			if !slices.Contains(merged.customPostTypes, postType) {
+				merged.customPostTypes = append(merged.customPostTypes, postType)
+			}
+		}
+
+		merged.navMenuItems = appendUniqueTerms(merged.navMenuItems, info.navMenuItems, func(m NavMenuItem) string { return m.ID })
+		merged.streamedPosts = appendUniqueTerms(merged.streamedPosts, info.streamedPosts, func(p PostLink) string { return p.PostID })
+		merged.reusableBlocks = appendUniqueTerms(merged.reusableBlocks, info.reusableBlocks, func(b ReusableBlock) string { return b.ID })
+		merged.acfFieldGroups = appendUniqueTerms(merged.acfFieldGroups, info.acfFieldGroups, func(g ACFFieldGroup) string { return g.ID })
+		merged.acfFields = appendUniqueTerms(merged.acfFields, info.acfFields, func(f ACFField) string { return f.ID })
+		merged.authors = appendUniqueTerms(merged.authors, info.authors, func(a AuthorInfo) string { return a.Login })
+		merged.categories = appendUniqueTerms(merged.categories, info.categories, func(c CategoryInfo) string { return c.ID })
+		merged.tags = appendUniqueTerms(merged.tags, info.tags, func(t TagInfo) string { return t.ID })
+		merged.taxonomies = appendUniqueTerms(merged.taxonomies, info.taxonomies, func(t TaxonomyInfo) string {
+			return fmt.Sprintf("%s/%d", t.Taxonomy, t.ID)
+		})
+
+		if merged.attachments, err = appendUniqueItems(merged.attachments, info.attachments, getAttachmentFields); err != nil {
+			return nil, err
+		}
+		if merged.pages, err = appendUniqueItems(merged.pages, info.pages, getPageFields); err != nil {
+			return nil, err
+		}
+		if merged.posts, err = appendUniqueItems(merged.posts, info.posts, getPostFields); err != nil {
+			return nil, err
+		}
+		if merged.customPosts, err = appendUniqueItems(merged.customPosts, info.customPosts, getCustomPostFields); err != nil {
+			return nil, err
+		}
+	}
+	merged.postIDToAttachmentCache = getPostIDToAttachmentsMap(merged.attachments)
+
+	log.Info().
+		Int("numFiles", len(infos)).
+		Int("numAttachments", len(merged.attachments)).
+		Int("numPages", len(merged.pages)).
+		Int("numPosts", len(merged.posts)).
+		Int("numCustomPosts", len(merged.customPosts)).
+		Msgf("Merged WebsiteInfo: %s", merged.title)
+	return &merged, nil
+}
+
+func getAttachmentFields(a AttachmentInfo) CommonFields { return a.CommonFields }
+
+func getPageFields(p PageInfo) CommonFields { return p.CommonFields }
+
+func getPostFields(p PostInfo) CommonFields { return p.CommonFields }
+
+func getCustomPostFields(c CustomPostInfo) CommonFields { return c.CommonFields }
+
+func appendUniqueTerms[T any](existing []T, additions []T, getID func(T) string) []T {
+	seen := make(map[string]bool, len(existing))
+	for _, term := range existing {
+		seen[getID(term)] = true
+	}
+	for _, addition := range additions {
+		if !seen[getID(addition)] {
+			seen[getID(addition)] = true
+			existing = append(existing, addition)
+		}
+	}
+	return existing
+}
+
+func appendUniqueItems[T any](existing []T, additions []T, getFields func(T) CommonFields) ([]T, error) {
+	indexByPostID := make(map[string]int, len(existing))
+	for i, item := range existing {
+		indexByPostID[getFields(item).PostID] = i
+	}
+
+	for _, addition := range additions {
+		postID := getFields(addition).PostID
+		i, ok := indexByPostID[postID]
+		if !ok {
+			indexByPostID[postID] = len(existing)
+			existing = append(existing, addition)
+			continue
+		}
+		if !reflect.DeepEqual(existing[i], addition) {
+			return nil, fmt.Errorf("%w: post ID %s (%q and %q)", ErrConflictingDuplicate,
+				postID, getFields(existing[i]).Title, getFields(addition).Title)
+		}
+		log.Debug().
+			Str("postID", postID).
+			Msg("Dropping duplicate item")
+	}
+	return existing, nil
+}
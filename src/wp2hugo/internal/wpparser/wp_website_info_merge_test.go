@@ -0,0 +1,41 @@
+package wpparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeWebsiteInfos(t *testing.T) {
+	t.Parallel()
+	// The post from the first file is repeated verbatim in the second one
+	first, err := NewParser().Parse(strings.NewReader(readTestExport(t, "sample.WordPress.xml")), nil, nil)
+	require.NoError(t, err)
+	second, err := NewParser().Parse(strings.NewReader(readTestExport(t, "sample.WordPress.xml")), nil, nil)
+	require.NoError(t, err)
+	extra := *second
+	extra.posts = append(extra.posts, PostInfo{CommonFields{PostID: "42", Title: "Second file post"}})
+	extra.attachments = nil
+
+	merged, err := MergeWebsiteInfos([]*WebsiteInfo{first, &extra})
+	require.NoError(t, err)
+	require.Len(t, merged.Posts(), 2)
+	require.Len(t, merged.Attachments(), 1)
+	require.Len(t, merged.categories, 1)
+	require.Len(t, merged.tags, 1)
+	// Attachment of the first file is found for the post
+	require.Len(t, merged.GetAttachmentsForPost("1"), 1)
+}
+
+func TestMergeWebsiteInfosConflictingDuplicate(t *testing.T) {
+	t.Parallel()
+	first, err := NewParser().Parse(strings.NewReader(readTestExport(t, "sample.WordPress.xml")), nil, nil)
+	require.NoError(t, err)
+	second, err := NewParser().Parse(strings.NewReader(readTestExport(t, "sample.WordPress.xml")), nil, nil)
+	require.NoError(t, err)
+	second.posts[0].Title = "Edited in between"
+
+	_, err = MergeWebsiteInfos([]*WebsiteInfo{first, second})
+	require.ErrorIs(t, err, ErrConflictingDuplicate)
+}